@@ -13,6 +13,8 @@ import (
 	"service/app/sdk/auth"
 	"service/app/sdk/debug"
 	"service/app/sdk/mux"
+	"service/business/domain/rolebus"
+	"service/business/domain/rolebus/stores/roledb"
 	"service/business/domain/userbus"
 	"service/business/domain/userbus/stores/userdb"
 	"service/business/sdk/delegate"
@@ -66,19 +68,27 @@ func run(ctx context.Context, log *logger.Logger) error {
 			DebugHost       string        `conf:"default:0.0.0.0:6010"`
 		}
 		DB struct {
-			User         string `conf:"default:postgres"`
-			Password     string `conf:"default:postgres,mask"`
-			Host         string `conf:"default:database-service"`
-			Name         string `conf:"default:postgres"`
-			MaxIdleConns int    `conf:"default:0"`
-			MaxOpenConns int    `conf:"default:0"`
-			DisableTLS   bool   `conf:"default:true"`
+			User             string        `conf:"default:postgres"`
+			Password         string        `conf:"default:postgres,mask"`
+			Host             string        `conf:"default:database-service"`
+			Name             string        `conf:"default:postgres"`
+			MaxIdleConns     int           `conf:"default:10"`
+			MaxOpenConns     int           `conf:"default:25"`
+			ConnMaxLifetime  time.Duration `conf:"default:30m"`
+			ConnMaxIdleTime  time.Duration `conf:"default:5m"`
+			StatementTimeout time.Duration `conf:"default:30s"`
+			DisableTLS       bool          `conf:"default:true"`
+			TLSRootCert      string
+			TLSCert          string
+			TLSKey           string
+			ConnectTimeout   time.Duration `conf:"default:30s"`
 		}
 		Auth struct {
 			KeysEnvVar string
-			KeysFolder string `conf:"default:zarf/keys/"`
-			ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
-			Issuer     string `conf:"default:service project"`
+			KeysFolder string        `conf:"default:zarf/keys/"`
+			ActiveKID  string        `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
+			Issuer     string        `conf:"default:service project"`
+			RoleTTL    time.Duration `conf:"default:30s"`
 		}
 		Tempo struct {
 			Host        string  `conf:"default:tempo:4317"`
@@ -123,13 +133,21 @@ func run(ctx context.Context, log *logger.Logger) error {
 	log.Info(ctx, "startup", "status", "initializing database support", "hostport", cfg.DB.Host)
 
 	db, err := sqldb.Open(sqldb.Config{
-		User:         cfg.DB.User,
-		Password:     cfg.DB.Password,
-		Host:         cfg.DB.Host,
-		Name:         cfg.DB.Name,
-		MaxIdleConns: cfg.DB.MaxIdleConns,
-		MaxOpenConns: cfg.DB.MaxOpenConns,
-		DisableTLS:   cfg.DB.DisableTLS,
+		User:             cfg.DB.User,
+		Password:         cfg.DB.Password,
+		Host:             cfg.DB.Host,
+		Name:             cfg.DB.Name,
+		MaxIdleConns:     cfg.DB.MaxIdleConns,
+		MaxOpenConns:     cfg.DB.MaxOpenConns,
+		ConnMaxLifetime:  cfg.DB.ConnMaxLifetime,
+		ConnMaxIdleTime:  cfg.DB.ConnMaxIdleTime,
+		StatementTimeout: cfg.DB.StatementTimeout,
+		ApplicationName:  "auth",
+		DisableTLS:       cfg.DB.DisableTLS,
+		TLSRootCert:      cfg.DB.TLSRootCert,
+		TLSCert:          cfg.DB.TLSCert,
+		TLSKey:           cfg.DB.TLSKey,
+		ConnectTimeout:   cfg.DB.ConnectTimeout,
 	})
 	if err != nil {
 		return fmt.Errorf("connecting to db: %w", err)
@@ -137,11 +155,14 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	defer db.Close()
 
+	sqldb.PublishStats("primary", db)
+
 	// -------------------------------------------------------------------------
 	// Create Business Packages
 
 	delegate := delegate.New(log)
 	userBus := userbus.NewBusiness(log, delegate, userdb.NewStore(log, db))
+	roleBus := rolebus.NewBusiness(log, roledb.NewStore(log, db))
 
 	// -------------------------------------------------------------------------
 	// Initialize authentication support
@@ -170,6 +191,8 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	authCfg := auth.Config{
 		Log:       log,
+		RoleBus:   roleBus,
+		RoleTTL:   cfg.Auth.RoleTTL,
 		KeyLookup: ks,
 		Issuer:    cfg.Auth.Issuer,
 	}
@@ -2,6 +2,11 @@ package all
 
 import (
 	"service/app/domain/checkapp"
+	"service/app/domain/gdprapp"
+	"service/app/domain/inviteapp"
+	"service/app/domain/orderapp"
+	"service/app/domain/productapp"
+	"service/app/domain/roleapp"
 	"service/app/domain/userapp"
 	"service/app/sdk/mux"
 	"service/foundation/web"
@@ -25,7 +30,47 @@ func (add) Add(app *web.App, cfg mux.Config) {
 
 	userapp.Routes(app, userapp.Config{
 		Log:        cfg.Log,
+		DB:         cfg.DB,
 		UserBus:    cfg.BusConfig.UserBus,
+		RoleBus:    cfg.BusConfig.RoleBus,
+		AuthClient: cfg.SalesConfig.AuthClient,
+		Beginner:   cfg.SalesConfig.Beginner,
+		NameRules:  cfg.SalesConfig.NameRules,
+	})
+
+	inviteapp.Routes(app, inviteapp.Config{
+		Log:        cfg.Log,
+		DB:         cfg.DB,
+		InviteBus:  cfg.BusConfig.InviteBus,
+		RoleBus:    cfg.BusConfig.RoleBus,
+		AuthClient: cfg.SalesConfig.AuthClient,
+		Beginner:   cfg.SalesConfig.Beginner,
+		NameRules:  cfg.SalesConfig.NameRules,
+	})
+
+	productapp.Routes(app, productapp.Config{
+		Log:        cfg.Log,
+		ProductBus: cfg.BusConfig.ProductBus,
+		AuthClient: cfg.SalesConfig.AuthClient,
+		NameRules:  cfg.SalesConfig.NameRules,
+	})
+
+	orderapp.Routes(app, orderapp.Config{
+		Log:        cfg.Log,
+		OrderBus:   cfg.BusConfig.OrderBus,
+		AuthClient: cfg.SalesConfig.AuthClient,
+		Beginner:   cfg.SalesConfig.Beginner,
+	})
+
+	roleapp.Routes(app, roleapp.Config{
+		Log:        cfg.Log,
+		RoleBus:    cfg.BusConfig.RoleBus,
+		AuthClient: cfg.SalesConfig.AuthClient,
+	})
+
+	gdprapp.Routes(app, gdprapp.Config{
+		Log:        cfg.Log,
+		GDPRBus:    cfg.BusConfig.GDPRBus,
 		AuthClient: cfg.SalesConfig.AuthClient,
 	})
 }
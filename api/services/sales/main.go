@@ -13,13 +13,39 @@ import (
 	"service/app/sdk/authclient"
 	"service/app/sdk/debug"
 	"service/app/sdk/mux"
+	"service/app/sdk/periodic"
+	"service/business/domain/auditbus"
+	"service/business/domain/auditbus/stores/auditdb"
+	"service/business/domain/gdprbus"
+	"service/business/domain/gdprbus/stores/gdprdb"
+	"service/business/domain/invitebus"
+	"service/business/domain/invitebus/extension/inviteaudit"
+	"service/business/domain/invitebus/extension/inviteotel"
+	"service/business/domain/invitebus/senders/invitelog"
+	"service/business/domain/invitebus/stores/invitedb"
+	"service/business/domain/orderbus"
+	"service/business/domain/orderbus/extension/orderaudit"
+	"service/business/domain/orderbus/extension/orderotel"
+	"service/business/domain/orderbus/stores/orderdb"
+	"service/business/domain/orgbus"
+	"service/business/domain/orgbus/stores/orgdb"
+	"service/business/domain/productbus"
+	"service/business/domain/productbus/extension/productotel"
+	"service/business/domain/productbus/stores/productcache"
+	"service/business/domain/productbus/stores/productdb"
+	"service/business/domain/rolebus"
+	"service/business/domain/rolebus/stores/roledb"
 	"service/business/domain/userbus"
 	"service/business/domain/userbus/stores/usercache"
 	"service/business/domain/userbus/stores/userdb"
 	"service/business/sdk/delegate"
+	"service/business/sdk/gdpr"
 	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/name"
 	"service/foundation/logger"
 	"service/foundation/otel"
+	"sync"
 	"syscall"
 	"time"
 
@@ -65,14 +91,50 @@ func run(ctx context.Context, log *logger.Logger) error {
 		Auth struct {
 			Host string `conf:"default:http://auth-service:6000"`
 		}
+		Invite struct {
+			URL string        `conf:"default:http://localhost:3000/invitations/accept"`
+			TTL time.Duration `conf:"default:72h"`
+		}
+		Name struct {
+			MinLength int `conf:"default:2"`
+			MaxLength int `conf:"default:100"`
+		}
+		Purge struct {
+			Interval  time.Duration `conf:"default:1h"`
+			Retention time.Duration `conf:"default:720h"`
+		}
+		GDPR struct {
+			Interval time.Duration `conf:"default:10s"`
+		}
+		Leader struct {
+			RetryInterval time.Duration `conf:"default:10s"`
+			CheckInterval time.Duration `conf:"default:5s"`
+		}
 		DB struct {
-			User         string `conf:"default:postgres"`
-			Password     string `conf:"default:postgres,mask"`
-			Host         string `conf:"default:database-service"`
-			Name         string `conf:"default:postgres"`
-			MaxIdleConns int    `conf:"default:0"`
-			MaxOpenConns int    `conf:"default:0"`
-			DisableTLS   bool   `conf:"default:true"`
+			User             string        `conf:"default:postgres"`
+			Password         string        `conf:"default:postgres,mask"`
+			Host             string        `conf:"default:database-service"`
+			Name             string        `conf:"default:postgres"`
+			MaxIdleConns     int           `conf:"default:10"`
+			MaxOpenConns     int           `conf:"default:25"`
+			ConnMaxLifetime  time.Duration `conf:"default:30m"`
+			ConnMaxIdleTime  time.Duration `conf:"default:5m"`
+			StatementTimeout time.Duration `conf:"default:30s"`
+			DisableTLS       bool          `conf:"default:true"`
+			TLSRootCert      string
+			TLSCert          string
+			TLSKey           string
+			ConnectTimeout   time.Duration `conf:"default:30s"`
+			TenantSchemas    bool          `conf:"default:false"`
+			SlowQuery        struct {
+				Threshold time.Duration `conf:"default:500ms"`
+				Explain   bool          `conf:"default:false"`
+			}
+			Replicas struct {
+				Hosts         []string
+				MaxLag        time.Duration `conf:"default:5s"`
+				CheckInterval time.Duration `conf:"default:5s"`
+			}
 		}
 		Tempo struct {
 			Host        string  `conf:"default:tempo:4317"`
@@ -117,28 +179,87 @@ func run(ctx context.Context, log *logger.Logger) error {
 	// -------------------------------------------------------------------------
 	// Database Support
 	log.Info(ctx, "startup", "status", "initializing database support", "hostport", cfg.DB.Host)
-	db, err := sqldb.Open(sqldb.Config{
-		User:         cfg.DB.User,
-		Password:     cfg.DB.Password,
-		Host:         cfg.DB.Host,
-		Name:         cfg.DB.Name,
-		MaxIdleConns: cfg.DB.MaxIdleConns,
-		MaxOpenConns: cfg.DB.MaxOpenConns,
-		DisableTLS:   cfg.DB.DisableTLS,
+
+	// With a schema per tenant, every statement of a request runs in the
+	// schema of the organization making it.
+	var schemas sqldb.SchemaFunc
+	if cfg.DB.TenantSchemas {
+		schemas = tenant.Schema
+	}
+
+	cluster, err := sqldb.OpenCluster(sqldb.ClusterConfig{
+		Config: sqldb.Config{
+			User:             cfg.DB.User,
+			Password:         cfg.DB.Password,
+			Host:             cfg.DB.Host,
+			Name:             cfg.DB.Name,
+			MaxIdleConns:     cfg.DB.MaxIdleConns,
+			MaxOpenConns:     cfg.DB.MaxOpenConns,
+			ConnMaxLifetime:  cfg.DB.ConnMaxLifetime,
+			ConnMaxIdleTime:  cfg.DB.ConnMaxIdleTime,
+			StatementTimeout: cfg.DB.StatementTimeout,
+			ApplicationName:  "sales",
+			DisableTLS:       cfg.DB.DisableTLS,
+			TLSRootCert:      cfg.DB.TLSRootCert,
+			TLSCert:          cfg.DB.TLSCert,
+			TLSKey:           cfg.DB.TLSKey,
+			ConnectTimeout:   cfg.DB.ConnectTimeout,
+		},
+		ReplicaHosts: cfg.DB.Replicas.Hosts,
+		MaxLag:       cfg.DB.Replicas.MaxLag,
+		Schemas:      schemas,
 	})
 	if err != nil {
 		return fmt.Errorf("connecting to db: %w", err)
 	}
 
-	defer db.Close()
+	defer cluster.Close()
+
+	sqldb.SetSlowQuery(sqldb.SlowQueryConfig{
+		Threshold: cfg.DB.SlowQuery.Threshold,
+		Explain:   cfg.DB.SlowQuery.Explain,
+	})
+
+	// Stores are given the cluster so their reads can go to the replicas.
+	// Transactions and the health checks use the primary.
+	db := cluster.Primary()
+
+	// Transactions are started on the primary pool, so they are bound to
+	// the schema of the organization on their own.
+	beginner := sqldb.NewBeginner(db)
+	if cfg.DB.TenantSchemas {
+		beginner = sqldb.NewSchemaBeginner(db, tenant.Schema)
+	}
 
 	// -------------------------------------------------------------------------
 	// Create Business Packages
 
-	userStorage := usercache.NewStore(log, userdb.NewStore(log, db), time.Minute)
+	userStorage := usercache.NewStore(log, userdb.NewStore(log, cluster), time.Minute)
 
 	delegate := delegate.New(log)
+	auditBus := auditbus.NewBusiness(log, auditdb.NewStore(log, cluster))
+	orgBus := orgbus.NewBusiness(log, orgdb.NewStore(log, cluster))
 	userBus := userbus.NewBusiness(log, delegate, userStorage)
+	roleBus := rolebus.NewBusiness(log, roledb.NewStore(log, cluster))
+
+	productOtelExt := productotel.NewExtension()
+	productStorage := productcache.NewStore(log, productdb.NewStore(log, cluster), time.Minute)
+	productBus := productbus.NewBusiness(log, userBus, delegate, productStorage, productOtelExt)
+
+	orderOtelExt := orderotel.NewExtension()
+	orderAuditExt := orderaudit.NewExtension(auditBus)
+	orderBus := orderbus.NewBusiness(log, productBus, delegate, orderdb.NewStore(log, cluster), orderOtelExt, orderAuditExt)
+
+	inviteOtelExt := inviteotel.NewExtension()
+	inviteAuditExt := inviteaudit.NewExtension(auditBus)
+	inviteSender := invitelog.NewSender(log, cfg.Invite.URL)
+	inviteBus := invitebus.NewBusiness(log, userBus, inviteSender, invitedb.NewStore(log, cluster), cfg.Invite.TTL, inviteOtelExt, inviteAuditExt)
+
+	gdprRegistry := gdpr.New(log)
+	userbus.RegisterGDPR(gdprRegistry, userBus)
+	auditbus.RegisterGDPR(gdprRegistry, auditBus, invitebus.GDPRInvitations(inviteBus, userBus))
+
+	gdprBus := gdprbus.NewBusiness(log, userBus, gdprRegistry, gdprdb.NewStore(log, cluster))
 
 	// -------------------------------------------------------------------------
 	// Initialize authentication support
@@ -179,6 +300,76 @@ func run(ctx context.Context, log *logger.Logger) error {
 		}
 	}()
 
+	// -------------------------------------------------------------------------
+	// Start Background Jobs
+
+	// The background jobs act across every organization.
+	jobsCtx, jobsCancel := context.WithCancel(tenant.System(ctx))
+	defer jobsCancel()
+
+	if len(cfg.DB.Replicas.Hosts) > 0 {
+		go periodic.Run(jobsCtx, log, "replica health", cfg.DB.Replicas.CheckInterval, cluster.Check)
+	}
+
+	// The purge and gdpr jobs must only run on one instance at a time.
+	leader := sqldb.NewLeader(log, db, sqldb.LeaderConfig{
+		Name:          "sales-jobs",
+		RetryInterval: cfg.Leader.RetryInterval,
+		CheckInterval: cfg.Leader.CheckInterval,
+	})
+
+	// With a schema per tenant the data of every organization lives in a
+	// schema of its own, so the jobs run once for every organization.
+	forEachTenant := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		if !cfg.DB.TenantSchemas {
+			return fn(ctx)
+		}
+		return orgBus.ForEach(ctx, fn)
+	}
+
+	purgeUsers := func(ctx context.Context) error {
+		return forEachTenant(ctx, func(ctx context.Context) error {
+			n, err := userBus.Purge(ctx, beginner, time.Now().Add(-cfg.Purge.Retention))
+			if err != nil {
+				return fmt.Errorf("purge users: %w", err)
+			}
+
+			if n > 0 {
+				log.Info(ctx, "purge", "status", "users purged", "count", n)
+			}
+
+			return nil
+		})
+	}
+
+	processGDPR := func(ctx context.Context) error {
+		return forEachTenant(ctx, func(ctx context.Context) error {
+			if _, err := gdprBus.ProcessPending(ctx); err != nil {
+				return fmt.Errorf("process gdpr jobs: %w", err)
+			}
+
+			return nil
+		})
+	}
+
+	var jobs sync.WaitGroup
+
+	startJobs := func(ctx context.Context) {
+		jobs.Go(func() {
+			periodic.Run(ctx, log, "user purge", cfg.Purge.Interval, purgeUsers)
+		})
+
+		jobs.Go(func() {
+			periodic.Run(ctx, log, "gdpr jobs", cfg.GDPR.Interval, processGDPR)
+		})
+	}
+
+	stopJobs := func(ctx context.Context) {
+		jobs.Wait()
+	}
+
+	go leader.Run(jobsCtx, startJobs, stopJobs)
+
 	// -------------------------------------------------------------------------
 	// Start API Service
 	log.Info(ctx, "startup", "status", "initializing V1 API support")
@@ -193,10 +384,20 @@ func run(ctx context.Context, log *logger.Logger) error {
 		DB:       db,
 		Tracer:   tracer,
 		BusConfig: mux.BusConfig{
-			UserBus: userBus,
+			UserBus:    userBus,
+			InviteBus:  inviteBus,
+			GDPRBus:    gdprBus,
+			ProductBus: productBus,
+			OrderBus:   orderBus,
+			RoleBus:    roleBus,
 		},
 		SalesConfig: mux.SalesConfig{
 			AuthClient: authClient,
+			Beginner:   beginner,
+			NameRules: name.Rules{
+				MinLength: cfg.Name.MinLength,
+				MaxLength: cfg.Name.MaxLength,
+			},
 		},
 	}
 	api := http.Server{
@@ -0,0 +1,71 @@
+package userapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"service/app/domain/userapp"
+	"service/app/sdk/apitest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func import200(sd apitest.SeedData) []apitest.Table {
+	table := []apitest.Table{
+		{
+			Name:       "dry-run",
+			URL:        "/v1/users/import?format=ndjson&dry_run=true",
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input:      json.RawMessage(`{"name":"Bill Kennedy","email":"bill@ardanlabs.com","roles":["USER"],"password":"123"}`),
+			GotResp:    &userapp.ImportReport{},
+			ExpResp: &userapp.ImportReport{
+				Total:  1,
+				Valid:  1,
+				DryRun: true,
+				Errors: []userapp.ImportError{},
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "row-errors",
+			URL:        "/v1/users/import?format=ndjson",
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input:      json.RawMessage(`{"name":"Bill Kennedy","email":"` + sd.Users[0].Email.Address + `","roles":["USER"],"password":"123"}`),
+			GotResp:    &userapp.ImportReport{},
+			ExpResp: &userapp.ImportReport{
+				Total: 1,
+				Errors: []userapp.ImportError{
+					{Row: 1, Email: sd.Users[0].Email.Address, Error: "email is not unique"},
+				},
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "basic",
+			URL:        "/v1/users/import?format=ndjson",
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			Input:      json.RawMessage(`{"name":"Bill Kennedy","email":"bill@ardanlabs.com","roles":["USER"],"password":"123"}`),
+			GotResp:    &userapp.ImportReport{},
+			ExpResp: &userapp.ImportReport{
+				Total:   1,
+				Valid:   1,
+				Created: 1,
+				Errors:  []userapp.ImportError{},
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -7,11 +7,12 @@ import (
 	"service/app/sdk/auth"
 	"service/business/domain/userbus"
 	"service/business/sdk/dbtest"
+	"service/business/sdk/tenant"
 	"service/business/types/role"
 )
 
 func insertSeedData(db *dbtest.Database, ath *auth.Auth) (apitest.SeedData, error) {
-	ctx := context.Background()
+	ctx := tenant.System(context.Background())
 	busDomain := db.BusDomain
 
 	usrs, err := userbus.TestSeedUsers(ctx, 2, role.AdminRole, busDomain.User)
@@ -0,0 +1,95 @@
+package userapi
+
+import (
+	"net/http"
+	"service/app/domain/userapp"
+	"service/app/sdk/apitest"
+	"service/app/sdk/errs"
+	"strconv"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func update200(sd apitest.SeedData) []apitest.Table {
+	dept := "Sales"
+
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        "/v1/users/" + sd.Users[0].ID.String(),
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPut,
+			Headers:    map[string]string{"If-Match": strconv.Quote(strconv.Itoa(sd.Users[0].Version))},
+			StatusCode: http.StatusOK,
+			Input: &userapp.UpdateUser{
+				Department: &dept,
+			},
+			GotResp: &userapp.User{},
+			ExpResp: func() *userapp.User {
+				app := toAppUser(sd.Users[0].User)
+				app.Department = dept
+				return &app
+			}(),
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*userapp.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(*userapp.User)
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func update412(sd apitest.SeedData) []apitest.Table {
+	dept := "Marketing"
+
+	table := []apitest.Table{
+		{
+			Name:       "stale-version",
+			URL:        "/v1/users/" + sd.Users[1].ID.String(),
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPut,
+			Headers:    map[string]string{"If-Match": strconv.Quote(strconv.Itoa(sd.Users[1].Version + 1))},
+			StatusCode: http.StatusPreconditionFailed,
+			Input: &userapp.UpdateUser{
+				Department: &dept,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: &errs.Error{
+				Code:    errs.PreconditionFailed,
+				Message: "user does not match the If-Match version",
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "weak-tag",
+			URL:        "/v1/users/" + sd.Users[1].ID.String(),
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPut,
+			Headers:    map[string]string{"If-Match": "W/" + strconv.Quote(strconv.Itoa(sd.Users[1].Version))},
+			StatusCode: http.StatusPreconditionFailed,
+			Input: &userapp.UpdateUser{
+				Department: &dept,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: &errs.Error{
+				Code:    errs.PreconditionFailed,
+				Message: "user does not match the If-Match version",
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -12,8 +12,14 @@ func Test_User(t *testing.T) {
 
 	// -------------------------------------------------------------------------
 
-	_, err := insertSeedData(test.DB, test.Auth)
+	sd, err := insertSeedData(test.DB, test.Auth)
 	if err != nil {
 		t.Fatalf("Seeding error: %s", err)
 	}
+
+	// -------------------------------------------------------------------------
+
+	test.Run(t, update200(sd), "update-200")
+	test.Run(t, update412(sd), "update-412")
+	test.Run(t, import200(sd), "import-200")
 }
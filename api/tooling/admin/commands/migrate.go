@@ -12,8 +12,9 @@ import (
 // ErrHelp provides context that help was given.
 var ErrHelp = errors.New("provided help")
 
-// Migrate creates the schema in the database.
-func Migrate(cfg sqldb.Config) error {
+// Migrate creates the schema in the database. When the database runs with a
+// schema per tenant, the schema of every organization is migrated as well.
+func Migrate(cfg sqldb.Config, tenantSchemas bool) error {
 	db, err := sqldb.Open(cfg)
 	if err != nil {
 		return fmt.Errorf("connect database: %w", err)
@@ -27,6 +28,12 @@ func Migrate(cfg sqldb.Config) error {
 		return fmt.Errorf("migrate database: %w", err)
 	}
 
+	if tenantSchemas {
+		if err := migrate.MigrateTenants(ctx, cfg); err != nil {
+			return fmt.Errorf("migrate tenant schemas: %w", err)
+		}
+	}
+
 	fmt.Println("migrations complete")
 	return nil
 }
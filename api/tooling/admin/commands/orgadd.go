@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"service/business/domain/orgbus"
+	"service/business/domain/orgbus/stores/orgdb"
+	"service/business/sdk/migrate"
+	"service/business/sdk/sqldb"
+	"service/foundation/logger"
+	"time"
+)
+
+// OrgAdd adds a new organization to the database. When the database runs
+// with a schema per tenant, the schema for the organization is created.
+func OrgAdd(log *logger.Logger, cfg sqldb.Config, name string, tenantSchemas bool) error {
+	if name == "" {
+		fmt.Println("help: orgadd <name>")
+		return ErrHelp
+	}
+
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	orgBus := orgbus.NewBusiness(log, orgdb.NewStore(log, db))
+
+	org, err := orgBus.Create(ctx, orgbus.NewOrg{Name: name})
+	if err != nil {
+		return fmt.Errorf("create org: %w", err)
+	}
+
+	if tenantSchemas {
+		if err := migrate.MigrateTenant(ctx, cfg, org.ID); err != nil {
+			return fmt.Errorf("migrate tenant schema: %w", err)
+		}
+	}
+
+	fmt.Println("org id:", org.ID)
+	return nil
+}
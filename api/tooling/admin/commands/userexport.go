@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"service/app/domain/userapp"
+	"service/business/domain/userbus"
+	"service/business/domain/userbus/stores/userdb"
+	"service/business/sdk/delegate"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/foundation/logger"
+	"time"
+)
+
+// UserExport writes every user in the system to stdout in the specified
+// format.
+func UserExport(log *logger.Logger, cfg sqldb.Config, format string) error {
+	format, err := userapp.ParseFormat(format, "")
+	if err != nil {
+		fmt.Println("help: userexport <csv|ndjson>")
+		return ErrHelp
+	}
+
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	// The admin tooling acts across every organization.
+	ctx, cancel := context.WithTimeout(tenant.System(context.Background()), 10*time.Minute)
+	defer cancel()
+
+	userBus := userbus.NewBusiness(log, delegate.New(log), userdb.NewStore(log, db))
+
+	var filter userbus.QueryFilter
+	filter.WithIncludeDeleted(true)
+
+	if _, err := userapp.Export(ctx, userBus, os.Stdout, format, filter, userbus.DefaultOrderBy); err != nil {
+		return fmt.Errorf("export users: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"service/app/domain/userapp"
+	"service/business/domain/rolebus"
+	"service/business/domain/rolebus/stores/roledb"
+	"service/business/domain/userbus"
+	"service/business/domain/userbus/stores/userdb"
+	"service/business/sdk/delegate"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/name"
+	"service/foundation/logger"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// importChunkSize is the number of users created per transaction when
+// importing from the command line.
+const importChunkSize = 500
+
+// UserImport loads the users held in the specified CSV or NDJSON file. The
+// format is taken from the file extension. When dryRun is set the file is
+// only validated.
+func UserImport(log *logger.Logger, cfg sqldb.Config, path string, dryRun bool) error {
+	if path == "" {
+		fmt.Println("help: userimport <file.csv|file.ndjson> [dryrun]")
+		return ErrHelp
+	}
+
+	format, err := userapp.ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."), "")
+	if err != nil {
+		return fmt.Errorf("format: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	// The admin tooling acts across every organization.
+	ctx, cancel := context.WithTimeout(tenant.System(context.Background()), 10*time.Minute)
+	defer cancel()
+
+	userBus := userbus.NewBusiness(log, delegate.New(log), userdb.NewStore(log, db))
+	roleBus := rolebus.NewBusiness(log, roledb.NewStore(log, db))
+
+	importCfg := userapp.ImportConfig{
+		Format:     format,
+		DryRun:     dryRun,
+		ChunkSize:  importChunkSize,
+		CheckRoles: roleBus.Check,
+		NameRules:  name.DefaultRules(),
+	}
+
+	report, err := userapp.Import(ctx, userBus, sqldb.NewBeginner(db), uuid.Nil, f, importCfg)
+	if err != nil {
+		return fmt.Errorf("import users: created[%d]: %w", report.Created, err)
+	}
+
+	for _, ie := range report.Errors {
+		fmt.Printf("row %d: %s: %s\n", ie.Row, ie.Email, ie.Error)
+	}
+
+	fmt.Printf("total: %d, valid: %d, created: %d, dryrun: %t\n", report.Total, report.Valid, report.Created, report.DryRun)
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d rows failed validation, nothing was imported", len(report.Errors))
+	}
+
+	return nil
+}
@@ -19,13 +19,14 @@ type config struct {
 	conf.Version
 	Args conf.Args
 	DB   struct {
-		User         string `conf:"default:postgres"`
-		Password     string `conf:"default:postgres,mask"`
-		Host         string `conf:"default:database-service"`
-		Name         string `conf:"default:postgres"`
-		MaxIdleConns int    `conf:"default:0"`
-		MaxOpenConns int    `conf:"default:0"`
-		DisableTLS   bool   `conf:"default:true"`
+		User          string `conf:"default:postgres"`
+		Password      string `conf:"default:postgres,mask"`
+		Host          string `conf:"default:database-service"`
+		Name          string `conf:"default:postgres"`
+		MaxIdleConns  int    `conf:"default:0"`
+		MaxOpenConns  int    `conf:"default:0"`
+		DisableTLS    bool   `conf:"default:true"`
+		TenantSchemas bool   `conf:"default:false"`
 	}
 	Auth struct {
 		KeysFolder string `conf:"default:zarf/keys/"`
@@ -87,7 +88,7 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 
 	switch args.Num(0) {
 	case "migrate":
-		if err := commands.Migrate(dbConfig); err != nil {
+		if err := commands.Migrate(dbConfig, cfg.DB.TenantSchemas); err != nil {
 			return fmt.Errorf("migrating database: %w", err)
 		}
 
@@ -97,17 +98,37 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 		}
 
 	case "migrate-seed":
-		if err := commands.Migrate(dbConfig); err != nil {
+		if err := commands.Migrate(dbConfig, cfg.DB.TenantSchemas); err != nil {
 			return fmt.Errorf("migrating database: %w", err)
 		}
 		if err := commands.Seed(dbConfig); err != nil {
 			return fmt.Errorf("seeding database: %w", err)
 		}
+
+	case "orgadd":
+		if err := commands.OrgAdd(log, dbConfig, args.Num(1), cfg.DB.TenantSchemas); err != nil {
+			return fmt.Errorf("adding org: %w", err)
+		}
+
+	case "userimport":
+		dryRun := args.Num(2) == "dryrun"
+		if err := commands.UserImport(log, dbConfig, args.Num(1), dryRun); err != nil {
+			return fmt.Errorf("importing users: %w", err)
+		}
+
+	case "userexport":
+		if err := commands.UserExport(log, dbConfig, args.Num(1)); err != nil {
+			return fmt.Errorf("exporting users: %w", err)
+		}
+
 	default:
 		fmt.Println("migrate:    create the schema in the database")
 		fmt.Println("seed:       add data to the database")
+		fmt.Println("orgadd:     add a new organization to the database")
 		fmt.Println("useradd:    add a new user to the database")
 		fmt.Println("users:      get a list of users from the database")
+		fmt.Println("userimport: add the users from a csv or ndjson file")
+		fmt.Println("userexport: write all users to stdout as csv or ndjson")
 		fmt.Println("genkey:     generate a set of private/public key files")
 		fmt.Println("gentoken:   generate a JWT for a user with claims")
 		fmt.Println("provide a command to get more help.")
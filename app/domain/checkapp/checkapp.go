@@ -59,7 +59,12 @@ func (a *app) readiness(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.Internal, err)
 	}
 
-	return nil
+	rdy := Readiness{
+		Status: "ok",
+		DB:     toAppPool(sqldb.Stats(a.db)),
+	}
+
+	return rdy
 }
 
 // func panics(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -1,6 +1,9 @@
 package checkapp
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"service/business/sdk/sqldb"
+)
 
 // Info represents information about the service.
 type Info struct {
@@ -19,3 +22,37 @@ func (app Info) Encode() ([]byte, string, error) {
 	data, err := json.Marshal(app)
 	return data, "application/json", err
 }
+
+// Readiness represents the readiness of the service along with the state
+// of its database connection pool.
+type Readiness struct {
+	Status string `json:"status"`
+	DB     Pool   `json:"db"`
+}
+
+// Encode implements the encoder interface.
+func (app Readiness) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Pool represents the state of a database connection pool.
+type Pool struct {
+	MaxOpen        int   `json:"maxOpen"`
+	Open           int   `json:"open"`
+	InUse          int   `json:"inUse"`
+	Idle           int   `json:"idle"`
+	WaitCount      int64 `json:"waitCount"`
+	WaitDurationMS int64 `json:"waitDurationMS"`
+}
+
+func toAppPool(stats sqldb.PoolStats) Pool {
+	return Pool{
+		MaxOpen:        stats.MaxOpen,
+		Open:           stats.Open,
+		InUse:          stats.InUse,
+		Idle:           stats.Idle,
+		WaitCount:      stats.WaitCount,
+		WaitDurationMS: stats.WaitDuration.Milliseconds(),
+	}
+}
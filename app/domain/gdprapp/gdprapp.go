@@ -0,0 +1,98 @@
+// Package gdprapp maintains the app layer api for data subject requests.
+package gdprapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"service/app/sdk/errs"
+	"service/app/sdk/mid"
+	"service/business/domain/gdprbus"
+	"service/business/types/jobkind"
+	"service/business/types/jobstatus"
+	"service/foundation/web"
+
+	"github.com/google/uuid"
+)
+
+type app struct {
+	gdprBus *gdprbus.Business
+}
+
+func newApp(gdprBus *gdprbus.Business) *app {
+	return &app{
+		gdprBus: gdprBus,
+	}
+}
+
+func (a *app) export(ctx context.Context, r *http.Request) web.Encoder {
+	return a.create(ctx, r, jobkind.Export)
+}
+
+func (a *app) erase(ctx context.Context, r *http.Request) web.Encoder {
+	return a.create(ctx, r, jobkind.Erase)
+}
+
+func (a *app) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	job, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	return toAppJob(job)
+}
+
+func (a *app) archive(ctx context.Context, r *http.Request) web.Encoder {
+	job, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	if job.Status != jobstatus.Completed || len(job.Archive) == 0 {
+		return errs.New(errs.FailedPrecondition, gdprbus.ErrNoArchive)
+	}
+
+	return Archive{data: job.Archive}
+}
+
+// =============================================================================
+
+func (a *app) create(ctx context.Context, r *http.Request, kind jobkind.Kind) web.Encoder {
+	var app NewJob
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	nj, err := toBusNewJob(app, kind)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	job, err := a.gdprBus.Create(ctx, mid.GetSubjectID(ctx), nj)
+	if err != nil {
+		if errors.Is(err, gdprbus.ErrUserNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "create: job[%+v]: %s", nj, err)
+	}
+
+	return toAppJob(job)
+}
+
+func (a *app) queryByParam(ctx context.Context, r *http.Request) (gdprbus.Job, error) {
+	id, err := uuid.Parse(web.Param(r, "job_id"))
+	if err != nil {
+		return gdprbus.Job{}, errs.NewFieldErrors("job_id", err)
+	}
+
+	job, err := a.gdprBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gdprbus.ErrNotFound) {
+			return gdprbus.Job{}, errs.New(errs.NotFound, err)
+		}
+		return gdprbus.Job{}, errs.New(errs.Internal, fmt.Errorf("querybyid: jobID[%s]: %w", id, err))
+	}
+
+	return job, nil
+}
@@ -0,0 +1,101 @@
+package gdprapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"service/app/sdk/errs"
+	"service/business/domain/gdprbus"
+	"service/business/types/jobkind"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job represents information about an individual data subject job.
+type Job struct {
+	ID            string `json:"id"`
+	OrgID         string `json:"orgID"`
+	UserID        string `json:"userID"`
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	HasArchive    bool   `json:"hasArchive"`
+	Error         string `json:"error,omitempty"`
+	RequestedBy   string `json:"requestedBy"`
+	DateCreated   string `json:"dateCreated"`
+	DateUpdated   string `json:"dateUpdated"`
+	DateCompleted string `json:"dateCompleted,omitempty"`
+}
+
+// Encode implements the encoder interface.
+func (app Job) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppJob(job gdprbus.Job) Job {
+	var dateCompleted string
+	if !job.DateCompleted.IsZero() {
+		dateCompleted = job.DateCompleted.Format(time.RFC3339)
+	}
+
+	return Job{
+		ID:            job.ID.String(),
+		OrgID:         job.OrgID.String(),
+		UserID:        job.UserID.String(),
+		Kind:          job.Kind.String(),
+		Status:        job.Status.String(),
+		HasArchive:    len(job.Archive) > 0,
+		Error:         job.Error,
+		RequestedBy:   job.RequestedBy.String(),
+		DateCreated:   job.DateCreated.Format(time.RFC3339),
+		DateUpdated:   job.DateUpdated.Format(time.RFC3339),
+		DateCompleted: dateCompleted,
+	}
+}
+
+// =============================================================================
+
+// Archive represents the downloadable export produced by a job.
+type Archive struct {
+	data []byte
+}
+
+// Encode implements the encoder interface.
+func (app Archive) Encode() ([]byte, string, error) {
+	return app.data, "application/zip", nil
+}
+
+// =============================================================================
+
+// NewJob defines the data needed to request an export or erasure.
+type NewJob struct {
+	UserID string `json:"userID" validate:"required,uuid"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewJob) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewJob) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewJob(app NewJob, kind jobkind.Kind) (gdprbus.NewJob, error) {
+	userID, err := uuid.Parse(app.UserID)
+	if err != nil {
+		return gdprbus.NewJob{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := gdprbus.NewJob{
+		UserID: userID,
+		Kind:   kind,
+	}
+
+	return bus, nil
+}
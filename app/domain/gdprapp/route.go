@@ -0,0 +1,33 @@
+package gdprapp
+
+import (
+	"net/http"
+	"service/app/sdk/auth"
+	"service/app/sdk/authclient"
+	"service/app/sdk/mid"
+	"service/business/domain/gdprbus"
+	"service/foundation/logger"
+	"service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	GDPRBus    *gdprbus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	tenant := mid.Tenant()
+	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+
+	api := newApp(cfg.GDPRBus)
+	app.HandleFunc(http.MethodPost, version, "/gdpr/exports", api.export, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/gdpr/erasures", api.erase, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodGet, version, "/gdpr/jobs/{job_id}", api.queryByID, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodGet, version, "/gdpr/jobs/{job_id}/archive", api.archive, authen, tenant, ruleAdmin)
+}
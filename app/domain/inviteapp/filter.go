@@ -0,0 +1,113 @@
+package inviteapp
+
+import (
+	"net/http"
+	"net/mail"
+	"service/app/sdk/errs"
+	"service/business/domain/invitebus"
+	"service/business/types/invitestatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	ID               string
+	Email            string
+	Status           string
+	InvitedBy        string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		ID:               values.Get("invitation_id"),
+		Email:            values.Get("email"),
+		Status:           values.Get("status"),
+		InvitedBy:        values.Get("invited_by"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+
+	return filter
+}
+
+func parseFilter(qp queryParams) (invitebus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter invitebus.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		switch err {
+		case nil:
+			filter.ID = &id
+		default:
+			fieldErrors.Add("invitation_id", err)
+		}
+	}
+
+	if qp.Email != "" {
+		addr, err := mail.ParseAddress(qp.Email)
+		switch err {
+		case nil:
+			filter.Email = addr
+		default:
+			fieldErrors.Add("email", err)
+		}
+	}
+
+	if qp.Status != "" {
+		status, err := invitestatus.Parse(qp.Status)
+		switch err {
+		case nil:
+			filter.Status = &status
+		default:
+			fieldErrors.Add("status", err)
+		}
+	}
+
+	if qp.InvitedBy != "" {
+		id, err := uuid.Parse(qp.InvitedBy)
+		switch err {
+		case nil:
+			filter.InvitedBy = &id
+		default:
+			fieldErrors.Add("invited_by", err)
+		}
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		switch err {
+		case nil:
+			filter.StartCreatedDate = &t
+		default:
+			fieldErrors.Add("start_created_date", err)
+		}
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		switch err {
+		case nil:
+			filter.EndCreatedDate = &t
+		default:
+			fieldErrors.Add("end_created_date", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return invitebus.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,235 @@
+// Package inviteapp maintains the app layer api for the invitation domain.
+package inviteapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"service/app/sdk/errs"
+	"service/app/sdk/mid"
+	"service/app/sdk/query"
+	"service/business/domain/invitebus"
+	"service/business/domain/rolebus"
+	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/types/name"
+	"service/foundation/web"
+
+	"github.com/google/uuid"
+)
+
+type app struct {
+	inviteBus invitebus.ExtBusiness
+	roleBus   *rolebus.Business
+	nameRules name.Rules
+}
+
+func newApp(inviteBus invitebus.ExtBusiness, roleBus *rolebus.Business, nameRules name.Rules) *app {
+	return &app{
+		inviteBus: inviteBus,
+		roleBus:   roleBus,
+		nameRules: nameRules,
+	}
+}
+
+// newWithTx constructs a new app value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *app) newWithTx(ctx context.Context) (*app, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteBus, err := a.inviteBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := app{
+		inviteBus: inviteBus,
+		roleBus:   a.roleBus,
+		nameRules: a.nameRules,
+	}
+
+	return &app, nil
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewInvitation
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ni, err := toBusNewInvitation(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := a.roleBus.Check(ctx, ni.Roles); err != nil {
+		if errors.Is(err, rolebus.ErrUnknownRole) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "check roles: %s", err)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	inv, err := a.inviteBus.Create(ctx, mid.GetSubjectID(ctx), ni)
+	if err != nil {
+		switch {
+		case errors.Is(err, invitebus.ErrUserExists):
+			return errs.New(errs.AlreadyExists, invitebus.ErrUserExists)
+		case errors.Is(err, invitebus.ErrPendingExists):
+			return errs.New(errs.AlreadyExists, invitebus.ErrPendingExists)
+		}
+		return errs.Newf(errs.Internal, "create: inv[%+v]: %s", ni, err)
+	}
+
+	return toAppInvitation(inv)
+}
+
+func (a *app) resend(ctx context.Context, r *http.Request) web.Encoder {
+	inv, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	inv, err = a.inviteBus.Resend(ctx, mid.GetSubjectID(ctx), inv)
+	if err != nil {
+		if errors.Is(err, invitebus.ErrNotPending) {
+			return errs.New(errs.FailedPrecondition, invitebus.ErrNotPending)
+		}
+		return errs.Newf(errs.Internal, "resend: invitationID[%s]: %s", inv.ID, err)
+	}
+
+	return toAppInvitation(inv)
+}
+
+func (a *app) revoke(ctx context.Context, r *http.Request) web.Encoder {
+	inv, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	if _, err := a.inviteBus.Revoke(ctx, mid.GetSubjectID(ctx), inv); err != nil {
+		if errors.Is(err, invitebus.ErrNotPending) {
+			return errs.New(errs.FailedPrecondition, invitebus.ErrNotPending)
+		}
+		return errs.Newf(errs.Internal, "revoke: invitationID[%s]: %s", inv.ID, err)
+	}
+
+	return nil
+}
+
+func (a *app) accept(ctx context.Context, r *http.Request) web.Encoder {
+	var app AcceptInvitation
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ai, err := toBusAcceptInvitation(app, a.nameRules)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	inv, usr, err := a.inviteBus.Accept(ctx, ai)
+	if err != nil {
+		switch {
+		case errors.Is(err, invitebus.ErrNotFound):
+			return errs.New(errs.NotFound, invitebus.ErrNotFound)
+		case errors.Is(err, invitebus.ErrNotPending):
+			return errs.New(errs.FailedPrecondition, invitebus.ErrNotPending)
+		case errors.Is(err, invitebus.ErrExpired):
+			return errs.New(errs.FailedPrecondition, invitebus.ErrExpired)
+		case errors.Is(err, userbus.ErrUniqueEmail):
+			return errs.New(errs.Aborted, userbus.ErrUniqueEmail)
+		}
+		return errs.Newf(errs.Internal, "accept: %s", err)
+	}
+
+	resp := AcceptedUser{
+		ID:           usr.ID.String(),
+		Name:         usr.Name.String(),
+		Email:        usr.Email.Address,
+		InvitationID: inv.ID.String(),
+	}
+
+	return resp
+}
+
+func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, invitebus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	invs, err := a.inviteBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.inviteBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppInvitations(invs), total, page)
+}
+
+func (a *app) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	inv, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	return toAppInvitation(inv)
+}
+
+func (a *app) queryByParam(ctx context.Context, r *http.Request) (invitebus.Invitation, error) {
+	id, err := uuid.Parse(web.Param(r, "invitation_id"))
+	if err != nil {
+		return invitebus.Invitation{}, errs.NewFieldErrors("invitation_id", err)
+	}
+
+	inv, err := a.inviteBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, invitebus.ErrNotFound) {
+			return invitebus.Invitation{}, errs.New(errs.NotFound, err)
+		}
+		return invitebus.Invitation{}, errs.New(errs.Internal, fmt.Errorf("querybyid: invitationID[%s]: %w", id, err))
+	}
+
+	return inv, nil
+}
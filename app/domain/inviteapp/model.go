@@ -0,0 +1,170 @@
+package inviteapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"service/app/sdk/errs"
+	"service/business/domain/invitebus"
+	"service/business/types/name"
+	"service/business/types/role"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation represents information about an individual invitation.
+type Invitation struct {
+	ID          string   `json:"id"`
+	OrgID       string   `json:"orgID"`
+	Email       string   `json:"email"`
+	Roles       []string `json:"roles"`
+	Department  string   `json:"department"`
+	Status      string   `json:"status"`
+	InvitedBy   string   `json:"invitedBy,omitempty"`
+	UserID      string   `json:"userID,omitempty"`
+	DateExpires string   `json:"dateExpires"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Invitation) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppInvitation(inv invitebus.Invitation) Invitation {
+	var invitedBy string
+	if inv.InvitedBy != uuid.Nil {
+		invitedBy = inv.InvitedBy.String()
+	}
+
+	var userID string
+	if inv.UserID != uuid.Nil {
+		userID = inv.UserID.String()
+	}
+
+	return Invitation{
+		ID:          inv.ID.String(),
+		OrgID:       inv.OrgID.String(),
+		Email:       inv.Email.Address,
+		Roles:       role.ParseToString(inv.Roles),
+		Department:  inv.Department,
+		Status:      inv.Status.String(),
+		InvitedBy:   invitedBy,
+		UserID:      userID,
+		DateExpires: inv.DateExpires.Format(time.RFC3339),
+		DateCreated: inv.DateCreated.Format(time.RFC3339),
+		DateUpdated: inv.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppInvitations(invs []invitebus.Invitation) []Invitation {
+	app := make([]Invitation, len(invs))
+	for i, inv := range invs {
+		app[i] = toAppInvitation(inv)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewInvitation defines the data needed to invite someone.
+type NewInvitation struct {
+	Email      string   `json:"email" validate:"required,email"`
+	Roles      []string `json:"roles" validate:"required"`
+	Department string   `json:"department"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewInvitation) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewInvitation) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewInvitation(app NewInvitation) (invitebus.NewInvitation, error) {
+	roles, err := role.ParseMany(app.Roles)
+	if err != nil {
+		return invitebus.NewInvitation{}, fmt.Errorf("parse: %w", err)
+	}
+
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return invitebus.NewInvitation{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := invitebus.NewInvitation{
+		Email:      *addr,
+		Roles:      roles,
+		Department: app.Department,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// AcceptInvitation defines the data the invitee provides to activate
+// their account.
+type AcceptInvitation struct {
+	Token           string `json:"token" validate:"required"`
+	Name            string `json:"name" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Decode implements the decoder interface.
+func (app *AcceptInvitation) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AcceptInvitation) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusAcceptInvitation(app AcceptInvitation, nameRules name.Rules) (invitebus.AcceptInvitation, error) {
+	nme, err := nameRules.Parse(app.Name)
+	if err != nil {
+		return invitebus.AcceptInvitation{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := invitebus.AcceptInvitation{
+		Token:    app.Token,
+		Name:     nme,
+		Password: app.Password,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// AcceptedUser represents the user that was created by accepting
+// an invitation.
+type AcceptedUser struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	InvitationID string `json:"invitationID"`
+}
+
+// Encode implements the encoder interface.
+func (app AcceptedUser) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
@@ -0,0 +1,13 @@
+package inviteapp
+
+import (
+	"service/business/domain/invitebus"
+)
+
+var orderByFields = map[string]string{
+	"invitation_id": invitebus.OrderByID,
+	"email":         invitebus.OrderByEmail,
+	"status":        invitebus.OrderByStatus,
+	"date_created":  invitebus.OrderByDateCreated,
+	"date_expires":  invitebus.OrderByDateExpires,
+}
@@ -0,0 +1,46 @@
+package inviteapp
+
+import (
+	"net/http"
+	"service/app/sdk/auth"
+	"service/app/sdk/authclient"
+	"service/app/sdk/mid"
+	"service/business/domain/invitebus"
+	"service/business/domain/rolebus"
+	"service/business/sdk/sqldb"
+	"service/business/types/name"
+	"service/foundation/logger"
+	"service/foundation/web"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	DB         *sqlx.DB
+	InviteBus  invitebus.ExtBusiness
+	RoleBus    *rolebus.Business
+	AuthClient *authclient.Client
+	Beginner   sqldb.Beginner
+	NameRules  name.Rules
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	tenant := mid.Tenant()
+	inviteeTenant := mid.TenantQuery("org")
+	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+	transaction := mid.BeginCommitRollback(cfg.Log, cfg.Beginner)
+
+	api := newApp(cfg.InviteBus, cfg.RoleBus, cfg.NameRules)
+	app.HandleFunc(http.MethodGet, version, "/invitations", api.query, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodGet, version, "/invitations/{invitation_id}", api.queryByID, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/invitations", api.create, authen, tenant, ruleAdmin, transaction)
+	app.HandleFunc(http.MethodPost, version, "/invitations/{invitation_id}/resend", api.resend, authen, tenant, ruleAdmin, transaction)
+	app.HandleFunc(http.MethodDelete, version, "/invitations/{invitation_id}", api.revoke, authen, tenant, ruleAdmin, transaction)
+	app.HandleFunc(http.MethodPost, version, "/invitations/accept", api.accept, inviteeTenant, transaction)
+}
@@ -0,0 +1,100 @@
+package orderapp
+
+import (
+	"net/http"
+	"service/app/sdk/errs"
+	"service/business/domain/orderbus"
+	"service/business/types/orderstatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	ID               string
+	UserID           string
+	Status           string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		ID:               values.Get("order_id"),
+		UserID:           values.Get("user_id"),
+		Status:           values.Get("status"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+
+	return filter
+}
+
+func parseFilter(qp queryParams) (orderbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter orderbus.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		switch err {
+		case nil:
+			filter.ID = &id
+		default:
+			fieldErrors.Add("order_id", err)
+		}
+	}
+
+	if qp.UserID != "" {
+		id, err := uuid.Parse(qp.UserID)
+		switch err {
+		case nil:
+			filter.UserID = &id
+		default:
+			fieldErrors.Add("user_id", err)
+		}
+	}
+
+	if qp.Status != "" {
+		status, err := orderstatus.Parse(qp.Status)
+		switch err {
+		case nil:
+			filter.Status = &status
+		default:
+			fieldErrors.Add("status", err)
+		}
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		switch err {
+		case nil:
+			filter.StartCreatedDate = &t
+		default:
+			fieldErrors.Add("start_created_date", err)
+		}
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		switch err {
+		case nil:
+			filter.EndCreatedDate = &t
+		default:
+			fieldErrors.Add("end_created_date", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return orderbus.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
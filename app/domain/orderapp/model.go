@@ -0,0 +1,169 @@
+package orderapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"service/app/sdk/errs"
+	"service/business/domain/orderbus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order represents information about an individual order.
+type Order struct {
+	ID          string `json:"id"`
+	OrgID       string `json:"orgID"`
+	UserID      string `json:"userID"`
+	Status      string `json:"status"`
+	Lines       []Line `json:"lines"`
+	Total       string `json:"total"`
+	Currency    string `json:"currency"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Line represents the quantity and price of a product in an order.
+type Line struct {
+	ProductID string `json:"productID"`
+	Quantity  int    `json:"quantity"`
+	Price     string `json:"price"`
+}
+
+// Encode implements the encoder interface.
+func (app Order) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppOrder(ord orderbus.Order) Order {
+	lines := make([]Line, len(ord.Lines))
+	for i, l := range ord.Lines {
+		lines[i] = Line{
+			ProductID: l.ProductID.String(),
+			Quantity:  l.Quantity,
+			Price:     l.Price.Decimal(),
+		}
+	}
+
+	// The lines are checked to be in a single currency when the order is
+	// placed so the total can't fail for a stored order.
+	total, _ := ord.Total()
+
+	return Order{
+		ID:          ord.ID.String(),
+		OrgID:       ord.OrgID.String(),
+		UserID:      ord.UserID.String(),
+		Status:      ord.Status.String(),
+		Lines:       lines,
+		Total:       total.Decimal(),
+		Currency:    total.Currency().String(),
+		DateCreated: ord.DateCreated.Format(time.RFC3339),
+		DateUpdated: ord.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrders(ords []orderbus.Order) []Order {
+	app := make([]Order, len(ords))
+	for i, ord := range ords {
+		app[i] = toAppOrder(ord)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewOrder defines the data needed to place a new order.
+type NewOrder struct {
+	Lines []NewLine `json:"lines" validate:"required,min=1,dive"`
+}
+
+// NewLine defines the quantity of a product being ordered.
+type NewLine struct {
+	ProductID string `json:"productID" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,gte=1"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewOrder) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewOrder) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewOrder(userID uuid.UUID, app NewOrder) (orderbus.NewOrder, error) {
+	lines := make([]orderbus.Line, len(app.Lines))
+	for i, l := range app.Lines {
+		productID, err := uuid.Parse(l.ProductID)
+		if err != nil {
+			return orderbus.NewOrder{}, fmt.Errorf("parse: productID: %w", err)
+		}
+
+		lines[i] = orderbus.Line{
+			ProductID: productID,
+			Quantity:  l.Quantity,
+		}
+	}
+
+	bus := orderbus.NewOrder{
+		UserID: userID,
+		Lines:  lines,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// Stock represents the quantity of a product available to be ordered.
+type Stock struct {
+	ProductID   string `json:"productID"`
+	Quantity    int    `json:"quantity"`
+	DateUpdated string `json:"dateUpdated,omitempty"`
+}
+
+// Encode implements the encoder interface.
+func (app Stock) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppStock(stock orderbus.Stock) Stock {
+	var dateUpdated string
+	if !stock.DateUpdated.IsZero() {
+		dateUpdated = stock.DateUpdated.Format(time.RFC3339)
+	}
+
+	return Stock{
+		ProductID:   stock.ProductID.String(),
+		Quantity:    stock.Quantity,
+		DateUpdated: dateUpdated,
+	}
+}
+
+// UpdateStock defines the data needed to set the stock of a product.
+type UpdateStock struct {
+	Quantity *int `json:"quantity" validate:"required,gte=0"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateStock) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateStock) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package orderapp
+
+import (
+	"service/business/domain/orderbus"
+)
+
+var orderByFields = map[string]string{
+	"order_id":     orderbus.OrderByID,
+	"user_id":      orderbus.OrderByUserID,
+	"status":       orderbus.OrderByStatus,
+	"date_created": orderbus.OrderByDateCreated,
+}
@@ -0,0 +1,272 @@
+// Package orderapp maintains the app layer api for the order domain.
+package orderapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"service/app/sdk/auth"
+	"service/app/sdk/authclient"
+	"service/app/sdk/errs"
+	"service/app/sdk/mid"
+	"service/app/sdk/query"
+	"service/business/domain/orderbus"
+	"service/business/domain/productbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/types/money"
+	"service/foundation/web"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotOwner is returned when a user accesses an order they didn't place.
+var ErrNotOwner = errors.New("order was placed by a different user")
+
+type app struct {
+	orderBus   orderbus.ExtBusiness
+	authClient *authclient.Client
+}
+
+func newApp(orderBus orderbus.ExtBusiness, authClient *authclient.Client) *app {
+	return &app{
+		orderBus:   orderBus,
+		authClient: authClient,
+	}
+}
+
+// newWithTx constructs a new app value with the domain apis
+// using a store transaction that was created via middleware.
+func (a *app) newWithTx(ctx context.Context) (*app, error) {
+	tx, err := mid.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBus, err := a.orderBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := app{
+		orderBus:   orderBus,
+		authClient: a.authClient,
+	}
+
+	return &app, nil
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewOrder
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	no, err := toBusNewOrder(mid.GetSubjectID(ctx), app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ord, err := a.orderBus.Create(ctx, mid.GetSubjectID(ctx), no)
+	if err != nil {
+		return writeError("create", no.UserID, err)
+	}
+
+	return toAppOrder(ord)
+}
+
+func (a *app) pay(ctx context.Context, r *http.Request) web.Encoder {
+	return a.transition(ctx, r, "pay", orderbus.ExtBusiness.Pay)
+}
+
+func (a *app) ship(ctx context.Context, r *http.Request) web.Encoder {
+	return a.transition(ctx, r, "ship", orderbus.ExtBusiness.Ship)
+}
+
+func (a *app) cancel(ctx context.Context, r *http.Request) web.Encoder {
+	return a.transition(ctx, r, "cancel", orderbus.ExtBusiness.Cancel)
+}
+
+func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	// Users only get to see the orders they placed unless one of their
+	// roles lets them manage every order.
+	manageAny, err := mid.Allowed(ctx, a.authClient, auth.RuleManageAnyOrder)
+	if err != nil {
+		return errs.Newf(errs.Internal, "allowed: %s", err)
+	}
+
+	if !manageAny {
+		filter.WithUserID(mid.GetSubjectID(ctx))
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, orderbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	ords, err := a.orderBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.orderBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppOrders(ords), total, page)
+}
+
+func (a *app) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	ord, err := a.queryOwnedByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	return toAppOrder(ord)
+}
+
+func (a *app) queryStock(ctx context.Context, r *http.Request) web.Encoder {
+	productID, err := uuid.Parse(web.Param(r, "product_id"))
+	if err != nil {
+		return errs.NewFieldErrors("product_id", err)
+	}
+
+	stock, err := a.orderBus.QueryStock(ctx, productID)
+	if err != nil {
+		if errors.Is(err, productbus.ErrNotFound) {
+			return errs.New(errs.NotFound, productbus.ErrNotFound)
+		}
+		return errs.Newf(errs.Internal, "querystock: productID[%s]: %s", productID, err)
+	}
+
+	return toAppStock(stock)
+}
+
+func (a *app) setStock(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateStock
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	productID, err := uuid.Parse(web.Param(r, "product_id"))
+	if err != nil {
+		return errs.NewFieldErrors("product_id", err)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	stock, err := a.orderBus.SetStock(ctx, mid.GetSubjectID(ctx), productID, *app.Quantity)
+	if err != nil {
+		if errors.Is(err, productbus.ErrNotFound) {
+			return errs.New(errs.NotFound, productbus.ErrNotFound)
+		}
+		return writeError("setstock", productID, err)
+	}
+
+	return toAppStock(stock)
+}
+
+// =============================================================================
+
+// transitionFunc is one of the status changes provided by the order
+// business api.
+type transitionFunc func(bus orderbus.ExtBusiness, ctx context.Context, actorID uuid.UUID, ord orderbus.Order) (orderbus.Order, error)
+
+// transition reads the order inside the request transaction and applies
+// the specified status change to it.
+func (a *app) transition(ctx context.Context, r *http.Request, op string, fn transitionFunc) web.Encoder {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ord, err := a.queryOwnedByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	updOrd, err := fn(a.orderBus, ctx, mid.GetSubjectID(ctx), ord)
+	if err != nil {
+		return writeError(op, ord.ID, err)
+	}
+
+	return toAppOrder(updOrd)
+}
+
+func (a *app) queryByParam(ctx context.Context, r *http.Request) (orderbus.Order, error) {
+	id, err := uuid.Parse(web.Param(r, "order_id"))
+	if err != nil {
+		return orderbus.Order{}, errs.NewFieldErrors("order_id", err)
+	}
+
+	ord, err := a.orderBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, orderbus.ErrNotFound) {
+			return orderbus.Order{}, errs.New(errs.NotFound, err)
+		}
+		return orderbus.Order{}, errs.New(errs.Internal, fmt.Errorf("querybyid: orderID[%s]: %w", id, err))
+	}
+
+	return ord, nil
+}
+
+// queryOwnedByParam returns the order in the request if it was placed by
+// the subject or a role of the subject lets them manage any order.
+func (a *app) queryOwnedByParam(ctx context.Context, r *http.Request) (orderbus.Order, error) {
+	ord, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	if ord.UserID == mid.GetSubjectID(ctx) {
+		return ord, nil
+	}
+
+	manageAny, err := mid.Allowed(ctx, a.authClient, auth.RuleManageAnyOrder)
+	if err != nil {
+		return orderbus.Order{}, errs.Newf(errs.Internal, "allowed: %s", err)
+	}
+
+	if !manageAny {
+		return orderbus.Order{}, errs.New(errs.PermissionDenied, ErrNotOwner)
+	}
+
+	return ord, nil
+}
+
+// writeError maps the errors returned when writing an order to the
+// response for the client.
+func writeError(op string, id uuid.UUID, err error) *errs.Error {
+	switch {
+	case errors.Is(err, orderbus.ErrNoLines), errors.Is(err, orderbus.ErrInvalidQuantity):
+		return errs.New(errs.InvalidArgument, err)
+	case errors.Is(err, orderbus.ErrInsufficientStock), errors.Is(err, orderbus.ErrInvalidTransition), errors.Is(err, money.ErrCurrencyMismatch):
+		return errs.New(errs.FailedPrecondition, err)
+	case errors.Is(err, productbus.ErrNotFound):
+		return errs.New(errs.FailedPrecondition, productbus.ErrNotFound)
+	}
+
+	return errs.Newf(errs.Internal, "%s: id[%s]: %s", op, id, err)
+}
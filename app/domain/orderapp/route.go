@@ -0,0 +1,45 @@
+package orderapp
+
+import (
+	"database/sql"
+	"net/http"
+	"service/app/sdk/auth"
+	"service/app/sdk/authclient"
+	"service/app/sdk/mid"
+	"service/business/domain/orderbus"
+	"service/business/sdk/sqldb"
+	"service/foundation/logger"
+	"service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	OrderBus   orderbus.ExtBusiness
+	AuthClient *authclient.Client
+	Beginner   sqldb.Beginner
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	tenant := mid.Tenant()
+	ruleAny := mid.Authorize(cfg.AuthClient, auth.RuleAny)
+	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+
+	// Stock reservations read and write the same inventory rows, so they
+	// run serializable and conflicting requests are retried.
+	transaction := mid.BeginCommitRollback(cfg.Log, cfg.Beginner, mid.WithIsolation(sql.LevelSerializable), mid.WithRetries(3))
+
+	api := newApp(cfg.OrderBus, cfg.AuthClient)
+	app.HandleFunc(http.MethodGet, version, "/orders", api.query, authen, tenant, ruleAny)
+	app.HandleFunc(http.MethodGet, version, "/orders/{order_id}", api.queryByID, authen, tenant, ruleAny)
+	app.HandleFunc(http.MethodPost, version, "/orders", api.create, authen, tenant, ruleAny, transaction)
+	app.HandleFunc(http.MethodPost, version, "/orders/{order_id}/pay", api.pay, authen, tenant, ruleAny, transaction)
+	app.HandleFunc(http.MethodPost, version, "/orders/{order_id}/ship", api.ship, authen, tenant, ruleAdmin, transaction)
+	app.HandleFunc(http.MethodPost, version, "/orders/{order_id}/cancel", api.cancel, authen, tenant, ruleAny, transaction)
+	app.HandleFunc(http.MethodGet, version, "/inventory/{product_id}", api.queryStock, authen, tenant, ruleAny)
+	app.HandleFunc(http.MethodPut, version, "/inventory/{product_id}", api.setStock, authen, tenant, ruleAdmin, transaction)
+}
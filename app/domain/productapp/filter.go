@@ -0,0 +1,104 @@
+package productapp
+
+import (
+	"net/http"
+	"service/app/sdk/errs"
+	"service/business/domain/productbus"
+	"service/business/types/money"
+	"service/business/types/name"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page     string
+	Rows     string
+	OrderBy  string
+	ID       string
+	UserID   string
+	Name     string
+	Cost     string
+	Currency string
+	Quantity string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:     values.Get("page"),
+		Rows:     values.Get("rows"),
+		OrderBy:  values.Get("orderBy"),
+		ID:       values.Get("product_id"),
+		UserID:   values.Get("user_id"),
+		Name:     values.Get("name"),
+		Cost:     values.Get("cost"),
+		Currency: values.Get("currency"),
+		Quantity: values.Get("quantity"),
+	}
+
+	return filter
+}
+
+func parseFilter(qp queryParams) (productbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter productbus.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		switch err {
+		case nil:
+			filter.ID = &id
+		default:
+			fieldErrors.Add("product_id", err)
+		}
+	}
+
+	if qp.UserID != "" {
+		id, err := uuid.Parse(qp.UserID)
+		switch err {
+		case nil:
+			filter.UserID = &id
+		default:
+			fieldErrors.Add("user_id", err)
+		}
+	}
+
+	if qp.Name != "" {
+		nme, err := name.Parse(qp.Name)
+		switch err {
+		case nil:
+			n := nme.String()
+			filter.Name = &n
+		default:
+			fieldErrors.Add("name", err)
+		}
+	}
+
+	if qp.Cost != "" {
+		cst, err := money.Parse(qp.Cost, qp.Currency)
+		switch err {
+		case nil:
+			filter.Cost = &cst
+		default:
+			fieldErrors.Add("cost", err)
+		}
+	}
+
+	if qp.Quantity != "" {
+		qua, err := strconv.Atoi(qp.Quantity)
+		switch err {
+		case nil:
+			filter.Quantity = &qua
+		default:
+			fieldErrors.Add("quantity", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return productbus.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,152 @@
+package productapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"service/app/sdk/errs"
+	"service/business/domain/productbus"
+	"service/business/types/money"
+	"service/business/types/name"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product represents information about an individual product.
+type Product struct {
+	ID          string `json:"id"`
+	OrgID       string `json:"orgID"`
+	UserID      string `json:"userID"`
+	Name        string `json:"name"`
+	Cost        string `json:"cost"`
+	Currency    string `json:"currency"`
+	Quantity    int    `json:"quantity"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Product) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppProduct(prd productbus.Product) Product {
+	return Product{
+		ID:          prd.ID.String(),
+		OrgID:       prd.OrgID.String(),
+		UserID:      prd.UserID.String(),
+		Name:        prd.Name.String(),
+		Cost:        prd.Cost.Decimal(),
+		Currency:    prd.Cost.Currency().String(),
+		Quantity:    prd.Quantity,
+		DateCreated: prd.DateCreated.Format(time.RFC3339),
+		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppProducts(prds []productbus.Product) []Product {
+	app := make([]Product, len(prds))
+	for i, prd := range prds {
+		app[i] = toAppProduct(prd)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewProduct defines the data needed to add a new product.
+type NewProduct struct {
+	Name     string `json:"name" validate:"required"`
+	Cost     string `json:"cost" validate:"required"`
+	Currency string `json:"currency" validate:"required"`
+	Quantity int    `json:"quantity" validate:"gte=0"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewProduct) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewProduct) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewProduct(userID uuid.UUID, app NewProduct, nameRules name.Rules) (productbus.NewProduct, error) {
+	nme, err := nameRules.Parse(app.Name)
+	if err != nil {
+		return productbus.NewProduct{}, fmt.Errorf("parse: %w", err)
+	}
+
+	cost, err := money.Parse(app.Cost, app.Currency)
+	if err != nil {
+		return productbus.NewProduct{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := productbus.NewProduct{
+		UserID:   userID,
+		Name:     nme,
+		Cost:     cost,
+		Quantity: app.Quantity,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// UpdateProduct defines the data needed to update a product.
+type UpdateProduct struct {
+	Name     *string `json:"name"`
+	Cost     *string `json:"cost" validate:"required_with=Currency"`
+	Currency *string `json:"currency" validate:"required_with=Cost"`
+	Quantity *int    `json:"quantity" validate:"omitempty,gte=0"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateProduct) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateProduct) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateProduct(app UpdateProduct, nameRules name.Rules) (productbus.UpdateProduct, error) {
+	var nme *name.Name
+	if app.Name != nil {
+		nm, err := nameRules.Parse(*app.Name)
+		if err != nil {
+			return productbus.UpdateProduct{}, fmt.Errorf("parse: %w", err)
+		}
+		nme = &nm
+	}
+
+	var cost *money.Money
+	if app.Cost != nil && app.Currency != nil {
+		cst, err := money.Parse(*app.Cost, *app.Currency)
+		if err != nil {
+			return productbus.UpdateProduct{}, fmt.Errorf("parse: %w", err)
+		}
+		cost = &cst
+	}
+
+	bus := productbus.UpdateProduct{
+		Name:     nme,
+		Cost:     cost,
+		Quantity: app.Quantity,
+	}
+
+	return bus, nil
+}
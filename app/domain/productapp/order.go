@@ -0,0 +1,14 @@
+package productapp
+
+import (
+	"service/business/domain/productbus"
+)
+
+var orderByFields = map[string]string{
+	"product_id":   productbus.OrderByID,
+	"user_id":      productbus.OrderByUserID,
+	"name":         productbus.OrderByName,
+	"cost":         productbus.OrderByCost,
+	"quantity":     productbus.OrderByQuantity,
+	"date_created": productbus.OrderByDateCreated,
+}
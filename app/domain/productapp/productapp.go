@@ -0,0 +1,191 @@
+// Package productapp maintains the app layer api for the product domain.
+package productapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"service/app/sdk/auth"
+	"service/app/sdk/authclient"
+	"service/app/sdk/errs"
+	"service/app/sdk/mid"
+	"service/app/sdk/query"
+	"service/business/domain/productbus"
+	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/types/name"
+	"service/foundation/web"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotOwner is returned when a user changes a product they don't own.
+var ErrNotOwner = errors.New("product is owned by a different user")
+
+type app struct {
+	productBus productbus.ExtBusiness
+	authClient *authclient.Client
+	nameRules  name.Rules
+}
+
+func newApp(productBus productbus.ExtBusiness, authClient *authclient.Client, nameRules name.Rules) *app {
+	return &app{
+		productBus: productBus,
+		authClient: authClient,
+		nameRules:  nameRules,
+	}
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewProduct
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	np, err := toBusNewProduct(mid.GetSubjectID(ctx), app, a.nameRules)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	prd, err := a.productBus.Create(ctx, mid.GetSubjectID(ctx), np)
+	if err != nil {
+		return writeError("create", np.UserID, err)
+	}
+
+	return toAppProduct(prd)
+}
+
+func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateProduct
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	up, err := toBusUpdateProduct(app, a.nameRules)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	prd, err := a.queryOwnedByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	updPrd, err := a.productBus.Update(ctx, mid.GetSubjectID(ctx), prd, up)
+	if err != nil {
+		return writeError("update", prd.ID, err)
+	}
+
+	return toAppProduct(updPrd)
+}
+
+func (a *app) delete(ctx context.Context, r *http.Request) web.Encoder {
+	prd, err := a.queryOwnedByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	if err := a.productBus.Delete(ctx, mid.GetSubjectID(ctx), prd); err != nil {
+		return errs.Newf(errs.Internal, "delete: productID[%s]: %s", prd.ID, err)
+	}
+
+	return nil
+}
+
+func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, productbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	prds, err := a.productBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.productBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppProducts(prds), total, page)
+}
+
+func (a *app) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	prd, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	return toAppProduct(prd)
+}
+
+func (a *app) queryByParam(ctx context.Context, r *http.Request) (productbus.Product, error) {
+	id, err := uuid.Parse(web.Param(r, "product_id"))
+	if err != nil {
+		return productbus.Product{}, errs.NewFieldErrors("product_id", err)
+	}
+
+	prd, err := a.productBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, productbus.ErrNotFound) {
+			return productbus.Product{}, errs.New(errs.NotFound, err)
+		}
+		return productbus.Product{}, errs.New(errs.Internal, fmt.Errorf("querybyid: productID[%s]: %w", id, err))
+	}
+
+	return prd, nil
+}
+
+// queryOwnedByParam returns the product in the request if it is owned by
+// the subject or a role of the subject lets them manage any product.
+func (a *app) queryOwnedByParam(ctx context.Context, r *http.Request) (productbus.Product, error) {
+	prd, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return productbus.Product{}, err
+	}
+
+	if prd.UserID == mid.GetSubjectID(ctx) {
+		return prd, nil
+	}
+
+	manageAny, err := mid.Allowed(ctx, a.authClient, auth.RuleManageAnyProduct)
+	if err != nil {
+		return productbus.Product{}, errs.Newf(errs.Internal, "allowed: %s", err)
+	}
+
+	if !manageAny {
+		return productbus.Product{}, errs.New(errs.PermissionDenied, ErrNotOwner)
+	}
+
+	return prd, nil
+}
+
+// writeError maps the errors returned when writing a product to the
+// response for the client.
+func writeError(op string, id uuid.UUID, err error) *errs.Error {
+	switch {
+	case errors.Is(err, productbus.ErrInvalidCost), errors.Is(err, productbus.ErrInvalidQty):
+		return errs.New(errs.InvalidArgument, err)
+	case errors.Is(err, productbus.ErrUserDisabled):
+		return errs.New(errs.FailedPrecondition, productbus.ErrUserDisabled)
+	case errors.Is(err, userbus.ErrNotFound):
+		return errs.New(errs.FailedPrecondition, userbus.ErrNotFound)
+	}
+
+	return errs.Newf(errs.Internal, "%s: id[%s]: %s", op, id, err)
+}
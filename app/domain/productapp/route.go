@@ -0,0 +1,36 @@
+package productapp
+
+import (
+	"net/http"
+	"service/app/sdk/auth"
+	"service/app/sdk/authclient"
+	"service/app/sdk/mid"
+	"service/business/domain/productbus"
+	"service/business/types/name"
+	"service/foundation/logger"
+	"service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	ProductBus productbus.ExtBusiness
+	AuthClient *authclient.Client
+	NameRules  name.Rules
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	tenant := mid.Tenant()
+	ruleAny := mid.Authorize(cfg.AuthClient, auth.RuleAny)
+
+	api := newApp(cfg.ProductBus, cfg.AuthClient, cfg.NameRules)
+	app.HandleFunc(http.MethodGet, version, "/products", api.query, authen, tenant, ruleAny)
+	app.HandleFunc(http.MethodGet, version, "/products/{product_id}", api.queryByID, authen, tenant, ruleAny)
+	app.HandleFunc(http.MethodPost, version, "/products", api.create, authen, tenant, ruleAny)
+	app.HandleFunc(http.MethodPut, version, "/products/{product_id}", api.update, authen, tenant, ruleAny)
+	app.HandleFunc(http.MethodDelete, version, "/products/{product_id}", api.delete, authen, tenant, ruleAny)
+}
@@ -0,0 +1,147 @@
+package roleapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"service/app/sdk/errs"
+	"service/business/domain/rolebus"
+	"service/business/types/role"
+	"time"
+)
+
+// Role represents information about a role and the permissions it grants.
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	Inherits    []string `json:"inherits"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Role) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppRole(rl rolebus.Role) Role {
+	permissions := rl.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return Role{
+		Name:        rl.Name.String(),
+		Description: rl.Description,
+		Permissions: permissions,
+		Inherits:    role.ParseToString(rl.Inherits),
+		DateCreated: rl.DateCreated.Format(time.RFC3339),
+		DateUpdated: rl.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// Roles represents the set of roles in the system.
+type Roles []Role
+
+// Encode implements the encoder interface.
+func (app Roles) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppRoles(rls []rolebus.Role) Roles {
+	app := make(Roles, len(rls))
+	for i, rl := range rls {
+		app[i] = toAppRole(rl)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewRole defines the data needed to add a new role.
+type NewRole struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	Inherits    []string `json:"inherits"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewRole) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewRole) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewRole(app NewRole) (rolebus.NewRole, error) {
+	name, err := role.Parse(app.Name)
+	if err != nil {
+		return rolebus.NewRole{}, fmt.Errorf("parse: %w", err)
+	}
+
+	inherits, err := role.ParseMany(app.Inherits)
+	if err != nil {
+		return rolebus.NewRole{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := rolebus.NewRole{
+		Name:        name,
+		Description: app.Description,
+		Permissions: app.Permissions,
+		Inherits:    inherits,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// UpdateRole defines the data needed to update a role.
+type UpdateRole struct {
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+	Inherits    []string `json:"inherits"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateRole) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateRole) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateRole(app UpdateRole) (rolebus.UpdateRole, error) {
+	var inherits []role.Role
+	if app.Inherits != nil {
+		var err error
+		inherits, err = role.ParseMany(app.Inherits)
+		if err != nil {
+			return rolebus.UpdateRole{}, fmt.Errorf("parse: %w", err)
+		}
+	}
+
+	bus := rolebus.UpdateRole{
+		Description: app.Description,
+		Permissions: app.Permissions,
+		Inherits:    inherits,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,129 @@
+// Package roleapp maintains the app layer api for the role domain.
+package roleapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"service/app/sdk/errs"
+	"service/business/domain/rolebus"
+	"service/business/types/role"
+	"service/foundation/web"
+)
+
+type app struct {
+	roleBus *rolebus.Business
+}
+
+func newApp(roleBus *rolebus.Business) *app {
+	return &app{
+		roleBus: roleBus,
+	}
+}
+
+func (a *app) create(ctx context.Context, r *http.Request) web.Encoder {
+	var app NewRole
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	nr, err := toBusNewRole(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rl, err := a.roleBus.Create(ctx, nr)
+	if err != nil {
+		return writeError("create", nr.Name, err)
+	}
+
+	return toAppRole(rl)
+}
+
+func (a *app) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateRole
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ur, err := toBusUpdateRole(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rl, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	updRl, err := a.roleBus.Update(ctx, rl, ur)
+	if err != nil {
+		return writeError("update", rl.Name, err)
+	}
+
+	return toAppRole(updRl)
+}
+
+func (a *app) delete(ctx context.Context, r *http.Request) web.Encoder {
+	rl, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	if err := a.roleBus.Delete(ctx, rl); err != nil {
+		return writeError("delete", rl.Name, err)
+	}
+
+	return nil
+}
+
+func (a *app) query(ctx context.Context, r *http.Request) web.Encoder {
+	rls, err := a.roleBus.QueryAll(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	return toAppRoles(rls)
+}
+
+func (a *app) queryByName(ctx context.Context, r *http.Request) web.Encoder {
+	rl, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	return toAppRole(rl)
+}
+
+func (a *app) queryByParam(ctx context.Context, r *http.Request) (rolebus.Role, error) {
+	name, err := role.Parse(web.Param(r, "role"))
+	if err != nil {
+		return rolebus.Role{}, errs.NewFieldErrors("role", err)
+	}
+
+	rl, err := a.roleBus.QueryByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, rolebus.ErrNotFound) {
+			return rolebus.Role{}, errs.New(errs.NotFound, err)
+		}
+		return rolebus.Role{}, errs.New(errs.Internal, fmt.Errorf("querybyname: role[%s]: %w", name, err))
+	}
+
+	return rl, nil
+}
+
+// writeError maps the errors returned when writing a role to the response
+// for the client.
+func writeError(op string, name role.Role, err error) *errs.Error {
+	switch {
+	case errors.Is(err, rolebus.ErrUniqueName):
+		return errs.New(errs.AlreadyExists, rolebus.ErrUniqueName)
+	case errors.Is(err, rolebus.ErrInvalidPermission), errors.Is(err, rolebus.ErrUnknownRole):
+		return errs.New(errs.InvalidArgument, err)
+	case errors.Is(err, rolebus.ErrCycle), errors.Is(err, rolebus.ErrBuiltIn), errors.Is(err, rolebus.ErrInUse):
+		return errs.New(errs.FailedPrecondition, err)
+	}
+
+	return errs.Newf(errs.Internal, "%s: role[%s]: %s", op, name, err)
+}
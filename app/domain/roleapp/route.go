@@ -0,0 +1,33 @@
+package roleapp
+
+import (
+	"net/http"
+	"service/app/sdk/auth"
+	"service/app/sdk/authclient"
+	"service/app/sdk/mid"
+	"service/business/domain/rolebus"
+	"service/foundation/logger"
+	"service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log        *logger.Logger
+	RoleBus    *rolebus.Business
+	AuthClient *authclient.Client
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	authen := mid.Authenticate(cfg.AuthClient)
+	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
+
+	api := newApp(cfg.RoleBus)
+	app.HandleFunc(http.MethodGet, version, "/roles", api.query, authen, ruleAdmin)
+	app.HandleFunc(http.MethodGet, version, "/roles/{role}", api.queryByName, authen, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/roles", api.create, authen, ruleAdmin)
+	app.HandleFunc(http.MethodPut, version, "/roles/{role}", api.update, authen, ruleAdmin)
+	app.HandleFunc(http.MethodDelete, version, "/roles/{role}", api.delete, authen, ruleAdmin)
+}
@@ -0,0 +1,528 @@
+package userapp
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/name"
+	"service/business/types/role"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of formats supported for bulk import and export.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// csvRoleSep separates the roles held in the single roles column of a
+// CSV file.
+const csvRoleSep = ";"
+
+// pageSize is the number of users read from the store per page while
+// streaming an export or checking the emails of an import.
+const pageSize = 100
+
+// ParseFormat validates the specified format, falling back to the
+// content type when no format is provided.
+func ParseFormat(format string, contentType string) (string, error) {
+	if format == "" {
+		switch {
+		case strings.Contains(contentType, "text/csv"):
+			return FormatCSV, nil
+		case strings.Contains(contentType, "ndjson"):
+			return FormatNDJSON, nil
+		}
+		return "", errors.New("format must be specified")
+	}
+
+	switch f := strings.ToLower(format); f {
+	case FormatCSV, FormatNDJSON:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unknown format %q", format)
+}
+
+// =============================================================================
+
+// ImportConfig defines how a bulk import is executed.
+type ImportConfig struct {
+	Format string
+
+	// DryRun validates every row and reports the errors without writing
+	// any users.
+	DryRun bool
+
+	// ChunkSize is the number of users created per transaction. A value of
+	// zero creates all the users inside a single transaction.
+	ChunkSize int
+
+	// MaxRows is the maximum number of rows accepted. A value of zero
+	// means there is no limit.
+	MaxRows int
+
+	// NameRules are the rules the names of the users must comply with.
+	NameRules name.Rules
+
+	// CheckRoles validates that the roles of a row exist. A nil value
+	// accepts any well formed role.
+	CheckRoles func(ctx context.Context, roles []role.Role) error
+}
+
+// ImportError describes why a row in the import could not be accepted.
+type ImportError struct {
+	Row   int    `json:"row"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport describes the outcome of a bulk import.
+type ImportReport struct {
+	Total   int           `json:"total"`
+	Valid   int           `json:"valid"`
+	Created int           `json:"created"`
+	DryRun  bool          `json:"dryRun"`
+	Errors  []ImportError `json:"errors"`
+}
+
+// Encode implements the encoder interface.
+func (app ImportReport) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Import reads the users from r in the configured format and validates
+// every row before anything is written. If any row fails validation or the
+// import is a dry run, no users are created and the report lists the row
+// errors. Otherwise the users are created in chunks, each chunk inside its
+// own transaction.
+func Import(ctx context.Context, userBus userbus.ExtBusiness, beginner sqldb.Beginner, actorID uuid.UUID, r io.Reader, cfg ImportConfig) (ImportReport, error) {
+	rows, err := newRowReader(r, cfg.Format)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	report := ImportReport{
+		DryRun: cfg.DryRun,
+		Errors: []ImportError{},
+	}
+
+	var valid []importRow
+	emails := make(map[string]int)
+
+	for {
+		row, app, err := rows.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			var rowErr *rowError
+			if !errors.As(err, &rowErr) {
+				return ImportReport{}, fmt.Errorf("read: %w", err)
+			}
+		}
+
+		report.Total++
+		if cfg.MaxRows > 0 && report.Total > cfg.MaxRows {
+			return ImportReport{}, fmt.Errorf("import exceeds the maximum of %d rows", cfg.MaxRows)
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, ImportError{Row: row, Error: err.Error()})
+			continue
+		}
+
+		nu, err := validateRow(ctx, cfg, app, emails, row)
+		if err != nil {
+			report.Errors = append(report.Errors, ImportError{Row: row, Email: app.Email, Error: err.Error()})
+			continue
+		}
+
+		valid = append(valid, importRow{row: row, email: app.Email, nu: nu})
+	}
+
+	chunkSize := cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = len(valid)
+	}
+
+	// The emails already taken are checked with one query per chunk rather
+	// than one per row.
+	var nus []userbus.NewUser
+	for start := 0; start < len(valid); start += chunkSize {
+		chunk := valid[start:min(start+chunkSize, len(valid))]
+
+		taken, err := takenEmails(ctx, userBus, chunk)
+		if err != nil {
+			return ImportReport{}, fmt.Errorf("emails: rows[%d-%d]: %w", chunk[0].row, chunk[len(chunk)-1].row, err)
+		}
+
+		for _, ir := range chunk {
+			if taken[ir.nu.Email.Address] {
+				report.Errors = append(report.Errors, ImportError{Row: ir.row, Email: ir.email, Error: userbus.ErrUniqueEmail.Error()})
+				continue
+			}
+
+			nus = append(nus, ir.nu)
+		}
+	}
+
+	slices.SortStableFunc(report.Errors, func(a, b ImportError) int {
+		return cmp.Compare(a.Row, b.Row)
+	})
+
+	report.Valid = len(nus)
+
+	if cfg.DryRun || len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	for start := 0; start < len(nus); start += chunkSize {
+		end := min(start+chunkSize, len(nus))
+
+		if err := createChunk(ctx, userBus, beginner, actorID, nus[start:end]); err != nil {
+			return report, fmt.Errorf("create: rows[%d-%d]: %w", start+1, end, err)
+		}
+
+		report.Created = end
+	}
+
+	return report, nil
+}
+
+// importRow represents a row that passed validation and still needs its
+// email checked against the existing users.
+type importRow struct {
+	row   int
+	email string
+	nu    userbus.NewUser
+}
+
+func validateRow(ctx context.Context, cfg ImportConfig, app NewUser, emails map[string]int, row int) (userbus.NewUser, error) {
+	if app.PasswordConfirm == "" {
+		app.PasswordConfirm = app.Password
+	}
+
+	if err := app.Validate(); err != nil {
+		return userbus.NewUser{}, err
+	}
+
+	nu, err := toBusNewUser(app, cfg.NameRules)
+	if err != nil {
+		return userbus.NewUser{}, err
+	}
+
+	if cfg.CheckRoles != nil {
+		if err := cfg.CheckRoles(ctx, nu.Roles); err != nil {
+			return userbus.NewUser{}, err
+		}
+	}
+
+	key := strings.ToLower(nu.Email.Address)
+	if prev, exists := emails[key]; exists {
+		return userbus.NewUser{}, fmt.Errorf("email duplicates row %d", prev)
+	}
+	emails[key] = row
+
+	return nu, nil
+}
+
+// takenEmails returns the emails of the rows already held by users. Emails
+// are only unique within an org and users imported without a tenant land
+// in the default one.
+func takenEmails(ctx context.Context, userBus userbus.ExtBusiness, rows []importRow) (map[string]bool, error) {
+	if _, ok := tenant.Get(ctx); !ok {
+		ctx = tenant.Set(ctx, tenant.DefaultOrgID)
+	}
+
+	emails := make([]mail.Address, len(rows))
+	for i, ir := range rows {
+		emails[i] = ir.nu.Email
+	}
+
+	var filter userbus.QueryFilter
+	filter.WithEmails(emails)
+
+	taken := make(map[string]bool)
+	for pageNumber := 1; ; pageNumber++ {
+		pg, err := page.Parse(strconv.Itoa(pageNumber), strconv.Itoa(pageSize))
+		if err != nil {
+			return nil, fmt.Errorf("page: %w", err)
+		}
+
+		usrs, err := userBus.Query(ctx, filter, userbus.DefaultOrderBy, pg)
+		if err != nil {
+			return nil, fmt.Errorf("query: page[%d]: %w", pageNumber, err)
+		}
+
+		for _, usr := range usrs {
+			taken[usr.Email.Address] = true
+		}
+
+		if len(usrs) < pageSize {
+			return taken, nil
+		}
+	}
+}
+
+func createChunk(ctx context.Context, userBus userbus.ExtBusiness, beginner sqldb.Beginner, actorID uuid.UUID, nus []userbus.NewUser) error {
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	bus, err := userBus.NewWithTx(tx)
+	if err != nil {
+		return fmt.Errorf("newwithtx: %w", err)
+	}
+
+	for _, nu := range nus {
+		if _, err := bus.Create(ctx, actorID, nu); err != nil {
+			return fmt.Errorf("email[%s]: %w", nu.Email.Address, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// rowError represents a row that could not be decoded. The import can
+// continue reading past it.
+type rowError struct {
+	err error
+}
+
+func (re *rowError) Error() string {
+	return re.err.Error()
+}
+
+type rowReader interface {
+	next() (int, NewUser, error)
+}
+
+func newRowReader(r io.Reader, format string) (rowReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		return newNDJSONReader(r), nil
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, col := range header {
+		columns[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+
+	for _, col := range []string{"name", "email", "roles", "password"} {
+		if _, exists := columns[col]; !exists {
+			return nil, fmt.Errorf("header is missing the %q column", col)
+		}
+	}
+
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (cr *csvReader) next() (int, NewUser, error) {
+	record, err := cr.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, NewUser{}, io.EOF
+		}
+
+		cr.row++
+
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			return cr.row, NewUser{}, &rowError{err: err}
+		}
+		return cr.row, NewUser{}, err
+	}
+
+	cr.row++
+
+	field := func(col string) string {
+		i, exists := cr.columns[col]
+		if !exists || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var roles []string
+	if v := field("roles"); v != "" {
+		for role := range strings.SplitSeq(v, csvRoleSep) {
+			roles = append(roles, strings.TrimSpace(role))
+		}
+	}
+
+	app := NewUser{
+		Name:       field("name"),
+		Email:      field("email"),
+		Roles:      roles,
+		Department: field("department"),
+		Password:   field("password"),
+	}
+
+	return cr.row, app, nil
+}
+
+type ndjsonReader struct {
+	s   *bufio.Scanner
+	row int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	return &ndjsonReader{s: s}
+}
+
+func (nr *ndjsonReader) next() (int, NewUser, error) {
+	for nr.s.Scan() {
+		line := strings.TrimSpace(nr.s.Text())
+		if line == "" {
+			continue
+		}
+
+		nr.row++
+
+		var app NewUser
+		if err := json.Unmarshal([]byte(line), &app); err != nil {
+			return nr.row, NewUser{}, &rowError{err: err}
+		}
+
+		return nr.row, app, nil
+	}
+
+	if err := nr.s.Err(); err != nil {
+		return nr.row, NewUser{}, err
+	}
+
+	return 0, NewUser{}, io.EOF
+}
+
+// =============================================================================
+
+// Export streams every user matching the filter to w in the specified
+// format. It returns the number of users written.
+func Export(ctx context.Context, userBus userbus.ExtBusiness, w io.Writer, format string, filter userbus.QueryFilter, orderBy order.By) (int, error) {
+	var write func(usr User) error
+	var flush func() error
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		header := []string{"id", "name", "email", "roles", "department", "enabled", "date_created", "date_updated", "date_deleted"}
+		if err := cw.Write(header); err != nil {
+			return 0, fmt.Errorf("write header: %w", err)
+		}
+
+		write = func(usr User) error {
+			return cw.Write([]string{
+				usr.ID,
+				usr.Name,
+				usr.Email,
+				strings.Join(usr.Roles, csvRoleSep),
+				usr.Department,
+				strconv.FormatBool(usr.Enabled),
+				usr.DateCreated,
+				usr.DateUpdated,
+				usr.DateDeleted,
+			})
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		write = func(usr User) error {
+			return enc.Encode(usr)
+		}
+		flush = func() error {
+			return nil
+		}
+
+	default:
+		return 0, fmt.Errorf("unknown format %q", format)
+	}
+
+	var total int
+	for pageNumber := 1; ; pageNumber++ {
+		pg, err := page.Parse(strconv.Itoa(pageNumber), strconv.Itoa(pageSize))
+		if err != nil {
+			return total, fmt.Errorf("page: %w", err)
+		}
+
+		usrs, err := userBus.Query(ctx, filter, orderBy, pg)
+		if err != nil {
+			return total, fmt.Errorf("query: page[%d]: %w", pageNumber, err)
+		}
+
+		for _, usr := range usrs {
+			if err := write(toAppUser(usr)); err != nil {
+				return total, fmt.Errorf("write: userID[%s]: %w", usr.ID, err)
+			}
+			total++
+		}
+
+		if err := flush(); err != nil {
+			return total, fmt.Errorf("flush: %w", err)
+		}
+
+		if len(usrs) < pageSize {
+			return total, nil
+		}
+	}
+}
+
+// exportFileName returns the file name suggested to the client for an
+// export in the specified format.
+func exportFileName(format string) string {
+	return fmt.Sprintf("users-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+}
@@ -0,0 +1,51 @@
+package userapp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"service/app/sdk/errs"
+	"service/business/domain/userbus"
+	"strconv"
+	"strings"
+)
+
+// etag formats the version of a user as a strong entity tag.
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// checkIfMatch honors the If-Match header of the request. A request without
+// the header is allowed through; otherwise the header must list the current
+// version of the user or be a wildcard. If-Match uses the strong comparison,
+// so a weak entity tag never matches.
+func checkIfMatch(r *http.Request, usr userbus.User) error {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return nil
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil
+		}
+
+		weak := strings.HasPrefix(tag, "W/")
+
+		value, err := strconv.Unquote(strings.TrimPrefix(tag, "W/"))
+		if err != nil {
+			return errs.NewFieldErrors("If-Match", fmt.Errorf("invalid entity tag %s", tag))
+		}
+
+		if weak {
+			continue
+		}
+
+		if version, err := strconv.Atoi(value); err == nil && version == usr.Version {
+			return nil
+		}
+	}
+
+	return errs.New(errs.PreconditionFailed, errors.New("user does not match the If-Match version"))
+}
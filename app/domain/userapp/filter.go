@@ -0,0 +1,115 @@
+package userapp
+
+import (
+	"net/http"
+	"net/mail"
+	"service/app/sdk/errs"
+	"service/business/domain/userbus"
+	"service/business/types/name"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	ID               string
+	Name             string
+	Email            string
+	StartCreatedDate string
+	EndCreatedDate   string
+	IncludeDeleted   string
+}
+
+func parseQueryParams(r *http.Request) queryParams {
+	values := r.URL.Query()
+
+	filter := queryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		ID:               values.Get("user_id"),
+		Name:             values.Get("name"),
+		Email:            values.Get("email"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+		IncludeDeleted:   values.Get("include_deleted"),
+	}
+
+	return filter
+}
+
+func parseFilter(qp queryParams) (userbus.QueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter userbus.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		switch err {
+		case nil:
+			filter.ID = &id
+		default:
+			fieldErrors.Add("user_id", err)
+		}
+	}
+
+	if qp.Name != "" {
+		nme, err := name.Parse(qp.Name)
+		switch err {
+		case nil:
+			n := nme.String()
+			filter.Name = &n
+		default:
+			fieldErrors.Add("name", err)
+		}
+	}
+
+	if qp.Email != "" {
+		addr, err := mail.ParseAddress(qp.Email)
+		switch err {
+		case nil:
+			filter.Email = addr
+		default:
+			fieldErrors.Add("email", err)
+		}
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		switch err {
+		case nil:
+			filter.StartCreatedDate = &t
+		default:
+			fieldErrors.Add("start_created_date", err)
+		}
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		switch err {
+		case nil:
+			filter.EndCreatedDate = &t
+		default:
+			fieldErrors.Add("end_created_date", err)
+		}
+	}
+
+	if qp.IncludeDeleted != "" {
+		include, err := strconv.ParseBool(qp.IncludeDeleted)
+		switch err {
+		case nil:
+			filter.IncludeDeleted = &include
+		default:
+			fieldErrors.Add("include_deleted", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return userbus.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
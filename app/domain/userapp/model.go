@@ -3,6 +3,7 @@ package userapp
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/mail"
 	"service/app/sdk/errs"
 	"service/business/domain/userbus"
@@ -14,6 +15,7 @@ import (
 // User represents information about an individual user.
 type User struct {
 	ID           string   `json:"id"`
+	OrgID        string   `json:"orgID"`
 	Name         string   `json:"name"`
 	Email        string   `json:"email"`
 	Roles        []string `json:"roles"`
@@ -22,6 +24,8 @@ type User struct {
 	Enabled      bool     `json:"enabled"`
 	DateCreated  string   `json:"dateCreated"`
 	DateUpdated  string   `json:"dateUpdated"`
+	DateDeleted  string   `json:"dateDeleted,omitempty"`
+	Version      int      `json:"-"`
 }
 
 // Encode implements the encoder interface.
@@ -30,14 +34,30 @@ func (app User) Encode() ([]byte, string, error) {
 	return data, "application/json", err
 }
 
+// HTTPHeader implements the web package httpHeader interface so the
+// version of the user is returned as the ETag.
+func (app User) HTTPHeader() http.Header {
+	if app.Version == 0 {
+		return nil
+	}
+
+	return http.Header{"ETag": {etag(app.Version)}}
+}
+
 func toAppUser(usr userbus.User) User {
 	roles := make([]string, len(usr.Roles))
 	for i, role := range usr.Roles {
 		roles[i] = role.String()
 	}
 
+	var dateDeleted string
+	if usr.Deleted() {
+		dateDeleted = usr.DateDeleted.Format(time.RFC3339)
+	}
+
 	return User{
 		ID:           usr.ID.String(),
+		OrgID:        usr.OrgID.String(),
 		Name:         usr.Name.String(),
 		Email:        usr.Email.Address,
 		Roles:        roles,
@@ -46,6 +66,8 @@ func toAppUser(usr userbus.User) User {
 		Enabled:      usr.Enabled,
 		DateCreated:  usr.DateCreated.Format(time.RFC3339),
 		DateUpdated:  usr.DateUpdated.Format(time.RFC3339),
+		DateDeleted:  dateDeleted,
+		Version:      usr.Version,
 	}
 }
 
@@ -58,6 +80,38 @@ func toAppUsers(users []userbus.User) []User {
 	return app
 }
 
+// SearchHit represents a user matching a search.
+type SearchHit struct {
+	User       User       `json:"user"`
+	Rank       float64    `json:"rank"`
+	Highlights Highlights `json:"highlights"`
+}
+
+// Highlights holds the searched fields with the matching terms wrapped in
+// <mark> tags.
+type Highlights struct {
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+	Department string `json:"department,omitempty"`
+}
+
+func toAppSearchHits(hits []userbus.SearchHit) []SearchHit {
+	app := make([]SearchHit, len(hits))
+	for i, hit := range hits {
+		app[i] = SearchHit{
+			User: toAppUser(hit.User),
+			Rank: hit.Rank,
+			Highlights: Highlights{
+				Name:       hit.Highlights.Name,
+				Email:      hit.Highlights.Email,
+				Department: hit.Highlights.Department,
+			},
+		}
+	}
+
+	return app
+}
+
 // NewUser defines the data needed to add a new user.
 type NewUser struct {
 	Name            string   `json:"name" validate:"required"`
@@ -68,7 +122,7 @@ type NewUser struct {
 	PasswordConfirm string   `json:"passwordConfirm" validate:"eqfield=Password"`
 }
 
-func toBusNewUser(app NewUser) (userbus.NewUser, error) {
+func toBusNewUser(app NewUser, nameRules name.Rules) (userbus.NewUser, error) {
 	roles := make([]role.Role, len(app.Roles))
 	for i, roleStr := range app.Roles {
 		role, err := role.Parse(roleStr)
@@ -83,7 +137,7 @@ func toBusNewUser(app NewUser) (userbus.NewUser, error) {
 		return userbus.NewUser{}, fmt.Errorf("parse: %w", err)
 	}
 
-	nme, err := name.Parse(app.Name)
+	nme, err := nameRules.Parse(app.Name)
 	if err != nil {
 		return userbus.NewUser{}, fmt.Errorf("parse: %w", err)
 	}
@@ -112,3 +166,73 @@ func (app NewUser) Validate() error {
 
 	return nil
 }
+
+// =============================================================================
+
+// UpdateUser defines the data needed to update a user.
+type UpdateUser struct {
+	Name            *string  `json:"name"`
+	Email           *string  `json:"email" validate:"omitempty,email"`
+	Roles           []string `json:"roles"`
+	Department      *string  `json:"department"`
+	Password        *string  `json:"password"`
+	PasswordConfirm *string  `json:"passwordConfirm" validate:"omitempty,eqfield=Password"`
+	Enabled         *bool    `json:"enabled"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateUser) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app UpdateUser) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return errs.Newf(errs.FailedPrecondition, "validate: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateUser(app UpdateUser, nameRules name.Rules) (userbus.UpdateUser, error) {
+	var roles []role.Role
+	if app.Roles != nil {
+		roles = make([]role.Role, len(app.Roles))
+		for i, roleStr := range app.Roles {
+			role, err := role.Parse(roleStr)
+			if err != nil {
+				return userbus.UpdateUser{}, fmt.Errorf("parse: %w", err)
+			}
+			roles[i] = role
+		}
+	}
+
+	var addr *mail.Address
+	if app.Email != nil {
+		var err error
+		addr, err = mail.ParseAddress(*app.Email)
+		if err != nil {
+			return userbus.UpdateUser{}, fmt.Errorf("parse: %w", err)
+		}
+	}
+
+	var nme *name.Name
+	if app.Name != nil {
+		nm, err := nameRules.Parse(*app.Name)
+		if err != nil {
+			return userbus.UpdateUser{}, fmt.Errorf("parse: %w", err)
+		}
+		nme = &nm
+	}
+
+	bus := userbus.UpdateUser{
+		Name:       nme,
+		Email:      addr,
+		Roles:      roles,
+		Department: app.Department,
+		Password:   app.Password,
+		Enabled:    app.Enabled,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,14 @@
+package userapp
+
+import (
+	"service/business/domain/userbus"
+)
+
+var orderByFields = map[string]string{
+	"user_id":      userbus.OrderByID,
+	"name":         userbus.OrderByName,
+	"email":        userbus.OrderByEmail,
+	"roles":        userbus.OrderByRoles,
+	"enabled":      userbus.OrderByEnabled,
+	"date_created": userbus.OrderByDateCreated,
+}
@@ -5,25 +5,44 @@ import (
 	"service/app/sdk/auth"
 	"service/app/sdk/authclient"
 	"service/app/sdk/mid"
+	"service/business/domain/rolebus"
 	"service/business/domain/userbus"
+	"service/business/sdk/sqldb"
+	"service/business/types/name"
 	"service/foundation/logger"
 	"service/foundation/web"
+
+	"github.com/jmoiron/sqlx"
 )
 
 // Config contains all the mandatory systems required by handlers.
 type Config struct {
 	Log        *logger.Logger
+	DB         *sqlx.DB
 	UserBus    userbus.ExtBusiness
+	RoleBus    *rolebus.Business
 	AuthClient *authclient.Client
+	Beginner   sqldb.Beginner
+	NameRules  name.Rules
 }
 
 // Routes adds specific routes for this group.
 func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 	authen := mid.Authenticate(cfg.AuthClient)
+	tenant := mid.Tenant()
 	ruleAdmin := mid.Authorize(cfg.AuthClient, auth.RuleAdminOnly)
 
-	api := NewApp(cfg.UserBus)
-	app.HandleFunc(http.MethodPost, version, "/users", api.create, authen, ruleAdmin)
-
+	api := NewApp(cfg.Log, cfg.UserBus, cfg.RoleBus, cfg.Beginner, cfg.NameRules)
+	app.HandleFunc(http.MethodGet, version, "/users", api.query, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodGet, version, "/users/search", api.search, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodGet, version, "/users/export", api.export, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/users/import", api.importUsers, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodGet, version, "/users/{user_id}", api.queryByID, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/users", api.create, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPut, version, "/users/{user_id}", api.update, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodDelete, version, "/users/{user_id}", api.delete, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/users/{user_id}/restore", api.restore, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/users/{user_id}/deactivate", api.deactivate, authen, tenant, ruleAdmin)
+	app.HandleFunc(http.MethodPost, version, "/users/{user_id}/activate", api.activate, authen, tenant, ruleAdmin)
 }
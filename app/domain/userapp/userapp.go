@@ -4,24 +4,46 @@ package userapp
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"service/app/sdk/auth"
 	"service/app/sdk/errs"
 	"service/app/sdk/mid"
+	"service/app/sdk/query"
+	"service/business/domain/rolebus"
 	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/types/name"
+	"service/foundation/logger"
 	"service/foundation/web"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
 )
 
 // App manages the set of app layer api functions for the user domain.
 type App struct {
-	userBus userbus.ExtBusiness
-	auth    *auth.Auth
+	log       *logger.Logger
+	userBus   userbus.ExtBusiness
+	roleBus   *rolebus.Business
+	beginner  sqldb.Beginner
+	nameRules name.Rules
+	auth      *auth.Auth
 }
 
-// NewApp constructs a user app API for use.
-func NewApp(userBus userbus.ExtBusiness) *App {
+// NewApp constructs a user app API for use. The names of the users created
+// or updated must comply with the name rules.
+func NewApp(log *logger.Logger, userBus userbus.ExtBusiness, roleBus *rolebus.Business, beginner sqldb.Beginner, nameRules name.Rules) *App {
 	return &App{
-		userBus: userBus,
+		log:       log,
+		userBus:   userBus,
+		roleBus:   roleBus,
+		beginner:  beginner,
+		nameRules: nameRules,
 	}
 }
 
@@ -40,11 +62,15 @@ func (a *App) create(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	nc, err := toBusNewUser(app)
+	nc, err := toBusNewUser(app, a.nameRules)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
+	if err := a.roleBus.Check(ctx, nc.Roles); err != nil {
+		return checkRolesError(err)
+	}
+
 	usr, err := a.userBus.Create(ctx, mid.GetSubjectID(ctx), nc)
 	if err != nil {
 		if errors.Is(err, userbus.ErrUniqueEmail) {
@@ -55,3 +81,324 @@ func (a *App) create(ctx context.Context, r *http.Request) web.Encoder {
 
 	return toAppUser(usr)
 }
+
+func (a *App) update(ctx context.Context, r *http.Request) web.Encoder {
+	var app UpdateUser
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	uu, err := toBusUpdateUser(app, a.nameRules)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := a.roleBus.Check(ctx, uu.Roles); err != nil {
+		return checkRolesError(err)
+	}
+
+	usr, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	if err := checkIfMatch(r, usr); err != nil {
+		return err.(*errs.Error)
+	}
+
+	updUsr, err := a.userBus.Update(ctx, mid.GetSubjectID(ctx), usr, uu)
+	if err != nil {
+		return updateError("update", usr, err)
+	}
+
+	return toAppUser(updUsr)
+}
+
+func (a *App) delete(ctx context.Context, r *http.Request) web.Encoder {
+	usr, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	if err := checkIfMatch(r, usr); err != nil {
+		return err.(*errs.Error)
+	}
+
+	if err := a.userBus.Delete(ctx, mid.GetSubjectID(ctx), usr); err != nil {
+		return errs.Newf(errs.Internal, "delete: userID[%s]: %s", usr.ID, err)
+	}
+
+	return nil
+}
+
+func (a *App) restore(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return errs.NewFieldErrors("user_id", err)
+	}
+
+	var filter userbus.QueryFilter
+	filter.WithUserID(id)
+	filter.WithIncludeDeleted(true)
+
+	usrs, err := a.userBus.Query(ctx, filter, userbus.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: userID[%s]: %s", id, err)
+	}
+
+	if len(usrs) == 0 {
+		return errs.New(errs.NotFound, userbus.ErrNotFound)
+	}
+
+	if err := checkIfMatch(r, usrs[0]); err != nil {
+		return err.(*errs.Error)
+	}
+
+	usr, err := a.userBus.Restore(ctx, mid.GetSubjectID(ctx), usrs[0])
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotDeleted) {
+			return errs.New(errs.FailedPrecondition, userbus.ErrNotDeleted)
+		}
+		return updateError("restore", usrs[0], err)
+	}
+
+	return toAppUser(usr)
+}
+
+func (a *App) deactivate(ctx context.Context, r *http.Request) web.Encoder {
+	usr, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	if err := checkIfMatch(r, usr); err != nil {
+		return err.(*errs.Error)
+	}
+
+	usr, err = a.userBus.Deactivate(ctx, mid.GetSubjectID(ctx), usr)
+	if err != nil {
+		return updateError("deactivate", usr, err)
+	}
+
+	return toAppUser(usr)
+}
+
+func (a *App) activate(ctx context.Context, r *http.Request) web.Encoder {
+	usr, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	if err := checkIfMatch(r, usr); err != nil {
+		return err.(*errs.Error)
+	}
+
+	usr, err = a.userBus.Activate(ctx, mid.GetSubjectID(ctx), usr)
+	if err != nil {
+		return updateError("activate", usr, err)
+	}
+
+	return toAppUser(usr)
+}
+
+func (a *App) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, userbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	usrs, err := a.userBus.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.userBus.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return query.NewResult(toAppUsers(usrs), total, page)
+}
+
+// Search terms shorter than minSearch match too much to be useful and
+// trigram matching needs a few characters to work with.
+const (
+	minSearch = 2
+	maxSearch = 100
+)
+
+func (a *App) search(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if n := utf8.RuneCountInString(q); n < minSearch || n > maxSearch {
+		return errs.NewFieldErrors("q", fmt.Errorf("must be between %d and %d characters", minSearch, maxSearch))
+	}
+
+	hits, err := a.userBus.Search(ctx, q, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "search: %s", err)
+	}
+
+	total, err := a.userBus.SearchCount(ctx, q)
+	if err != nil {
+		return errs.Newf(errs.Internal, "searchcount: %s", err)
+	}
+
+	return query.NewResult(toAppSearchHits(hits), total, page)
+}
+
+// maxImportRows caps the number of rows accepted by a single import
+// request. Larger files should be loaded with the admin tooling.
+const maxImportRows = 1000
+
+func (a *App) importUsers(ctx context.Context, r *http.Request) web.Encoder {
+	values := r.URL.Query()
+
+	format, err := ParseFormat(values.Get("format"), r.Header.Get("Content-Type"))
+	if err != nil {
+		return errs.NewFieldErrors("format", err)
+	}
+
+	var dryRun bool
+	if v := values.Get("dry_run"); v != "" {
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			return errs.NewFieldErrors("dry_run", err)
+		}
+	}
+
+	var chunkSize int
+	if v := values.Get("chunk_size"); v != "" {
+		chunkSize, err = strconv.Atoi(v)
+		if err != nil || chunkSize < 0 {
+			return errs.NewFieldErrors("chunk_size", fmt.Errorf("invalid chunk size %q", v))
+		}
+	}
+
+	cfg := ImportConfig{
+		Format:     format,
+		DryRun:     dryRun,
+		ChunkSize:  chunkSize,
+		MaxRows:    maxImportRows,
+		CheckRoles: a.roleBus.Check,
+		NameRules:  a.nameRules,
+	}
+
+	report, err := Import(ctx, a.userBus, a.beginner, mid.GetSubjectID(ctx), r.Body, cfg)
+	if err != nil {
+		if errors.Is(err, userbus.ErrUniqueEmail) {
+			return errs.New(errs.Aborted, err)
+		}
+		if report.Total == 0 {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "import: created[%d]: %s", report.Created, err)
+	}
+
+	return report
+}
+
+func (a *App) export(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseQueryParams(r)
+
+	format, err := ParseFormat(r.URL.Query().Get("format"), "")
+	if err != nil {
+		return errs.NewFieldErrors("format", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, userbus.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	w := web.GetWriter(ctx)
+
+	contentType := "text/csv"
+	if format == FormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFileName(format)))
+	w.WriteHeader(http.StatusOK)
+
+	// The status has already been sent, so a failure part way through the
+	// stream can only be logged. The client sees a truncated file.
+	if n, err := Export(ctx, a.userBus, w, format, filter, orderBy); err != nil {
+		a.log.Error(ctx, "export users", "written", n, "msg", err)
+	}
+
+	return web.NewNoResponse()
+}
+
+func (a *App) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	usr, err := a.queryByParam(ctx, r)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	return toAppUser(usr)
+}
+
+func (a *App) queryByParam(ctx context.Context, r *http.Request) (userbus.User, error) {
+	id, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return userbus.User{}, errs.NewFieldErrors("user_id", err)
+	}
+
+	usr, err := a.userBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, userbus.ErrNotFound) {
+			return userbus.User{}, errs.New(errs.NotFound, err)
+		}
+		return userbus.User{}, errs.New(errs.Internal, fmt.Errorf("querybyid: userID[%s]: %w", id, err))
+	}
+
+	return usr, nil
+}
+
+// updateError maps the errors returned when writing a user to the
+// response for the client.
+func updateError(op string, usr userbus.User, err error) *errs.Error {
+	switch {
+	case errors.Is(err, userbus.ErrVersionConflict):
+		return errs.New(errs.PreconditionFailed, userbus.ErrVersionConflict)
+	case errors.Is(err, userbus.ErrUniqueEmail):
+		return errs.New(errs.Aborted, userbus.ErrUniqueEmail)
+	}
+
+	return errs.Newf(errs.Internal, "%s: userID[%s]: %s", op, usr.ID, err)
+}
+
+// checkRolesError maps the errors returned when checking the roles being
+// assigned to a user.
+func checkRolesError(err error) *errs.Error {
+	if errors.Is(err, rolebus.ErrUnknownRole) {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	return errs.Newf(errs.Internal, "check roles: %s", err)
+}
@@ -10,6 +10,7 @@ import (
 	"service/app/sdk/auth"
 	"service/business/domain/userbus"
 	"service/business/sdk/dbtest"
+	"service/business/sdk/tenant"
 	"service/business/types/role"
 	"testing"
 	"time"
@@ -65,6 +66,10 @@ func (at *Test) Run(t *testing.T, table []Table, testName string, options ...Opt
 			}
 
 			r.Header.Set("Authorization", "Bearer "+tt.Token)
+			for key, value := range tt.Headers {
+				r.Header.Set(key, value)
+			}
+
 			at.mux.ServeHTTP(w, r)
 
 			if w.Code != tt.StatusCode {
@@ -101,7 +106,7 @@ func (at *Test) Run(t *testing.T, table []Table, testName string, options ...Opt
 func Token(userBus userbus.ExtBusiness, ath *auth.Auth, email string) string {
 	addr, _ := mail.ParseAddress(email)
 
-	dbUsr, err := userBus.QueryByEmail(context.Background(), *addr)
+	dbUsr, err := userBus.QueryByEmail(tenant.System(context.Background()), *addr)
 	if err != nil {
 		return ""
 	}
@@ -114,6 +119,7 @@ func Token(userBus userbus.ExtBusiness, ath *auth.Auth, email string) string {
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
 		Roles: role.ParseToString(dbUsr.Roles),
+		OrgID: dbUsr.OrgID.String(),
 	}
 
 	token, err := ath.GenerateToken(kid, claims)
@@ -22,6 +22,7 @@ type Table struct {
 	URL        string
 	Token      string
 	Method     string
+	Headers    map[string]string
 	StatusCode int
 	Input      any
 	GotResp    any
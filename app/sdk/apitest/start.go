@@ -8,6 +8,8 @@ import (
 	"service/app/sdk/authclient"
 	"service/app/sdk/mux"
 	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/types/name"
 	"testing"
 )
 
@@ -20,6 +22,7 @@ func New(t *testing.T, testName string) *Test {
 	auth, err := auth.New(auth.Config{
 		Log:       db.Log,
 		UserBus:   db.BusDomain.User,
+		RoleBus:   db.BusDomain.Role,
 		KeyLookup: &KeyStore{},
 	})
 	if err != nil {
@@ -47,10 +50,17 @@ func New(t *testing.T, testName string) *Test {
 		Log: db.Log,
 		DB:  db.DB,
 		BusConfig: mux.BusConfig{
-			UserBus: db.BusDomain.User,
+			UserBus:    db.BusDomain.User,
+			InviteBus:  db.BusDomain.Invite,
+			GDPRBus:    db.BusDomain.GDPR,
+			ProductBus: db.BusDomain.Product,
+			OrderBus:   db.BusDomain.Order,
+			RoleBus:    db.BusDomain.Role,
 		},
 		SalesConfig: mux.SalesConfig{
 			AuthClient: authClient,
+			Beginner:   sqldb.NewBeginner(db.DB),
+			NameRules:  name.DefaultRules(),
 		},
 	}, salesbuild.Routes())
 
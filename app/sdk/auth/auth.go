@@ -4,19 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"service/business/domain/rolebus"
 	"service/business/domain/userbus"
 	"service/foundation/logger"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
 )
 
 // Claims represents the authorization claims transmitted via a JWT.
 type Claims struct {
 	jwt.RegisteredClaims
 	Roles []string `json:"roles"`
+	OrgID string   `json:"org_id,omitempty"`
 }
 
 // HasRole checks if the specified role exists.
@@ -34,24 +39,43 @@ type KeyLookup interface {
 	PublicKey(kid string) (key string, err error)
 }
 
+// Config represents information required to initialize auth. When RoleBus
+// is nil only the built-in roles are known to the policies.
 type Config struct {
 	Log       *logger.Logger
 	UserBus   userbus.ExtBusiness
+	RoleBus   *rolebus.Business
+	RoleTTL   time.Duration
 	KeyLookup KeyLookup
 	Issuer    string
 }
 
 type Auth struct {
+	log       *logger.Logger
 	keyLookup KeyLookup
 	userBus   userbus.ExtBusiness
+	roleBus   *rolebus.Business
+	roleTTL   time.Duration
 	method    jwt.SigningMethod
 	parser    *jwt.Parser
 	issuer    string
+
+	mu          sync.Mutex
+	roleData    map[string]any
+	roleExpires time.Time
 }
 
 func New(cfg Config) (*Auth, error) {
+	roleTTL := cfg.RoleTTL
+	if roleTTL == 0 {
+		roleTTL = 30 * time.Second
+	}
+
 	a := Auth{
+		log:       cfg.Log,
 		keyLookup: cfg.KeyLookup,
+		roleBus:   cfg.RoleBus,
+		roleTTL:   roleTTL,
 		method:    jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
 		parser:    jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
 		issuer:    cfg.Issuer,
@@ -119,7 +143,7 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 		"ISS":   a.issuer,
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, regoAuthentication, RuleAuthenticate, input); err != nil {
+	if err := a.opaPolicyEvaluation(ctx, regoAuthentication, RuleAuthenticate, input, nil); err != nil {
 		return Claims{}, fmt.Errorf("authentication failed : %w", err)
 	}
 
@@ -133,20 +157,63 @@ func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, r
 		"UserID":  userID,
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, regoAuthorization, rule, input); err != nil {
+	data, err := a.roles(ctx)
+	if err != nil {
+		return fmt.Errorf("roles: %w", err)
+	}
+
+	if err := a.opaPolicyEvaluation(ctx, regoAuthorization, rule, input, data); err != nil {
 		return fmt.Errorf("rego evaluation failed: %w", err)
 	}
 	return nil
 }
 
-func (a *Auth) opaPolicyEvaluation(ctx context.Context, regoScript string, rule string, input any) error {
+// roles returns the role document provided to the policies as data. The
+// document is reloaded from the role domain once the ttl has passed, so
+// changes to roles take effect without a restart.
+func (a *Auth) roles(ctx context.Context) (map[string]any, error) {
+	if a.roleBus == nil {
+		return builtInRoles, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.roleData != nil && time.Now().Before(a.roleExpires) {
+		return a.roleData, nil
+	}
+
+	rls, err := a.roleBus.QueryAll(ctx)
+	if err != nil {
+		// Keep authorizing with the last known roles rather than denying
+		// every request while the database is unavailable.
+		if a.roleData != nil {
+			a.log.Error(ctx, "auth: roles", "msg", "using previous roles", "err", err)
+			return a.roleData, nil
+		}
+		return nil, err
+	}
+
+	a.roleData = toRoleData(rls)
+	a.roleExpires = time.Now().Add(a.roleTTL)
+
+	return a.roleData, nil
+}
+
+func (a *Auth) opaPolicyEvaluation(ctx context.Context, regoScript string, rule string, input any, data map[string]any) error {
 
 	query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
 
-	q, err := rego.New(
+	opts := []func(*rego.Rego){
 		rego.Query(query),
 		rego.Module("policy.rego", regoScript),
-	).PrepareForEval(ctx)
+	}
+
+	if data != nil {
+		opts = append(opts, rego.Store(inmem.NewFromObject(data)))
+	}
+
+	q, err := rego.New(opts...).PrepareForEval(ctx)
 
 	if err != nil {
 		return err
@@ -66,6 +66,13 @@ func test1(ath *auth.Auth) func(t *testing.T) {
 		if err != nil {
 			t.Errorf("should be able to authorize the RuleAdminOrSubject claim with Roles.Admin only : %s", err)
 		}
+
+		for _, rule := range []string{auth.RuleManageAnyOrder, auth.RuleManageAnyProduct} {
+			err = ath.Authorize(context.Background(), parsedClaims, userID, rule)
+			if err != nil {
+				t.Errorf("Should be able to authorize the %s claim with Roles.Admin only : %s", rule, err)
+			}
+		}
 	}
 	return f
 }
@@ -113,6 +120,13 @@ func test2(ath *auth.Auth) func(t *testing.T) {
 		if err != nil {
 			t.Errorf("Should be able to authorize the RuleAny any claim with Roles.User only: %s", err)
 		}
+
+		for _, rule := range []string{auth.RuleManageAnyOrder, auth.RuleManageAnyProduct} {
+			err = ath.Authorize(context.Background(), parsedClaims, userID, rule)
+			if err == nil {
+				t.Errorf("Should NOT be able to authorize the %s claim with Roles.User only", rule)
+			}
+		}
 	}
 	return f
 }
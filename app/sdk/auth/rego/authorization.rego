@@ -10,37 +10,54 @@ default rule_user_only := false
 
 default rule_admin_or_subject := false
 
-role_user := "USER"
+default rule_manage_any_order := false
 
-role_admin := "ADMIN"
+default rule_manage_any_product := false
 
-role_all := {role_admin, role_user}
+permission_user := "user"
+
+permission_admin := "admin"
+
+permission_manage_any_order := "order:manage_any"
+
+permission_manage_any_product := "product:manage_any"
+
+# Roles are provided as data.roles, keyed by role name, with the permissions
+# each role grants and the roles it inherits.
+role_graph := {name: role.inherits | some name, role in data.roles}
+
+claim_roles := {role | some role in input.Roles}
+
+effective_roles := graph.reachable(role_graph, claim_roles) & object.keys(data.roles)
+
+permissions := {permission |
+	some role in effective_roles
+	some permission in data.roles[role].permissions
+}
 
 rule_any if {
-	claim_roles := {role | some role in input.Roles}
-	input_roles := role_all & claim_roles
-	count(input_roles) > 0
+	count(effective_roles) > 0
 }
 
 rule_admin_only if {
-	claim_roles := {role | some role in input.Roles}
-	input_admin := {role_admin} & claim_roles
-	count(input_admin) > 0
+	permission_admin in permissions
 }
 
 rule_user_only if {
-	claim_roles := {role | some role in input.Roles}
-	input_user := {role_user} & claim_roles
-	count(input_user) > 0
+	permission_user in permissions
 }
 
 rule_admin_or_subject if {
-	claim_roles := {role | some role in input.Roles}
-	input_admin := {role_admin} & claim_roles
-	count(input_admin) > 0
+	permission_admin in permissions
 } else if {
-	claim_roles := {role | some role in input.Roles}
-	input_user := {role_user} & claim_roles
-	count(input_user) > 0
+	permission_user in permissions
 	input.UserID == input.Subject
-}
\ No newline at end of file
+}
+
+rule_manage_any_order if {
+	permission_manage_any_order in permissions
+}
+
+rule_manage_any_product if {
+	permission_manage_any_product in permissions
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"service/business/domain/rolebus"
+	"service/business/types/role"
+)
+
+// These are the permissions checked by the authorization rules.
+const (
+	PermissionAdmin = "admin"
+	PermissionUser  = "user"
+
+	// PermissionManageAnyOrder and PermissionManageAnyProduct let a role
+	// act on the orders and products of every user, not only its own.
+	PermissionManageAnyOrder   = "order:manage_any"
+	PermissionManageAnyProduct = "product:manage_any"
+)
+
+// builtInRoles is the role document used when no role domain is configured.
+var builtInRoles = map[string]any{
+	"roles": map[string]any{
+		role.AdminRole.String(): map[string]any{
+			"permissions": []any{PermissionAdmin, PermissionManageAnyOrder, PermissionManageAnyProduct},
+			"inherits":    []any{},
+		},
+		role.UserRole.String(): map[string]any{
+			"permissions": []any{PermissionUser},
+			"inherits":    []any{},
+		},
+	},
+}
+
+// toRoleData converts the roles into the document the policies read from
+// data.roles.
+func toRoleData(rls []rolebus.Role) map[string]any {
+	roles := make(map[string]any, len(rls))
+
+	for _, rl := range rls {
+		permissions := make([]any, len(rl.Permissions))
+		for i, p := range rl.Permissions {
+			permissions[i] = p
+		}
+
+		inherits := make([]any, len(rl.Inherits))
+		for i, r := range rl.Inherits {
+			inherits[i] = r.String()
+		}
+
+		roles[rl.Name.String()] = map[string]any{
+			"permissions": permissions,
+			"inherits":    inherits,
+		}
+	}
+
+	return map[string]any{
+		"roles": roles,
+	}
+}
@@ -6,11 +6,13 @@ import (
 
 // These the current set of rules we have for auth.
 const (
-	RuleAuthenticate   = "auth"
-	RuleAny            = "rule_any"
-	RuleAdminOnly      = "rule_admin_only"
-	RuleUserOnly       = "rule_user_only"
-	RuleAdminOrSubject = "rule_admin_or_subject"
+	RuleAuthenticate     = "auth"
+	RuleAny              = "rule_any"
+	RuleAdminOnly        = "rule_admin_only"
+	RuleUserOnly         = "rule_user_only"
+	RuleAdminOrSubject   = "rule_admin_or_subject"
+	RuleManageAnyOrder   = "rule_manage_any_order"
+	RuleManageAnyProduct = "rule_manage_any_product"
 )
 
 // Package name of our rego code.
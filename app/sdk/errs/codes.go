@@ -116,6 +116,12 @@ var (
 	// system has been broken. If you see one of these errors,
 	// something is very broken. The error message is not sent to the client.
 	InternalOnlyLog = ErrCode{value: 19}
+
+	// PreconditionFailed indicates a conditional request was rejected
+	// because the resource no longer matches the version the client holds,
+	// typically from an If-Match header. Unlike FailedPrecondition, the
+	// client should fetch the resource again before retrying.
+	PreconditionFailed = ErrCode{value: 20}
 )
 
 var codeNumbers = map[string]ErrCode{
@@ -139,6 +145,7 @@ var codeNumbers = map[string]ErrCode{
 	"unauthenticated":     Unauthenticated,
 	"too_many_requests":   TooManyRequests,
 	"internal_only_log":   InternalOnlyLog,
+	"precondition_failed": PreconditionFailed,
 }
 
 var codeNames = map[ErrCode]string{
@@ -162,6 +169,7 @@ var codeNames = map[ErrCode]string{
 	Unauthenticated:    "unauthenticated",
 	TooManyRequests:    "too_many_requests",
 	InternalOnlyLog:    "internal_only_log",
+	PreconditionFailed: "precondition_failed",
 }
 
 var httpStatus = map[ErrCode]int{
@@ -185,4 +193,5 @@ var httpStatus = map[ErrCode]int{
 	Unauthenticated:    http.StatusUnauthorized,
 	TooManyRequests:    http.StatusTooManyRequests,
 	InternalOnlyLog:    http.StatusInternalServerError,
+	PreconditionFailed: http.StatusPreconditionFailed,
 }
@@ -10,6 +10,7 @@ import (
 	"service/app/sdk/authclient"
 	"service/app/sdk/errs"
 	"service/business/domain/userbus"
+	"service/business/sdk/tenant"
 	"service/business/types/role"
 	"service/foundation/web"
 	"strings"
@@ -67,7 +68,9 @@ func Bearer(ath *auth.Auth) web.MidFunc {
 	return m
 }
 
-// Basic processes basic authentication logic.
+// Basic processes basic authentication logic. Emails are only unique within
+// an organization, so a user whose email is also used in another one has to
+// name their organization in the X-Org-ID header.
 func Basic(ath *auth.Auth, userBus userbus.ExtBusiness) web.MidFunc {
 	m := func(next web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
@@ -82,7 +85,18 @@ func Basic(ath *auth.Auth, userBus userbus.ExtBusiness) web.MidFunc {
 			}
 			fmt.Println(addr, pass)
 
-			usr, err := userBus.Authenticate(ctx, *addr, pass)
+			// The user isn't known yet, so without the header the lookup
+			// can't be scoped to their organization.
+			authCtx := tenant.System(ctx)
+			if v := r.Header.Get("X-Org-ID"); v != "" {
+				orgID, err := uuid.Parse(v)
+				if err != nil {
+					return errs.Newf(errs.Unauthenticated, "parsing org: %s", err)
+				}
+				authCtx = tenant.Set(ctx, orgID)
+			}
+
+			usr, err := userBus.Authenticate(authCtx, *addr, pass)
 			if err != nil {
 				return errs.New(errs.Unauthenticated, err)
 			}
@@ -95,6 +109,7 @@ func Basic(ath *auth.Auth, userBus userbus.ExtBusiness) web.MidFunc {
 					IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 				},
 				Roles: role.ParseToString(usr.Roles),
+				OrgID: usr.OrgID.String(),
 			}
 
 			subjectID, err := uuid.Parse(claims.Subject)
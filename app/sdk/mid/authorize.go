@@ -2,6 +2,8 @@ package mid
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"service/app/sdk/authclient"
 	"service/app/sdk/errs"
@@ -34,3 +36,33 @@ func Authorize(client *authclient.Client, rule string) web.MidFunc {
 	}
 	return m
 }
+
+// Allowed reports whether the claims in the context satisfy the rule. Unlike
+// Authorize it doesn't reject the request, so a handler can widen what the
+// caller may act on when the rule holds. A rule that doesn't hold isn't an
+// error, only a failure to reach the auth service is.
+func Allowed(ctx context.Context, client *authclient.Client, rule string) (bool, error) {
+	userID, err := GetUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	auth := authclient.Authorize{
+		Claims: GetClaims(ctx),
+		UserID: userID,
+		Rule:   rule,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := client.Authorize(ctx, auth); err != nil {
+		var appErr *errs.Error
+		if errors.As(err, &appErr) && appErr.Code == errs.Unauthenticated {
+			return false, nil
+		}
+		return false, fmt.Errorf("authorize: rule[%s]: %w", rule, err)
+	}
+
+	return true, nil
+}
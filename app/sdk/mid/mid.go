@@ -67,6 +67,7 @@ func GetUserID(ctx context.Context) (uuid.UUID, error) {
 }
 
 func setTran(ctx context.Context, tx sqldb.CommitRollbacker) context.Context {
+	ctx = sqldb.WithTx(ctx, tx)
 	return context.WithValue(ctx, trKey, tx)
 }
 
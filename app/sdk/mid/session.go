@@ -0,0 +1,21 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+	"service/business/sdk/sqldb"
+	"service/foundation/web"
+)
+
+// Session starts a read-your-writes database session for the request so
+// reads made after a write are served by the primary instead of a replica
+// that may not have the change yet.
+func Session() web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			return next(sqldb.WithSession(ctx), r)
+		}
+		return h
+	}
+	return m
+}
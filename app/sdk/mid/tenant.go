@@ -0,0 +1,64 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"service/app/sdk/errs"
+	"service/business/sdk/tenant"
+	"service/foundation/web"
+
+	"github.com/google/uuid"
+)
+
+// Tenant resolves the organization from the claims and stores it in the
+// context so every store call made by the handler is scoped to it. It must
+// run after the authentication middleware.
+func Tenant() web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			claims := GetClaims(ctx)
+			if claims.OrgID == "" {
+				return errs.New(errs.Unauthenticated, errors.New("tenant: no organization in claims"))
+			}
+
+			orgID, err := uuid.Parse(claims.OrgID)
+			if err != nil {
+				return errs.Newf(errs.Unauthenticated, "tenant: parsing organization: %s", err)
+			}
+
+			ctx = tenant.Set(ctx, orgID)
+
+			return next(ctx, r)
+		}
+		return h
+	}
+	return m
+}
+
+// TenantQuery resolves the organization from the specified query parameter
+// and stores it in the context like Tenant. It's meant for routes called
+// without authentication, like accepting an invitation, whose link carries
+// the organization. The handler must still verify the data it finds belongs
+// to that organization.
+func TenantQuery(param string) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			v := r.URL.Query().Get(param)
+			if v == "" {
+				return errs.Newf(errs.InvalidArgument, "tenant: no %s in request", param)
+			}
+
+			orgID, err := uuid.Parse(v)
+			if err != nil {
+				return errs.Newf(errs.InvalidArgument, "tenant: parsing %s: %s", param, err)
+			}
+
+			ctx = tenant.Set(ctx, orgID)
+
+			return next(ctx, r)
+		}
+		return h
+	}
+	return m
+}
@@ -1,57 +1,180 @@
 package mid
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"service/app/sdk/errs"
 	"service/business/sdk/sqldb"
 	"service/foundation/logger"
 	"service/foundation/web"
+	"time"
 )
 
-func BeginCommitRollback(log *logger.Logger, bgn sqldb.Beginner) web.MidFunc {
-	m := func(next web.HandlerFunc) web.HandlerFunc {
-		h := func(ctx context.Context, r *http.Request) web.Encoder {
-			hasCommitted := false
+// Retries of a transaction that lost a serialization conflict or a deadlock
+// wait a random time up to a doubling ceiling so competing requests spread
+// out instead of colliding again. The body kept for the retries is capped
+// since it's held in memory for the whole request.
+const (
+	retryBaseDelay = 10 * time.Millisecond
+	retryMaxDelay  = 500 * time.Millisecond
+	retryMaxBody   = 1 << 20
+)
 
-			log.Info(ctx, "BEGIN TRANSACTION")
-			tx, err := bgn.Begin()
+// TxOption represents an option for the transaction of a route.
+type TxOption func(opts *txOptions)
 
-			if err != nil {
-				return errs.Newf(errs.Internal, "BEGIN TRANSACTION: %s", err)
+type txOptions struct {
+	isolation     sql.IsolationLevel
+	retries       int
+	commitOnError bool
+}
+
+// WithIsolation runs the transaction of the route at the specified
+// isolation level instead of the database default.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(opts *txOptions) {
+		opts.isolation = level
+	}
+}
+
+// WithRetries sets how many times the handler is run again when its
+// transaction fails with a serialization failure or a deadlock. Retries are
+// off by default since anything the handler does outside the database, like
+// sending an email, is repeated as well. The request body of a route with
+// retries is limited to 1MB.
+func WithRetries(retries int) TxOption {
+	return func(opts *txOptions) {
+		opts.retries = retries
+	}
+}
+
+// WithCommitOnError commits the transaction of the route even when the
+// handler returns an error. Use it only for handlers whose writes must
+// persist on a handled error, like recording a failed attempt.
+func WithCommitOnError() TxOption {
+	return func(opts *txOptions) {
+		opts.commitOnError = true
+	}
+}
+
+// BeginCommitRollback runs the handler inside a transaction that is
+// committed when the handler succeeds and rolled back when it returns an
+// error. The work handed to sqldb.AfterCommit only runs once the
+// transaction commits. Routes set up WithRetries run the handler again in a new
+// transaction with the same request body when the transaction fails with a
+// serialization failure or a deadlock.
+func BeginCommitRollback(log *logger.Logger, bgn sqldb.Beginner, options ...TxOption) web.MidFunc {
+	var opts txOptions
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			if opts.isolation != sql.LevelDefault {
+				ctx = sqldb.WithIsolation(ctx, opts.isolation)
 			}
 
-			defer func() {
-				if !hasCommitted {
-					log.Info(ctx, "ROLLBACK TRANSACTION")
-				}
-				if err := tx.Rollback(); err != nil {
-					if errors.Is(err, sql.ErrTxDone) {
-						return
+			// The body can only be read once, so it's kept to hand a fresh
+			// reader to every attempt.
+			var body []byte
+			if opts.retries > 0 && r.Body != nil {
+				var err error
+				if body, err = io.ReadAll(http.MaxBytesReader(nil, r.Body, retryMaxBody)); err != nil {
+					var maxErr *http.MaxBytesError
+					if errors.As(err, &maxErr) {
+						return errs.Newf(errs.InvalidArgument, "body larger than %d bytes", maxErr.Limit)
 					}
-					log.Info(ctx, "ROLLBACK TRANSACTION", "ERROR", err)
+					return errs.Newf(errs.InvalidArgument, "reading body: %s", err)
 				}
-			}()
+			}
 
-			ctx = setTran(ctx, tx)
-			resp := next(ctx, r)
+			for attempt := 0; ; attempt++ {
+				if body != nil {
+					r.Body = io.NopCloser(bytes.NewReader(body))
+				}
 
-			// if errs.isError(resp) != nil {
-			// 	return resp
-			// }
+				resp, retry := runTran(ctx, log, bgn, opts, next, r)
+				if !retry {
+					return resp
+				}
 
-			log.Info(ctx, "COMMIT TRANSACTION")
-			if err := tx.Commit(); err != nil {
-				return errs.Newf(errs.Internal, "COMMIT TRANSACTION: %s", err)
-			}
+				if attempt >= opts.retries {
+					return errs.Newf(errs.Aborted, "transaction conflict, retries exhausted")
+				}
 
-			hasCommitted = true
+				delay := min(retryBaseDelay<<attempt, retryMaxDelay)
+				delay = rand.N(delay) + 1
 
-			return resp
+				log.Info(ctx, "RETRY TRANSACTION", "attempt", attempt+1, "delay", delay)
+
+				select {
+				case <-ctx.Done():
+					return errs.Newf(errs.Aborted, "transaction conflict: %s", ctx.Err())
+				case <-time.After(delay):
+				}
+			}
 		}
 		return h
 	}
 	return m
 }
+
+// runTran runs a single attempt of the handler inside a transaction and
+// reports whether it failed in a way that running it again can fix.
+func runTran(ctx context.Context, log *logger.Logger, bgn sqldb.Beginner, opts txOptions, next web.HandlerFunc, r *http.Request) (web.Encoder, bool) {
+	hasCommitted := false
+
+	ctx = sqldb.TrackRetry(ctx)
+	ctx = sqldb.TrackCommit(ctx)
+
+	log.Info(ctx, "BEGIN TRANSACTION")
+	tx, err := bgn.Begin(ctx)
+
+	if err != nil {
+		return errs.Newf(errs.Internal, "BEGIN TRANSACTION: %s", err), false
+	}
+
+	defer func() {
+		if !hasCommitted {
+			log.Info(ctx, "ROLLBACK TRANSACTION")
+		}
+		if err := tx.Rollback(); err != nil {
+			if errors.Is(err, sql.ErrTxDone) {
+				return
+			}
+			log.Info(ctx, "ROLLBACK TRANSACTION", "ERROR", err)
+		}
+	}()
+
+	ctx = setTran(ctx, tx)
+	resp := next(ctx, r)
+
+	if sqldb.ShouldRetry(ctx) {
+		return resp, true
+	}
+
+	if isError(resp) != nil && !opts.commitOnError {
+		return resp, false
+	}
+
+	log.Info(ctx, "COMMIT TRANSACTION")
+	if err := tx.Commit(); err != nil {
+		if sqldb.IsRetryable(err) {
+			return resp, true
+		}
+		return errs.Newf(errs.Internal, "COMMIT TRANSACTION: %s", err), false
+	}
+
+	hasCommitted = true
+
+	sqldb.RunAfterCommit(ctx)
+
+	return resp, false
+}
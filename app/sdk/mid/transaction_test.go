@@ -0,0 +1,186 @@
+package mid_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"service/app/sdk/errs"
+	"service/app/sdk/mid"
+	"service/business/domain/userbus"
+	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/unitest"
+	"service/business/types/name"
+	"service/business/types/role"
+	"service/foundation/web"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Transaction(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Transaction")
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, transaction(db), "transaction")
+}
+
+// =============================================================================
+
+func transaction(db *dbtest.Database) []unitest.Table {
+	bgn := sqldb.NewBeginner(db.DB)
+
+	table := []unitest.Table{
+		{
+			Name:    "commit",
+			ExpResp: "commit@ardanlabs.com",
+			ExcFunc: func(ctx context.Context) any {
+				return runHandler(ctx, db, bgn, "commit@ardanlabs.com", false)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "rollback",
+			ExpResp: userbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				return runHandler(ctx, db, bgn, "rollback@ardanlabs.com", true)
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "commit-on-error",
+			ExpResp: "kept@ardanlabs.com",
+			ExcFunc: func(ctx context.Context) any {
+				return runHandler(ctx, db, bgn, "kept@ardanlabs.com", true, mid.WithCommitOnError())
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "after-commit",
+			ExpResp: []string{"commit"},
+			ExcFunc: func(ctx context.Context) any {
+				var sent []string
+
+				for _, name := range []string{"commit", "rollback"} {
+					handler := func(ctx context.Context, r *http.Request) web.Encoder {
+						sqldb.AfterCommit(ctx, func(ctx context.Context) {
+							sent = append(sent, name)
+						})
+
+						if name == "rollback" {
+							return errs.Newf(errs.FailedPrecondition, "second step failed")
+						}
+						return nil
+					}
+
+					h := mid.BeginCommitRollback(db.Log, bgn)(handler)
+					h(ctx, httptest.NewRequest(http.MethodPost, "/", nil))
+				}
+
+				return sent
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "retry-body-limit",
+			ExpResp: errs.InvalidArgument.String(),
+			ExcFunc: func(ctx context.Context) any {
+				handler := func(ctx context.Context, r *http.Request) web.Encoder {
+					return nil
+				}
+
+				h := mid.BeginCommitRollback(db.Log, bgn, mid.WithRetries(1))(handler)
+
+				body := strings.NewReader(strings.Repeat("x", 1<<20+1))
+				r := httptest.NewRequest(http.MethodPost, "/", body)
+
+				var appErr *errs.Error
+				resp := h(ctx, r)
+				if resp == nil || !errors.As(resp.(error), &appErr) {
+					return fmt.Errorf("expected an error, got %v", resp)
+				}
+
+				return appErr.Code.String()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// runHandler creates a user inside the transaction of the middleware and
+// returns the email of the user if it can be found once the middleware is
+// done.
+func runHandler(ctx context.Context, db *dbtest.Database, bgn sqldb.Beginner, email string, fail bool, options ...mid.TxOption) any {
+	addr := mail.Address{Address: email}
+
+	handler := func(ctx context.Context, r *http.Request) web.Encoder {
+		tx, err := mid.GetTran(ctx)
+		if err != nil {
+			return errs.New(errs.Internal, err)
+		}
+
+		userBus, err := db.BusDomain.User.NewWithTx(tx)
+		if err != nil {
+			return errs.New(errs.Internal, err)
+		}
+
+		nu := userbus.NewUser{
+			Name:     name.MustParse("Transaction Test"),
+			Email:    addr,
+			Roles:    []role.Role{role.UserRole},
+			Password: "123",
+		}
+
+		if _, err := userBus.Create(ctx, uuid.UUID{}, nu); err != nil {
+			return errs.New(errs.Internal, err)
+		}
+
+		if fail {
+			return errs.Newf(errs.FailedPrecondition, "second step failed")
+		}
+
+		return nil
+	}
+
+	h := mid.BeginCommitRollback(db.Log, bgn, options...)(handler)
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	if resp := h(ctx, r); resp != nil {
+		var appErr *errs.Error
+		if !errors.As(resp.(error), &appErr) || appErr.Code != errs.FailedPrecondition {
+			return fmt.Errorf("unexpected response: %v", resp)
+		}
+	}
+
+	usr, err := db.BusDomain.User.QueryByEmail(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	return usr.Email.Address
+}
@@ -6,7 +6,14 @@ import (
 	"service/app/sdk/auth"
 	"service/app/sdk/authclient"
 	"service/app/sdk/mid"
+	"service/business/domain/gdprbus"
+	"service/business/domain/invitebus"
+	"service/business/domain/orderbus"
+	"service/business/domain/productbus"
+	"service/business/domain/rolebus"
 	"service/business/domain/userbus"
+	"service/business/sdk/sqldb"
+	"service/business/types/name"
 	"service/foundation/logger"
 	"service/foundation/web"
 
@@ -36,10 +43,18 @@ type AuthConfig struct {
 // SalesConfig contains sales service specific config.
 type SalesConfig struct {
 	AuthClient *authclient.Client
+	Beginner   sqldb.Beginner
+	NameRules  name.Rules
 }
 
+// BusConfig contains the business domain apis shared by the services.
 type BusConfig struct {
-	UserBus userbus.ExtBusiness
+	UserBus    userbus.ExtBusiness
+	InviteBus  invitebus.ExtBusiness
+	GDPRBus    *gdprbus.Business
+	ProductBus productbus.ExtBusiness
+	OrderBus   orderbus.ExtBusiness
+	RoleBus    *rolebus.Business
 }
 
 // Config contains all the mandatory systems required by handlers.
@@ -62,7 +77,7 @@ type RouteAdder interface {
 
 func WebAPI(cfg Config, routeAdder RouteAdder, options ...func(opts *Options)) *web.App {
 	mux := web.New(cfg.Shutdown, cfg.Tracer, mid.Otel(cfg.Tracer),
-		mid.Logger(cfg.Log), mid.Errors(cfg.Log), mid.Metrics(), mid.Panics())
+		mid.Logger(cfg.Log), mid.Errors(cfg.Log), mid.Metrics(), mid.Panics(), mid.Session())
 
 	routeAdder.Add(mux, cfg)
 
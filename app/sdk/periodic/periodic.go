@@ -0,0 +1,34 @@
+// Package periodic provides support for running background work on a
+// fixed interval.
+package periodic
+
+import (
+	"context"
+	"service/foundation/logger"
+	"time"
+)
+
+// Func represents the work performed on each tick.
+type Func func(ctx context.Context) error
+
+// Run executes the function every interval until the context is cancelled.
+// Errors are logged and do not stop future runs.
+func Run(ctx context.Context, log *logger.Logger, name string, interval time.Duration, fn Func) {
+	log.Info(ctx, "periodic", "status", "started", "name", name, "interval", interval)
+	defer log.Info(ctx, "periodic", "status", "stopped", "name", name)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Error(ctx, "periodic", "name", name, "ERROR", err)
+			}
+		}
+	}
+}
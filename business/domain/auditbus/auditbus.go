@@ -1,15 +1,132 @@
+// Package auditbus provides business access to audit domain.
 package auditbus
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"service/business/sdk/order"
 	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/foundation/logger"
+	"service/foundation/otel"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// DomainName represents the name of this domain.
+const DomainName = "audit"
+
+// ErrRedactAll is returned when a redaction doesn't select an object or an
+// actor and would redact every audit record.
+var ErrRedactAll = errors.New("redaction must select an object or an actor")
+
 // Storer interface declares the behavior this package needs to persist and
 // retrieve data.
 type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, audit Audit) error
+	Redact(ctx context.Context, filter QueryFilter) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 }
+
+// Business manages the set of APIs for audit access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a audit business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new audit record to the system.
+func (b *Business) Create(ctx context.Context, na NewAudit) (Audit, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.create")
+	defer span.End()
+
+	jsonData, err := json.Marshal(na.Data)
+	if err != nil {
+		return Audit{}, fmt.Errorf("marshal object: %w", err)
+	}
+
+	aud := Audit{
+		ID:        uuid.New(),
+		ObjID:     na.ObjID,
+		ObjDomain: na.ObjDomain,
+		ObjName:   na.ObjName,
+		ActorID:   na.ActorID,
+		Action:    na.Action,
+		Data:      jsonData,
+		Message:   na.Message,
+		Timestamp: time.Now(),
+	}
+
+	if err := b.storer.Create(ctx, aud); err != nil {
+		return Audit{}, fmt.Errorf("create audit: %w", err)
+	}
+
+	return aud, nil
+}
+
+// Redact removes the data and message captured in the audit records
+// matching the filter. The records themselves are kept as proof the actions
+// took place. The filter must select an object or an actor.
+func (b *Business) Redact(ctx context.Context, filter QueryFilter) error {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.redact")
+	defer span.End()
+
+	if filter.ObjID == nil && filter.ObjIDs == nil && filter.ActorID == nil {
+		return ErrRedactAll
+	}
+
+	if err := b.storer.Redact(ctx, filter); err != nil {
+		return fmt.Errorf("redact: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing audit records.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Audit, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.query")
+	defer span.End()
+
+	audits, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return audits, nil
+}
+
+// Count returns the total number of audit records.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.auditbus.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
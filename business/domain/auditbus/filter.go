@@ -12,6 +12,7 @@ import (
 // We are using pointer semantics because the With API mutates the value.
 type QueryFilter struct {
 	ObjID     *uuid.UUID
+	ObjIDs    []uuid.UUID
 	ObjDomain *domain.Domain
 	ObjName   *name.Name
 	ActorID   *uuid.UUID
@@ -19,3 +20,28 @@ type QueryFilter struct {
 	Since     *time.Time
 	Until     *time.Time
 }
+
+// WithObjID sets the ObjID field of the QueryFilter value.
+func (qf *QueryFilter) WithObjID(objID uuid.UUID) {
+	qf.ObjID = &objID
+}
+
+// WithObjIDs sets the ObjIDs field of the QueryFilter value.
+func (qf *QueryFilter) WithObjIDs(objIDs []uuid.UUID) {
+	qf.ObjIDs = objIDs
+}
+
+// WithObjDomain sets the ObjDomain field of the QueryFilter value.
+func (qf *QueryFilter) WithObjDomain(objDomain domain.Domain) {
+	qf.ObjDomain = &objDomain
+}
+
+// WithActorID sets the ActorID field of the QueryFilter value.
+func (qf *QueryFilter) WithActorID(actorID uuid.UUID) {
+	qf.ActorID = &actorID
+}
+
+// WithAction sets the Action field of the QueryFilter value.
+func (qf *QueryFilter) WithAction(action string) {
+	qf.Action = &action
+}
@@ -0,0 +1,171 @@
+package auditbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"service/business/sdk/gdpr"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exportAudit represents the audit data included in a data subject export.
+type exportAudit struct {
+	ID        uuid.UUID       `json:"id"`
+	ObjID     uuid.UUID       `json:"objID"`
+	ObjDomain string          `json:"objDomain"`
+	ObjName   string          `json:"objName"`
+	ActorID   uuid.UUID       `json:"actorID"`
+	Action    string          `json:"action"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Message   string          `json:"message,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// exportAudits represents the audit records about the user, the actions
+// the user has taken and the records about objects of other domains that
+// hold data about the user.
+type exportAudits struct {
+	About   []exportAudit `json:"about"`
+	Actor   []exportAudit `json:"actor"`
+	Related []exportAudit `json:"related"`
+}
+
+// RelatedFunc returns the IDs of the objects of another domain that hold
+// data about the specified user, such as the invitations sent to their
+// email, so the audit records about them are exported and redacted along
+// with the records about the user.
+type RelatedFunc func(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+// RegisterGDPR registers the audit domain as a contributor to data subject
+// export and erasure requests.
+func RegisterGDPR(registry *gdpr.Registry, bus *Business, related ...RelatedFunc) {
+	registry.Register(DomainName, gdpr.Contributor{
+		Export: func(ctx context.Context, userID uuid.UUID) (any, error) {
+			var about QueryFilter
+			about.WithObjID(userID)
+
+			aboutAudits, err := queryAll(ctx, bus, about)
+			if err != nil {
+				return nil, fmt.Errorf("query about: %w", err)
+			}
+
+			var actor QueryFilter
+			actor.WithActorID(userID)
+
+			actorAudits, err := queryAll(ctx, bus, actor)
+			if err != nil {
+				return nil, fmt.Errorf("query actor: %w", err)
+			}
+
+			objIDs, err := relatedIDs(ctx, related, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			relatedAudits := []exportAudit{}
+			if len(objIDs) > 0 {
+				var rel QueryFilter
+				rel.WithObjIDs(objIDs)
+
+				relatedAudits, err = queryAll(ctx, bus, rel)
+				if err != nil {
+					return nil, fmt.Errorf("query related: %w", err)
+				}
+			}
+
+			exp := exportAudits{
+				About:   aboutAudits,
+				Actor:   actorAudits,
+				Related: relatedAudits,
+			}
+
+			return exp, nil
+		},
+
+		Erase: func(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error {
+			var about QueryFilter
+			about.WithObjID(userID)
+
+			if err := bus.Redact(ctx, about); err != nil {
+				return fmt.Errorf("redact about: %w", err)
+			}
+
+			var actor QueryFilter
+			actor.WithActorID(userID)
+
+			if err := bus.Redact(ctx, actor); err != nil {
+				return fmt.Errorf("redact actor: %w", err)
+			}
+
+			objIDs, err := relatedIDs(ctx, related, userID)
+			if err != nil {
+				return err
+			}
+
+			if len(objIDs) > 0 {
+				var rel QueryFilter
+				rel.WithObjIDs(objIDs)
+
+				if err := bus.Redact(ctx, rel); err != nil {
+					return fmt.Errorf("redact related: %w", err)
+				}
+			}
+
+			return nil
+		},
+	})
+}
+
+// relatedIDs collects the objects other domains report as holding data
+// about the user.
+func relatedIDs(ctx context.Context, related []RelatedFunc, userID uuid.UUID) ([]uuid.UUID, error) {
+	var objIDs []uuid.UUID
+	for _, fn := range related {
+		ids, err := fn(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("related: %w", err)
+		}
+		objIDs = append(objIDs, ids...)
+	}
+
+	return objIDs, nil
+}
+
+// byTimestamp keeps the export in the order the actions took place.
+var byTimestamp = order.NewBy(OrderByTimestamp, order.ASC)
+
+// queryAll pages through all the audit records matching the filter.
+func queryAll(ctx context.Context, bus *Business, filter QueryFilter) ([]exportAudit, error) {
+	const rows = 100
+
+	exp := []exportAudit{}
+	for number := 1; ; number++ {
+		audits, err := bus.Query(ctx, filter, byTimestamp, page.MustParse(strconv.Itoa(number), strconv.Itoa(rows)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, aud := range audits {
+			exp = append(exp, exportAudit{
+				ID:        aud.ID,
+				ObjID:     aud.ObjID,
+				ObjDomain: aud.ObjDomain.String(),
+				ObjName:   aud.ObjName.String(),
+				ActorID:   aud.ActorID,
+				Action:    aud.Action,
+				Data:      aud.Data,
+				Message:   aud.Message,
+				Timestamp: aud.Timestamp,
+			})
+		}
+
+		if len(audits) < rows {
+			return exp, nil
+		}
+	}
+}
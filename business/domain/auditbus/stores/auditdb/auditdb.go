@@ -0,0 +1,142 @@
+// Package auditdb contains audit related CRUD functionality.
+package auditdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"service/business/domain/auditbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/foundation/logger"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for audit database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db sqlx.ExtContext) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (auditbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new audit record into the database.
+func (s *Store) Create(ctx context.Context, audit auditbus.Audit) error {
+	const q = `
+	INSERT INTO audit
+		(id, obj_id, obj_domain, obj_name, actor_id, action, data, message, timestamp)
+	VALUES
+		(:id, :obj_id, :obj_domain, :obj_name, :actor_id, :action, :data, :message, :timestamp)`
+
+	dbAudit, err := toDBAudit(audit)
+	if err != nil {
+		return err
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, dbAudit); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Redact clears the data and message of the audit records matching the
+// filter.
+func (s *Store) Redact(ctx context.Context, filter auditbus.QueryFilter) error {
+	data := map[string]any{}
+
+	const q = `
+	UPDATE
+		audit
+	SET
+		data = NULL,
+		message = NULL`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing audit records from the database.
+func (s *Store) Query(ctx context.Context, filter auditbus.QueryFilter, orderBy order.By, page page.Page) ([]auditbus.Audit, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		id, obj_id, obj_domain, obj_name, actor_id, action, data, message, timestamp
+	FROM
+		audit`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbAudits []audit
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbAudits); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusAudits(dbAudits)
+}
+
+// Count returns the total number of audit records in the DB.
+func (s *Store) Count(ctx context.Context, filter auditbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		audit`
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
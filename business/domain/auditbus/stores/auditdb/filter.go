@@ -0,0 +1,25 @@
+package auditdb
+
+import (
+	"bytes"
+	"service/business/domain/auditbus"
+	"service/business/sdk/sqldb"
+)
+
+func applyFilter(filter auditbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	f := sqldb.NewFilter(data)
+
+	f.Eq("obj_id", filter.ObjID)
+	f.In("obj_id", filter.ObjIDs)
+
+	if filter.ObjDomain != nil {
+		f.Eq("obj_domain", filter.ObjDomain.String())
+	}
+
+	f.Like("obj_name", filter.ObjName)
+	f.Eq("actor_id", filter.ActorID)
+	f.Eq("action", filter.Action)
+	f.Range("timestamp", filter.Since, filter.Until)
+
+	f.Apply(buf)
+}
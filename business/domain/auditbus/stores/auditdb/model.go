@@ -0,0 +1,86 @@
+package auditdb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"service/business/domain/auditbus"
+	"service/business/types/domain"
+	"service/business/types/name"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type audit struct {
+	ID        uuid.UUID      `db:"id"`
+	ObjID     uuid.UUID      `db:"obj_id"`
+	ObjDomain string         `db:"obj_domain"`
+	ObjName   string         `db:"obj_name"`
+	ActorID   uuid.UUID      `db:"actor_id"`
+	Action    string         `db:"action"`
+	Data      sql.NullString `db:"data"`
+	Message   sql.NullString `db:"message"`
+	Timestamp time.Time      `db:"timestamp"`
+}
+
+func toDBAudit(bus auditbus.Audit) (audit, error) {
+	db := audit{
+		ID:        bus.ID,
+		ObjID:     bus.ObjID,
+		ObjDomain: bus.ObjDomain.String(),
+		ObjName:   bus.ObjName.String(),
+		ActorID:   bus.ActorID,
+		Action:    bus.Action,
+		Data: sql.NullString{
+			String: string(bus.Data),
+			Valid:  len(bus.Data) > 0,
+		},
+		Message: sql.NullString{
+			String: bus.Message,
+			Valid:  bus.Message != "",
+		},
+		Timestamp: bus.Timestamp.UTC(),
+	}
+
+	return db, nil
+}
+
+func toBusAudit(db audit) (auditbus.Audit, error) {
+	dmn, err := domain.Parse(db.ObjDomain)
+	if err != nil {
+		return auditbus.Audit{}, fmt.Errorf("parse domain: %w", err)
+	}
+
+	nme, err := name.Parse(db.ObjName)
+	if err != nil {
+		return auditbus.Audit{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	bus := auditbus.Audit{
+		ID:        db.ID,
+		ObjID:     db.ObjID,
+		ObjDomain: dmn,
+		ObjName:   nme,
+		ActorID:   db.ActorID,
+		Action:    db.Action,
+		Data:      json.RawMessage(db.Data.String),
+		Message:   db.Message.String,
+		Timestamp: db.Timestamp.Local(),
+	}
+
+	return bus, nil
+}
+
+func toBusAudits(dbs []audit) ([]auditbus.Audit, error) {
+	audits := make([]auditbus.Audit, len(dbs))
+	for i, db := range dbs {
+		a, err := toBusAudit(db)
+		if err != nil {
+			return nil, err
+		}
+		audits[i] = a
+	}
+
+	return audits, nil
+}
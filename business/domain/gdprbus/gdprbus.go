@@ -0,0 +1,276 @@
+// Package gdprbus provides business access to data subject export and
+// erasure jobs.
+package gdprbus
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"service/business/domain/userbus"
+	"service/business/sdk/gdpr"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/jobkind"
+	"service/business/types/jobstatus"
+	"service/foundation/logger"
+	"service/foundation/otel"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound     = errors.New("job not found")
+	ErrUserNotFound = errors.New("user not found")
+	ErrNoArchive    = errors.New("job has no archive")
+	ErrNoPending    = errors.New("no pending job")
+	ErrUnexpected   = errors.New("unexpected job kind")
+)
+
+// claimTimeout is how long a job can stay running before it's considered
+// abandoned and is claimed again.
+const claimTimeout = time.Hour
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, job Job) error
+	Update(ctx context.Context, job Job) error
+	Claim(ctx context.Context, now time.Time, stale time.Time) (Job, error)
+	ClearArchives(ctx context.Context, userID uuid.UUID) error
+	QueryByID(ctx context.Context, jobID uuid.UUID) (Job, error)
+}
+
+// Business manages the set of APIs for data subject job access.
+type Business struct {
+	log      *logger.Logger
+	userBus  userbus.ExtBusiness
+	registry *gdpr.Registry
+	storer   Storer
+}
+
+// NewBusiness constructs a data subject job business API for use.
+func NewBusiness(log *logger.Logger, userBus userbus.ExtBusiness, registry *gdpr.Registry, storer Storer) *Business {
+	return &Business{
+		log:      log,
+		userBus:  userBus,
+		registry: registry,
+		storer:   storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		userBus:  userBus,
+		registry: b.registry,
+		storer:   storer,
+	}
+
+	return &bus, nil
+}
+
+// Create records a new job to be picked up by ProcessPending. The user must
+// belong to the tenant in the context, deleted users included.
+func (b *Business) Create(ctx context.Context, actorID uuid.UUID, nj NewJob) (Job, error) {
+	ctx, span := otel.AddSpan(ctx, "business.gdprbus.create")
+	defer span.End()
+
+	var filter userbus.QueryFilter
+	filter.WithUserID(nj.UserID)
+	filter.WithIncludeDeleted(true)
+
+	usrs, err := b.userBus.Query(ctx, filter, userbus.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		return Job{}, fmt.Errorf("query user: userID[%s]: %w", nj.UserID, err)
+	}
+
+	if len(usrs) == 0 {
+		return Job{}, fmt.Errorf("query user: userID[%s]: %w", nj.UserID, ErrUserNotFound)
+	}
+
+	now := time.Now()
+
+	job := Job{
+		ID:          uuid.New(),
+		OrgID:       usrs[0].OrgID,
+		UserID:      nj.UserID,
+		Kind:        nj.Kind,
+		Status:      jobstatus.Pending,
+		RequestedBy: actorID,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, job); err != nil {
+		return Job{}, fmt.Errorf("create: %w", err)
+	}
+
+	return job, nil
+}
+
+// QueryByID finds the job by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, jobID uuid.UUID) (Job, error) {
+	ctx, span := otel.AddSpan(ctx, "business.gdprbus.querybyid")
+	defer span.End()
+
+	job, err := b.storer.QueryByID(ctx, jobID)
+	if err != nil {
+		return Job{}, fmt.Errorf("query: jobID[%s]: %w", jobID, err)
+	}
+
+	return job, nil
+}
+
+// ProcessPending runs pending jobs until there are none left and returns
+// the number of jobs that were run. A job that fails is marked as failed
+// and does not stop the remaining jobs from running. Each job runs in the
+// organization of its user, and a job left running longer than the claim
+// timeout is run again.
+func (b *Business) ProcessPending(ctx context.Context) (int, error) {
+	var processed int
+	for {
+		now := time.Now()
+
+		job, err := b.storer.Claim(ctx, now, now.Add(-claimTimeout))
+		if err != nil {
+			if errors.Is(err, ErrNoPending) {
+				return processed, nil
+			}
+			return processed, fmt.Errorf("claim: %w", err)
+		}
+
+		if _, err := b.process(tenant.Set(ctx, job.OrgID), job); err != nil {
+			return processed, err
+		}
+
+		processed++
+	}
+}
+
+// =============================================================================
+
+func (b *Business) process(ctx context.Context, job Job) (Job, error) {
+	ctx, span := otel.AddSpan(ctx, "business.gdprbus.process")
+	defer span.End()
+
+	var err error
+	switch job.Kind {
+	case jobkind.Export:
+		job.Archive, err = b.export(ctx, job)
+
+	case jobkind.Erase:
+		err = b.erase(ctx, job)
+
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnexpected, job.Kind)
+	}
+
+	now := time.Now()
+
+	job.Status = jobstatus.Completed
+	job.DateUpdated = now
+	job.DateCompleted = now
+
+	if err != nil {
+		b.log.Error(ctx, "gdpr job", "jobID", job.ID, "kind", job.Kind, "ERROR", err)
+
+		job.Status = jobstatus.Failed
+		job.Archive = nil
+		job.Error = err.Error()
+	}
+
+	if err := b.storer.Update(ctx, job); err != nil {
+		return Job{}, fmt.Errorf("update: jobID[%s]: %w", job.ID, err)
+	}
+
+	return job, nil
+}
+
+func (b *Business) export(ctx context.Context, job Job) ([]byte, error) {
+	sections, err := b.registry.Export(ctx, job.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	manifest := struct {
+		JobID       uuid.UUID `json:"jobID"`
+		UserID      uuid.UUID `json:"userID"`
+		Domains     []string  `json:"domains"`
+		DateCreated time.Time `json:"dateCreated"`
+	}{
+		JobID:       job.ID,
+		UserID:      job.UserID,
+		DateCreated: time.Now().UTC(),
+	}
+
+	for _, section := range sections {
+		manifest.Domains = append(manifest.Domains, section.Domain)
+
+		if err := writeFile(zw, section.Domain+".json", section.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	if err := writeFile(zw, "manifest.json", data); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (b *Business) erase(ctx context.Context, job Job) error {
+	if err := b.registry.Erase(ctx, job.RequestedBy, job.UserID); err != nil {
+		return err
+	}
+
+	// Archives produced by earlier exports hold the same personal data.
+	if err := b.storer.ClearArchives(ctx, job.UserID); err != nil {
+		return fmt.Errorf("clear archives: %w", err)
+	}
+
+	return nil
+}
+
+func writeFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,580 @@
+package gdprbus_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"service/business/domain/auditbus"
+	"service/business/domain/gdprbus"
+	"service/business/domain/gdprbus/stores/gdprdb"
+	"service/business/domain/invitebus"
+	"service/business/domain/orgbus"
+	"service/business/domain/orgbus/stores/orgdb"
+	"service/business/domain/userbus"
+	"service/business/domain/userbus/stores/userdb"
+	"service/business/sdk/dbtest"
+	"service/business/sdk/delegate"
+	"service/business/sdk/gdpr"
+	"service/business/sdk/migrate"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/sdk/unitest"
+	"service/business/types/domain"
+	"service/business/types/jobkind"
+	"service/business/types/jobstatus"
+	"service/business/types/name"
+	"service/business/types/role"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+var pageOne = page.MustParse("1", "10")
+
+func Test_GDPR(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_GDPR")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, export(db.BusDomain, sd), "export")
+	unitest.Run(t, erase(db.BusDomain, sd), "erase")
+	unitest.Run(t, process(db, sd), "process")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := tenant.System(context.Background())
+
+	admins, err := userbus.TestSeedUsers(ctx, 1, role.AdminRole, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding admins : %w", err)
+	}
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, role.UserRole, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	na := auditbus.NewAudit{
+		ObjID:     usrs[0].ID,
+		ObjDomain: domain.User,
+		ObjName:   name.MustParse("User"),
+		ActorID:   admins[0].ID,
+		Action:    "created",
+		Data:      map[string]string{"email": usrs[0].Email.Address},
+		Message:   "created user",
+	}
+
+	if _, err := busDomain.Audit.Create(ctx, na); err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding audit : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Admins: []unitest.User{{User: admins[0]}},
+		Users:  []unitest.User{{User: usrs[0]}},
+	}
+
+	return sd, nil
+}
+
+func export(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "archive",
+			ExpResp: []string{"audit.json", "manifest.json", "user.json"},
+			ExcFunc: func(ctx context.Context) any {
+				job, err := runJob(ctx, busDomain, sd, jobkind.Export)
+				if err != nil {
+					return err
+				}
+
+				files, err := unzip(job.Archive)
+				if err != nil {
+					return err
+				}
+
+				if !strings.Contains(files["user.json"], sd.Users[0].Email.Address) {
+					return fmt.Errorf("expected the user's email in the export: %s", files["user.json"])
+				}
+
+				if strings.Contains(files["user.json"], "password") {
+					return fmt.Errorf("the password hash must not be exported: %s", files["user.json"])
+				}
+
+				if !strings.Contains(files["audit.json"], "created user") {
+					return fmt.Errorf("expected the audit record in the export: %s", files["audit.json"])
+				}
+
+				names := make([]string, 0, len(files))
+				for name := range files {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				return names
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func erase(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "pseudonymize",
+			ExpResp: "erased-" + sd.Users[0].ID.String() + "@erased.invalid",
+			ExcFunc: func(ctx context.Context) any {
+				exportJob, err := runJob(ctx, busDomain, sd, jobkind.Export)
+				if err != nil {
+					return err
+				}
+
+				if _, err := runJob(ctx, busDomain, sd, jobkind.Erase); err != nil {
+					return err
+				}
+
+				exportJob, err = busDomain.GDPR.QueryByID(ctx, exportJob.ID)
+				if err != nil {
+					return err
+				}
+
+				if len(exportJob.Archive) != 0 {
+					return fmt.Errorf("expected earlier export archives to be removed")
+				}
+
+				var filter auditbus.QueryFilter
+				filter.WithObjID(sd.Users[0].ID)
+
+				audits, err := busDomain.Audit.Query(ctx, filter, auditbus.DefaultOrderBy, pageOne)
+				if err != nil {
+					return err
+				}
+
+				for _, aud := range audits {
+					if len(aud.Data) != 0 || aud.Message != "" {
+						return fmt.Errorf("expected audit record to be redacted: %+v", aud)
+					}
+				}
+
+				var usrFilter userbus.QueryFilter
+				usrFilter.WithUserID(sd.Users[0].ID)
+				usrFilter.WithIncludeDeleted(true)
+
+				usrs, err := busDomain.User.Query(ctx, usrFilter, userbus.DefaultOrderBy, pageOne)
+				if err != nil {
+					return err
+				}
+
+				if len(usrs) != 1 || usrs[0].Enabled || !usrs[0].Deleted() {
+					return fmt.Errorf("expected the user to be disabled and deleted: %+v", usrs)
+				}
+
+				return usrs[0].Email.Address
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "nothing-exported-survives",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				email, _ := mail.ParseAddress("subject@ardanlabs.com")
+
+				ni := invitebus.NewInvitation{
+					Email:      *email,
+					Roles:      []role.Role{role.UserRole},
+					Department: "Sales",
+				}
+
+				inv, err := busDomain.Invite.Create(ctx, sd.Admins[0].ID, ni)
+				if err != nil {
+					return err
+				}
+
+				ai := invitebus.AcceptInvitation{
+					Token:    busDomain.InviteSender.Token(inv),
+					Name:     name.MustParse("Data Subject"),
+					Password: "gophers",
+				}
+
+				_, usr, err := busDomain.Invite.Accept(ctx, ai)
+				if err != nil {
+					return err
+				}
+
+				na := auditbus.NewAudit{
+					ObjID:     sd.Admins[0].ID,
+					ObjDomain: domain.User,
+					ObjName:   name.MustParse("User"),
+					ActorID:   usr.ID,
+					Action:    "updated",
+					Data:      map[string]string{"by": usr.Email.Address},
+					Message:   "updated by " + usr.Name.String(),
+				}
+
+				if _, err := busDomain.Audit.Create(ctx, na); err != nil {
+					return err
+				}
+
+				subject := unitest.SeedData{
+					Admins: sd.Admins,
+					Users:  []unitest.User{{User: usr}},
+				}
+
+				exportJob, err := runJob(ctx, busDomain, subject, jobkind.Export)
+				if err != nil {
+					return err
+				}
+
+				files, err := unzip(exportJob.Archive)
+				if err != nil {
+					return err
+				}
+
+				type exportedAudit struct {
+					ID uuid.UUID `json:"id"`
+				}
+
+				var exported struct {
+					About   []exportedAudit `json:"about"`
+					Actor   []exportedAudit `json:"actor"`
+					Related []exportedAudit `json:"related"`
+				}
+				if err := json.Unmarshal([]byte(files["audit.json"]), &exported); err != nil {
+					return err
+				}
+
+				if len(exported.Actor) == 0 || len(exported.Related) == 0 {
+					return fmt.Errorf("expected actor and invitation records in the export: %s", files["audit.json"])
+				}
+
+				exportedIDs := make(map[uuid.UUID]bool)
+				for _, auds := range [][]exportedAudit{exported.About, exported.Actor, exported.Related} {
+					for _, aud := range auds {
+						exportedIDs[aud.ID] = true
+					}
+				}
+
+				if _, err := runJob(ctx, busDomain, subject, jobkind.Erase); err != nil {
+					return err
+				}
+
+				audits, err := busDomain.Audit.Query(ctx, auditbus.QueryFilter{}, auditbus.DefaultOrderBy, page.MustParse("1", "1000"))
+				if err != nil {
+					return err
+				}
+
+				var survived int
+				for _, aud := range audits {
+					if !exportedIDs[aud.ID] {
+						continue
+					}
+
+					if len(aud.Data) != 0 || aud.Message != "" {
+						survived++
+					}
+
+					for _, value := range []string{usr.Email.Address, usr.Name.String(), usr.Department} {
+						if strings.Contains(string(aud.Data), value) || strings.Contains(aud.Message, value) {
+							return fmt.Errorf("expected %q to be erased from audit record: %+v", value, aud)
+						}
+					}
+				}
+
+				var usrFilter userbus.QueryFilter
+				usrFilter.WithUserID(usr.ID)
+				usrFilter.WithIncludeDeleted(true)
+
+				erased, err := busDomain.User.Query(ctx, usrFilter, userbus.DefaultOrderBy, pageOne)
+				if err != nil {
+					return err
+				}
+
+				if len(erased) != 1 || erased[0].Email.Address == usr.Email.Address || erased[0].Name.String() == usr.Name.String() || erased[0].Department != "" {
+					return fmt.Errorf("expected the exported user details to be erased: %+v", erased)
+				}
+
+				return survived
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func process(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	busDomain := db.BusDomain
+
+	table := []unitest.Table{
+		{
+			Name:    "other-org",
+			ExpResp: gdprbus.ErrUserNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				org, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: "GDPR Org"})
+				if err != nil {
+					return err
+				}
+
+				other, err := userbus.TestSeedUsers(tenant.Set(ctx, org.ID), 1, role.UserRole, busDomain.User)
+				if err != nil {
+					return err
+				}
+
+				nj := gdprbus.NewJob{
+					UserID: other[0].ID,
+					Kind:   jobkind.Export,
+				}
+
+				// A job started by a system call belongs to the org of the
+				// user and is run in that org.
+				job, err := busDomain.GDPR.Create(ctx, sd.Admins[0].ID, nj)
+				if err != nil {
+					return err
+				}
+
+				if job.OrgID != org.ID {
+					return fmt.Errorf("expected the job to belong to the user's org, got %s", job.OrgID)
+				}
+
+				if _, err := busDomain.GDPR.ProcessPending(ctx); err != nil {
+					return err
+				}
+
+				job, err = busDomain.GDPR.QueryByID(ctx, job.ID)
+				if err != nil {
+					return err
+				}
+
+				if job.Status != jobstatus.Completed {
+					return fmt.Errorf("expected job to complete, got %s: %s", job.Status, job.Error)
+				}
+
+				// The job and the user can't be reached from another org.
+				defaultCtx := tenant.Set(ctx, tenant.DefaultOrgID)
+
+				if _, err := busDomain.GDPR.QueryByID(defaultCtx, job.ID); !errors.Is(err, gdprbus.ErrNotFound) {
+					return fmt.Errorf("query by id: expected not found, got %v", err)
+				}
+
+				_, err = busDomain.GDPR.Create(defaultCtx, sd.Admins[0].ID, nj)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "abandoned",
+			ExpResp: jobstatus.Completed.String(),
+			ExcFunc: func(ctx context.Context) any {
+				nj := gdprbus.NewJob{
+					UserID: sd.Users[0].ID,
+					Kind:   jobkind.Export,
+				}
+
+				job, err := busDomain.GDPR.Create(ctx, sd.Admins[0].ID, nj)
+				if err != nil {
+					return err
+				}
+
+				// Leave the job as an instance that failed to record the
+				// outcome would.
+				const q = `UPDATE gdpr_jobs SET status = :status, date_updated = :date_updated WHERE job_id = :job_id`
+
+				data := map[string]any{
+					"job_id":       job.ID,
+					"status":       jobstatus.Running.String(),
+					"date_updated": time.Now().Add(-2 * time.Hour),
+				}
+
+				if err := sqldb.NamedExecContext(ctx, db.Log, db.DB, q, data); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.GDPR.ProcessPending(ctx); err != nil {
+					return err
+				}
+
+				job, err = busDomain.GDPR.QueryByID(ctx, job.ID)
+				if err != nil {
+					return err
+				}
+
+				return job.Status.String()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "tenant-schema",
+			ExpResp: jobstatus.Completed.String(),
+			ExcFunc: func(ctx context.Context) any {
+				org, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: "Schema Org"})
+				if err != nil {
+					return err
+				}
+
+				if err := migrate.MigrateTenants(ctx, db.Config); err != nil {
+					return err
+				}
+
+				cluster, err := sqldb.OpenCluster(sqldb.ClusterConfig{
+					Config:  db.Config,
+					Schemas: tenant.Schema,
+				})
+				if err != nil {
+					return err
+				}
+				defer cluster.Close()
+
+				orgBus := orgbus.NewBusiness(db.Log, orgdb.NewStore(db.Log, cluster))
+				userBus := userbus.NewBusiness(db.Log, delegate.New(db.Log), userdb.NewStore(db.Log, cluster))
+
+				registry := gdpr.New(db.Log)
+				userbus.RegisterGDPR(registry, userBus)
+
+				gdprBus := gdprbus.NewBusiness(db.Log, userBus, registry, gdprdb.NewStore(db.Log, cluster))
+
+				orgCtx := tenant.Set(ctx, org.ID)
+
+				usrs, err := userbus.TestSeedUsers(orgCtx, 1, role.UserRole, userBus)
+				if err != nil {
+					return err
+				}
+
+				nj := gdprbus.NewJob{
+					UserID: usrs[0].ID,
+					Kind:   jobkind.Export,
+				}
+
+				job, err := gdprBus.Create(orgCtx, usrs[0].ID, nj)
+				if err != nil {
+					return err
+				}
+
+				// A system call only reaches the shared schema.
+				if _, err := gdprBus.ProcessPending(ctx); err != nil {
+					return err
+				}
+
+				if job, err = gdprBus.QueryByID(orgCtx, job.ID); err != nil {
+					return err
+				}
+
+				if job.Status != jobstatus.Pending {
+					return fmt.Errorf("expected the job to be left to the tenant pass, got %s", job.Status)
+				}
+
+				process := func(ctx context.Context) error {
+					_, err := gdprBus.ProcessPending(ctx)
+					return err
+				}
+
+				if err := orgBus.ForEach(ctx, process); err != nil {
+					return err
+				}
+
+				if job, err = gdprBus.QueryByID(orgCtx, job.ID); err != nil {
+					return err
+				}
+
+				return job.Status.String()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+func runJob(ctx context.Context, busDomain dbtest.BusDomain, sd unitest.SeedData, kind jobkind.Kind) (gdprbus.Job, error) {
+	nj := gdprbus.NewJob{
+		UserID: sd.Users[0].ID,
+		Kind:   kind,
+	}
+
+	job, err := busDomain.GDPR.Create(ctx, sd.Admins[0].ID, nj)
+	if err != nil {
+		return gdprbus.Job{}, err
+	}
+
+	if _, err := busDomain.GDPR.ProcessPending(ctx); err != nil {
+		return gdprbus.Job{}, err
+	}
+
+	job, err = busDomain.GDPR.QueryByID(ctx, job.ID)
+	if err != nil {
+		return gdprbus.Job{}, err
+	}
+
+	if job.Status != jobstatus.Completed {
+		return gdprbus.Job{}, fmt.Errorf("expected job to complete, got %s: %s", job.Status, job.Error)
+	}
+
+	return job, nil
+}
+
+func unzip(archive []byte) (map[string]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("file %s is not valid json", f.Name)
+		}
+
+		files[f.Name] = string(data)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,31 @@
+package gdprbus
+
+import (
+	"service/business/types/jobkind"
+	"service/business/types/jobstatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job represents a data subject export or erasure request for a user. The
+// job belongs to the organization of that user.
+type Job struct {
+	ID            uuid.UUID
+	OrgID         uuid.UUID
+	UserID        uuid.UUID
+	Kind          jobkind.Kind
+	Status        jobstatus.Status
+	Archive       []byte
+	Error         string
+	RequestedBy   uuid.UUID
+	DateCreated   time.Time
+	DateUpdated   time.Time
+	DateCompleted time.Time
+}
+
+// NewJob contains information needed to request a new job.
+type NewJob struct {
+	UserID uuid.UUID
+	Kind   jobkind.Kind
+}
@@ -0,0 +1,41 @@
+package gdprdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"service/business/domain/gdprbus"
+	"service/business/sdk/tenant"
+
+	"github.com/google/uuid"
+)
+
+// applyTenant adds the condition restricting a statement that already has
+// a WHERE clause to the tenant in the context.
+func applyTenant(ctx context.Context, data map[string]any, buf *bytes.Buffer) error {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	if scoped {
+		data["org_id"] = orgID
+		buf.WriteString(" AND org_id = :org_id")
+	}
+
+	return nil
+}
+
+// checkTenant validates the job belongs to the tenant in the context. A job
+// of another tenant is reported as not found so its existence isn't
+// revealed.
+func checkTenant(ctx context.Context, orgID uuid.UUID) error {
+	if err := tenant.Check(ctx, orgID); err != nil {
+		if errors.Is(err, tenant.ErrCrossTenant) {
+			return gdprbus.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
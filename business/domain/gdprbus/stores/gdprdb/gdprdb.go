@@ -0,0 +1,198 @@
+// Package gdprdb contains data subject job related CRUD functionality.
+package gdprdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"service/business/domain/gdprbus"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/jobstatus"
+	"service/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for data subject job database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db sqlx.ExtContext) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (gdprbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new job into the database. The job must belong to the
+// tenant in the context unless the call is a system one.
+func (s *Store) Create(ctx context.Context, job gdprbus.Job) error {
+	if err := tenant.Check(ctx, job.OrgID); err != nil {
+		return fmt.Errorf("check: orgID[%s]: %w", job.OrgID, err)
+	}
+
+	const q = `
+	INSERT INTO gdpr_jobs
+		(job_id, org_id, user_id, kind, status, archive, error, requested_by, date_created, date_updated, date_completed)
+	VALUES
+		(:job_id, :org_id, :user_id, :kind, :status, :archive, :error, :requested_by, :date_created, :date_updated, :date_completed)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBJob(job)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a job document in the database.
+func (s *Store) Update(ctx context.Context, job gdprbus.Job) error {
+	if err := checkTenant(ctx, job.OrgID); err != nil {
+		return fmt.Errorf("check: %w", err)
+	}
+
+	const q = `
+	UPDATE
+		gdpr_jobs
+	SET
+		"status" = :status,
+		"archive" = :archive,
+		"error" = :error,
+		"date_updated" = :date_updated,
+		"date_completed" = :date_completed
+	WHERE
+		job_id = :job_id AND
+		org_id = :org_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBJob(job)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Claim marks the oldest pending job as running and returns it. A job that
+// has been running since before the stale time is claimed again, since the
+// instance running it failed to record its outcome. Rows locked by another
+// instance are skipped so a job is only claimed by one at a time.
+func (s *Store) Claim(ctx context.Context, now time.Time, stale time.Time) (gdprbus.Job, error) {
+	data := map[string]any{
+		"pending":      jobstatus.Pending.String(),
+		"running":      jobstatus.Running.String(),
+		"date_updated": now.UTC(),
+		"stale":        stale.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		gdpr_jobs
+	SET
+		"status" = :running,
+		"date_updated" = :date_updated
+	WHERE
+		job_id = (
+			SELECT job_id FROM gdpr_jobs
+			WHERE
+				(status = :pending OR (status = :running AND date_updated < :stale))`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return gdprbus.Job{}, fmt.Errorf("scope: %w", err)
+	}
+
+	buf.WriteString(`
+			ORDER BY date_created
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING
+		job_id, org_id, user_id, kind, status, archive, error, requested_by, date_created, date_updated, date_completed`)
+
+	var dbJob job
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbJob); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return gdprbus.Job{}, gdprbus.ErrNoPending
+		}
+		return gdprbus.Job{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusJob(dbJob)
+}
+
+// ClearArchives removes the export archives produced for the specified user.
+func (s *Store) ClearArchives(ctx context.Context, userID uuid.UUID) error {
+	data := map[string]any{
+		"user_id": userID,
+	}
+
+	const q = `
+	UPDATE
+		gdpr_jobs
+	SET
+		archive = NULL
+	WHERE
+		user_id = :user_id`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return fmt.Errorf("scope: %w", err)
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified job from the database.
+func (s *Store) QueryByID(ctx context.Context, jobID uuid.UUID) (gdprbus.Job, error) {
+	data := map[string]any{
+		"job_id": jobID.String(),
+	}
+
+	const q = `
+	SELECT
+		job_id, org_id, user_id, kind, status, archive, error, requested_by, date_created, date_updated, date_completed
+	FROM
+		gdpr_jobs
+	WHERE
+		job_id = :job_id`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return gdprbus.Job{}, fmt.Errorf("scope: %w", err)
+	}
+
+	var dbJob job
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbJob); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return gdprbus.Job{}, fmt.Errorf("db: %w", gdprbus.ErrNotFound)
+		}
+		return gdprbus.Job{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusJob(dbJob)
+}
@@ -0,0 +1,79 @@
+package gdprdb
+
+import (
+	"database/sql"
+	"fmt"
+	"service/business/domain/gdprbus"
+	"service/business/types/jobkind"
+	"service/business/types/jobstatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type job struct {
+	ID            uuid.UUID      `db:"job_id"`
+	OrgID         uuid.UUID      `db:"org_id"`
+	UserID        uuid.UUID      `db:"user_id"`
+	Kind          string         `db:"kind"`
+	Status        string         `db:"status"`
+	Archive       []byte         `db:"archive"`
+	Error         sql.NullString `db:"error"`
+	RequestedBy   uuid.UUID      `db:"requested_by"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+	DateCompleted sql.NullTime   `db:"date_completed"`
+}
+
+func toDBJob(bus gdprbus.Job) job {
+	return job{
+		ID:      bus.ID,
+		OrgID:   bus.OrgID,
+		UserID:  bus.UserID,
+		Kind:    bus.Kind.String(),
+		Status:  bus.Status.String(),
+		Archive: bus.Archive,
+		Error: sql.NullString{
+			String: bus.Error,
+			Valid:  bus.Error != "",
+		},
+		RequestedBy: bus.RequestedBy,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+		DateCompleted: sql.NullTime{
+			Time:  bus.DateCompleted.UTC(),
+			Valid: !bus.DateCompleted.IsZero(),
+		},
+	}
+}
+
+func toBusJob(db job) (gdprbus.Job, error) {
+	kind, err := jobkind.Parse(db.Kind)
+	if err != nil {
+		return gdprbus.Job{}, fmt.Errorf("parse kind: %w", err)
+	}
+
+	status, err := jobstatus.Parse(db.Status)
+	if err != nil {
+		return gdprbus.Job{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	bus := gdprbus.Job{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		UserID:      db.UserID,
+		Kind:        kind,
+		Status:      status,
+		Archive:     db.Archive,
+		Error:       db.Error.String,
+		RequestedBy: db.RequestedBy,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	if db.DateCompleted.Valid {
+		bus.DateCompleted = db.DateCompleted.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,161 @@
+// Package inviteaudit provides an extension for invitebus that adds
+// auditing functionality.
+package inviteaudit
+
+import (
+	"context"
+	"fmt"
+	"service/business/domain/auditbus"
+	"service/business/domain/invitebus"
+	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/types/domain"
+	"service/business/types/name"
+
+	"github.com/google/uuid"
+)
+
+// Set of audit actions recorded by this extension.
+const (
+	ActionCreated  = "created"
+	ActionResent   = "resent"
+	ActionRevoked  = "revoked"
+	ActionAccepted = "accepted"
+)
+
+// objName is recorded as the audit object name since an invitation has no
+// name of its own until it has been accepted.
+var objName = name.MustParse("Invitation")
+
+// Extension provides a wrapper for audit functionality around the invitebus.
+type Extension struct {
+	bus      invitebus.ExtBusiness
+	auditBus *auditbus.Business
+}
+
+// NewExtension constructs a new extension that wraps the invitebus with audit.
+func NewExtension(auditBus *auditbus.Business) invitebus.Extension {
+	return func(bus invitebus.ExtBusiness) invitebus.ExtBusiness {
+		return &Extension{
+			bus:      bus,
+			auditBus: auditBus,
+		}
+	}
+}
+
+// NewWithTx applies auditing inside the same transaction so an audit record
+// is only kept when the step it describes is committed.
+func (ext *Extension) NewWithTx(tx sqldb.CommitRollbacker) (invitebus.ExtBusiness, error) {
+	bus, err := ext.bus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	auditBus, err := ext.auditBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extension{
+		bus:      bus,
+		auditBus: auditBus,
+	}, nil
+}
+
+// Create applies auditing to the invitation creation process.
+func (ext *Extension) Create(ctx context.Context, actorID uuid.UUID, ni invitebus.NewInvitation) (invitebus.Invitation, error) {
+	inv, err := ext.bus.Create(ctx, actorID, ni)
+	if err != nil {
+		return invitebus.Invitation{}, err
+	}
+
+	if err := ext.audit(ctx, actorID, inv, ActionCreated, ni, "invitation created"); err != nil {
+		return invitebus.Invitation{}, err
+	}
+
+	return inv, nil
+}
+
+// Resend applies auditing to the invitation resend process.
+func (ext *Extension) Resend(ctx context.Context, actorID uuid.UUID, inv invitebus.Invitation) (invitebus.Invitation, error) {
+	inv, err := ext.bus.Resend(ctx, actorID, inv)
+	if err != nil {
+		return invitebus.Invitation{}, err
+	}
+
+	if err := ext.audit(ctx, actorID, inv, ActionResent, nil, "invitation resent"); err != nil {
+		return invitebus.Invitation{}, err
+	}
+
+	return inv, nil
+}
+
+// Revoke applies auditing to the invitation revoke process.
+func (ext *Extension) Revoke(ctx context.Context, actorID uuid.UUID, inv invitebus.Invitation) (invitebus.Invitation, error) {
+	inv, err := ext.bus.Revoke(ctx, actorID, inv)
+	if err != nil {
+		return invitebus.Invitation{}, err
+	}
+
+	if err := ext.audit(ctx, actorID, inv, ActionRevoked, nil, "invitation revoked"); err != nil {
+		return invitebus.Invitation{}, err
+	}
+
+	return inv, nil
+}
+
+// Accept applies auditing to the invitation accept process. The new user is
+// recorded as the actor.
+func (ext *Extension) Accept(ctx context.Context, ai invitebus.AcceptInvitation) (invitebus.Invitation, userbus.User, error) {
+	inv, usr, err := ext.bus.Accept(ctx, ai)
+	if err != nil {
+		return invitebus.Invitation{}, userbus.User{}, err
+	}
+
+	data := struct {
+		UserID uuid.UUID
+	}{
+		UserID: usr.ID,
+	}
+
+	if err := ext.audit(ctx, usr.ID, inv, ActionAccepted, data, "invitation accepted"); err != nil {
+		return invitebus.Invitation{}, userbus.User{}, err
+	}
+
+	return inv, usr, nil
+}
+
+// Query does not apply auditing.
+func (ext *Extension) Query(ctx context.Context, filter invitebus.QueryFilter, orderBy order.By, page page.Page) ([]invitebus.Invitation, error) {
+	return ext.bus.Query(ctx, filter, orderBy, page)
+}
+
+// Count does not apply auditing.
+func (ext *Extension) Count(ctx context.Context, filter invitebus.QueryFilter) (int, error) {
+	return ext.bus.Count(ctx, filter)
+}
+
+// QueryByID does not apply auditing.
+func (ext *Extension) QueryByID(ctx context.Context, invitationID uuid.UUID) (invitebus.Invitation, error) {
+	return ext.bus.QueryByID(ctx, invitationID)
+}
+
+func (ext *Extension) audit(ctx context.Context, actorID uuid.UUID, inv invitebus.Invitation, action string, data any, message string) error {
+	na := auditbus.NewAudit{
+		ObjID:     inv.ID,
+		ObjDomain: domain.Invitation,
+		ObjName:   objName,
+		ActorID:   actorID,
+		Action:    action,
+		Data:      data,
+		Message:   message,
+	}
+
+	if _, err := ext.auditBus.Create(ctx, na); err != nil {
+		return fmt.Errorf("audit: %s: %w", action, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package inviteotel provides an extension for invitebus that adds
+// otel tracking.
+package inviteotel
+
+import (
+	"context"
+	"service/business/domain/invitebus"
+	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/foundation/otel"
+
+	"github.com/google/uuid"
+)
+
+// Extension provides a wrapper for otel functionality around the invitebus.
+type Extension struct {
+	bus invitebus.ExtBusiness
+}
+
+// NewExtension constructs a new extension that wraps the invitebus with otel.
+func NewExtension() invitebus.Extension {
+	return func(bus invitebus.ExtBusiness) invitebus.ExtBusiness {
+		return &Extension{
+			bus: bus,
+		}
+	}
+}
+
+// NewWithTx does not apply otel.
+func (ext *Extension) NewWithTx(tx sqldb.CommitRollbacker) (invitebus.ExtBusiness, error) {
+	return ext.bus.NewWithTx(tx)
+}
+
+// Create applies otel to the invitation creation process.
+func (ext *Extension) Create(ctx context.Context, actorID uuid.UUID, ni invitebus.NewInvitation) (invitebus.Invitation, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitebus.create")
+	defer span.End()
+
+	return ext.bus.Create(ctx, actorID, ni)
+}
+
+// Resend applies otel to the invitation resend process.
+func (ext *Extension) Resend(ctx context.Context, actorID uuid.UUID, inv invitebus.Invitation) (invitebus.Invitation, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitebus.resend")
+	defer span.End()
+
+	return ext.bus.Resend(ctx, actorID, inv)
+}
+
+// Revoke applies otel to the invitation revoke process.
+func (ext *Extension) Revoke(ctx context.Context, actorID uuid.UUID, inv invitebus.Invitation) (invitebus.Invitation, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitebus.revoke")
+	defer span.End()
+
+	return ext.bus.Revoke(ctx, actorID, inv)
+}
+
+// Accept applies otel to the invitation accept process.
+func (ext *Extension) Accept(ctx context.Context, ai invitebus.AcceptInvitation) (invitebus.Invitation, userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitebus.accept")
+	defer span.End()
+
+	return ext.bus.Accept(ctx, ai)
+}
+
+// Query applies otel to the invitation query process.
+func (ext *Extension) Query(ctx context.Context, filter invitebus.QueryFilter, orderBy order.By, page page.Page) ([]invitebus.Invitation, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitebus.query")
+	defer span.End()
+
+	return ext.bus.Query(ctx, filter, orderBy, page)
+}
+
+// Count applies otel to the invitation count process.
+func (ext *Extension) Count(ctx context.Context, filter invitebus.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitebus.count")
+	defer span.End()
+
+	return ext.bus.Count(ctx, filter)
+}
+
+// QueryByID applies otel to the invitation query by id process.
+func (ext *Extension) QueryByID(ctx context.Context, invitationID uuid.UUID) (invitebus.Invitation, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitebus.querybyid")
+	defer span.End()
+
+	return ext.bus.QueryByID(ctx, invitationID)
+}
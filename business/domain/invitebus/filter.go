@@ -0,0 +1,52 @@
+package invitebus
+
+import (
+	"net/mail"
+	"service/business/types/invitestatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ID               *uuid.UUID
+	Email            *mail.Address
+	Status           *invitestatus.Status
+	InvitedBy        *uuid.UUID
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
+
+// WithInvitationID sets the ID field of the QueryFilter value.
+func (qf *QueryFilter) WithInvitationID(invitationID uuid.UUID) {
+	qf.ID = &invitationID
+}
+
+// WithEmail sets the Email field of the QueryFilter value.
+func (qf *QueryFilter) WithEmail(email mail.Address) {
+	qf.Email = &email
+}
+
+// WithStatus sets the Status field of the QueryFilter value.
+func (qf *QueryFilter) WithStatus(status invitestatus.Status) {
+	qf.Status = &status
+}
+
+// WithInvitedBy sets the InvitedBy field of the QueryFilter value.
+func (qf *QueryFilter) WithInvitedBy(userID uuid.UUID) {
+	qf.InvitedBy = &userID
+}
+
+// WithStartDateCreated sets the StartCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithStartDateCreated(startDate time.Time) {
+	d := startDate.UTC()
+	qf.StartCreatedDate = &d
+}
+
+// WithEndCreatedDate sets the EndCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndCreatedDate = &d
+}
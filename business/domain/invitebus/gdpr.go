@@ -0,0 +1,57 @@
+package invitebus
+
+import (
+	"context"
+	"fmt"
+	"service/business/domain/userbus"
+	"service/business/sdk/page"
+	"service/business/sdk/tenant"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// GDPRInvitations returns a function finding the invitations sent to the
+// email of a user within the user's organization. Their audit records are
+// kept against the invitation and the invitee's email, not the user, so
+// the audit domain uses it to export and redact them with the user's data.
+// It must run before the user is erased, while the email still matches.
+func GDPRInvitations(bus ExtBusiness, userBus userbus.ExtBusiness) func(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return func(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+		var usrFilter userbus.QueryFilter
+		usrFilter.WithUserID(userID)
+		usrFilter.WithIncludeDeleted(true)
+
+		usrs, err := userBus.Query(ctx, usrFilter, userbus.DefaultOrderBy, page.MustParse("1", "1"))
+		if err != nil {
+			return nil, fmt.Errorf("query user: userID[%s]: %w", userID, err)
+		}
+
+		if len(usrs) == 0 {
+			return nil, nil
+		}
+
+		ctx = tenant.Set(ctx, usrs[0].OrgID)
+
+		var filter QueryFilter
+		filter.WithEmail(usrs[0].Email)
+
+		const rows = 100
+
+		var ids []uuid.UUID
+		for number := 1; ; number++ {
+			invs, err := bus.Query(ctx, filter, DefaultOrderBy, page.MustParse(strconv.Itoa(number), strconv.Itoa(rows)))
+			if err != nil {
+				return nil, fmt.Errorf("query: %w", err)
+			}
+
+			for _, inv := range invs {
+				ids = append(ids, inv.ID)
+			}
+
+			if len(invs) < rows {
+				return ids, nil
+			}
+		}
+	}
+}
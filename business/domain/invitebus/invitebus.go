@@ -0,0 +1,333 @@
+// Package invitebus provides business access to the invitation domain.
+package invitebus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/invitestatus"
+	"service/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound      = errors.New("invitation not found")
+	ErrUserExists    = errors.New("a user with this email already exists")
+	ErrPendingExists = errors.New("a pending invitation already exists for this email")
+	ErrNotPending    = errors.New("invitation is no longer pending")
+	ErrExpired       = errors.New("invitation has expired")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, inv Invitation) error
+	Update(ctx context.Context, inv Invitation) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Invitation, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, invitationID uuid.UUID) (Invitation, error)
+	QueryByTokenHash(ctx context.Context, tokenHash []byte) (Invitation, error)
+}
+
+// Sender declares the behavior this package needs to deliver the single-use
+// link for an invitation to the invitee.
+type Sender interface {
+	Send(ctx context.Context, inv Invitation, token string) error
+}
+
+// ExtBusiness interface provides support for extensions that wrap extra functionality
+// around the core busines logic.
+type ExtBusiness interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (ExtBusiness, error)
+	Create(ctx context.Context, actorID uuid.UUID, ni NewInvitation) (Invitation, error)
+	Resend(ctx context.Context, actorID uuid.UUID, inv Invitation) (Invitation, error)
+	Revoke(ctx context.Context, actorID uuid.UUID, inv Invitation) (Invitation, error)
+	Accept(ctx context.Context, ai AcceptInvitation) (Invitation, userbus.User, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Invitation, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, invitationID uuid.UUID) (Invitation, error)
+}
+
+// Extension is a function that wraps a new layer of business logic
+// around the existing business logic.
+type Extension func(ExtBusiness) ExtBusiness
+
+// Business manages the set of APIs for invitation access.
+type Business struct {
+	log     *logger.Logger
+	userBus userbus.ExtBusiness
+	sender  Sender
+	storer  Storer
+	ttl     time.Duration
+}
+
+// NewBusiness constructs an invitation business API for use. The ttl
+// specifies how long an invitation link remains valid.
+func NewBusiness(log *logger.Logger, userBus userbus.ExtBusiness, sender Sender, storer Storer, ttl time.Duration, extensions ...Extension) ExtBusiness {
+	b := ExtBusiness(&Business{
+		log:     log,
+		userBus: userBus,
+		sender:  sender,
+		storer:  storer,
+		ttl:     ttl,
+	})
+
+	for i := len(extensions) - 1; i >= 0; i-- {
+		ext := extensions[i]
+		if ext != nil {
+			b = ext(b)
+		}
+	}
+
+	return b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (ExtBusiness, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:     b.log,
+		userBus: userBus,
+		sender:  b.sender,
+		storer:  storer,
+		ttl:     b.ttl,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new invitation to the system and sends the invitee a
+// single-use link to activate their account. The link is sent once the
+// transaction the call runs in commits.
+func (b *Business) Create(ctx context.Context, actorID uuid.UUID, ni NewInvitation) (Invitation, error) {
+	orgID := ni.OrgID
+	if orgID == uuid.Nil {
+		orgID = tenant.DefaultOrgID
+		if v, ok := tenant.Get(ctx); ok {
+			orgID = v
+		}
+	}
+
+	if err := tenant.Check(ctx, orgID); err != nil {
+		return Invitation{}, fmt.Errorf("check: orgID[%s]: %w", orgID, err)
+	}
+
+	// Emails are only unique within an org, so only a user of the org the
+	// invitation is for blocks it.
+	_, err := b.userBus.QueryByEmail(tenant.Set(ctx, orgID), ni.Email)
+	switch {
+	case err == nil:
+		return Invitation{}, ErrUserExists
+	case !errors.Is(err, userbus.ErrNotFound):
+		return Invitation{}, fmt.Errorf("query: email[%s]: %w", ni.Email.Address, err)
+	}
+
+	token, hash, err := generateToken()
+	if err != nil {
+		return Invitation{}, fmt.Errorf("generatetoken: %w", err)
+	}
+
+	now := time.Now()
+
+	inv := Invitation{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		Email:       ni.Email,
+		Roles:       ni.Roles,
+		Department:  ni.Department,
+		Status:      invitestatus.Pending,
+		TokenHash:   hash,
+		InvitedBy:   actorID,
+		DateExpires: now.Add(b.ttl),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, inv); err != nil {
+		return Invitation{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.send(ctx, inv, token)
+
+	return inv, nil
+}
+
+// Resend issues a new single-use link for a pending invitation, invalidating
+// the previous one, and extends the expiration. The link is sent once the
+// transaction the call runs in commits.
+func (b *Business) Resend(ctx context.Context, actorID uuid.UUID, inv Invitation) (Invitation, error) {
+	if inv.Status != invitestatus.Pending {
+		return Invitation{}, ErrNotPending
+	}
+
+	token, hash, err := generateToken()
+	if err != nil {
+		return Invitation{}, fmt.Errorf("generatetoken: %w", err)
+	}
+
+	now := time.Now()
+
+	inv.TokenHash = hash
+	inv.DateExpires = now.Add(b.ttl)
+	inv.DateUpdated = now
+
+	if err := b.storer.Update(ctx, inv); err != nil {
+		return Invitation{}, fmt.Errorf("update: %w", err)
+	}
+
+	b.send(ctx, inv, token)
+
+	return inv, nil
+}
+
+// Revoke cancels a pending invitation so its link can no longer be used.
+func (b *Business) Revoke(ctx context.Context, actorID uuid.UUID, inv Invitation) (Invitation, error) {
+	if inv.Status != invitestatus.Pending {
+		return Invitation{}, ErrNotPending
+	}
+
+	inv.Status = invitestatus.Revoked
+	inv.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, inv); err != nil {
+		return Invitation{}, fmt.Errorf("update: %w", err)
+	}
+
+	return inv, nil
+}
+
+// Accept activates the invitation identified by the token, creating the
+// user with the password chosen by the invitee. The call must run inside a
+// transaction for the user and the invitation to be updated atomically.
+func (b *Business) Accept(ctx context.Context, ai AcceptInvitation) (Invitation, userbus.User, error) {
+	inv, err := b.storer.QueryByTokenHash(ctx, hashToken(ai.Token))
+	if err != nil {
+		return Invitation{}, userbus.User{}, fmt.Errorf("query: %w", err)
+	}
+
+	if inv.Status != invitestatus.Pending {
+		return Invitation{}, userbus.User{}, ErrNotPending
+	}
+
+	now := time.Now()
+
+	if inv.Expired(now) {
+		return Invitation{}, userbus.User{}, ErrExpired
+	}
+
+	// A system call looks the token up across organizations, so the
+	// invitation is checked against the one the link was for as well.
+	if err := tenant.Check(ctx, inv.OrgID); err != nil {
+		if errors.Is(err, tenant.ErrCrossTenant) {
+			return Invitation{}, userbus.User{}, ErrNotFound
+		}
+		return Invitation{}, userbus.User{}, fmt.Errorf("check: %w", err)
+	}
+
+	// The invitee isn't authenticated, so the rest of the call runs on
+	// behalf of the organization they were invited to.
+	ctx = tenant.Set(ctx, inv.OrgID)
+
+	nu := userbus.NewUser{
+		OrgID:      inv.OrgID,
+		Name:       ai.Name,
+		Email:      inv.Email,
+		Roles:      inv.Roles,
+		Department: inv.Department,
+		Password:   ai.Password,
+	}
+
+	usr, err := b.userBus.Create(ctx, inv.InvitedBy, nu)
+	if err != nil {
+		return Invitation{}, userbus.User{}, fmt.Errorf("create user: %w", err)
+	}
+
+	inv.Status = invitestatus.Accepted
+	inv.UserID = usr.ID
+	inv.DateUpdated = now
+
+	if err := b.storer.Update(ctx, inv); err != nil {
+		return Invitation{}, userbus.User{}, fmt.Errorf("update: %w", err)
+	}
+
+	return inv, usr, nil
+}
+
+// Query retrieves a list of existing invitations.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Invitation, error) {
+	invs, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return invs, nil
+}
+
+// Count returns the total number of invitations.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the invitation by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, invitationID uuid.UUID) (Invitation, error) {
+	inv, err := b.storer.QueryByID(ctx, invitationID)
+	if err != nil {
+		return Invitation{}, fmt.Errorf("query: invitationID[%s]: %w", invitationID, err)
+	}
+
+	return inv, nil
+}
+
+// =============================================================================
+
+// send delivers the link after the transaction commits, so no link goes out
+// for an invitation that was rolled back. By then the call can't fail
+// anymore, so a failed delivery is logged and the link can be sent again
+// with Resend.
+func (b *Business) send(ctx context.Context, inv Invitation, token string) {
+	sqldb.AfterCommit(ctx, func(ctx context.Context) {
+		if err := b.sender.Send(ctx, inv, token); err != nil {
+			b.log.Error(ctx, "invite: send", "invitationID", inv.ID, "ERROR", err)
+		}
+	})
+}
+
+// generateToken returns a random url safe token for the invitation link and
+// the hash of that token which is what gets persisted.
+func generateToken() (string, []byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,372 @@
+package invitebus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"testing"
+	"time"
+
+	"service/business/domain/auditbus"
+	"service/business/domain/invitebus"
+	"service/business/domain/userbus"
+	"service/business/sdk/dbtest"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/sdk/unitest"
+	"service/business/types/domain"
+	"service/business/types/invitestatus"
+	"service/business/types/name"
+	"service/business/types/role"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func Test_Invite(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Invite")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, accept(db.BusDomain, sd), "accept")
+	unitest.Run(t, revoke(db.BusDomain, sd), "revoke")
+	unitest.Run(t, inviterPurged(db.BusDomain, sqldb.NewBeginner(db.DB)), "inviterpurged")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := tenant.System(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, role.AdminRole, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Admins: []unitest.User{{User: usrs[0]}},
+	}
+
+	return sd, nil
+}
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	email, _ := mail.ParseAddress("jack@ardanlabs.com")
+
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: invitebus.Invitation{
+				OrgID:      tenant.DefaultOrgID,
+				Email:      *email,
+				Roles:      []role.Role{role.UserRole},
+				Department: "ITO",
+				Status:     invitestatus.Pending,
+				InvitedBy:  sd.Admins[0].ID,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				ni := invitebus.NewInvitation{
+					Email:      *email,
+					Roles:      []role.Role{role.UserRole},
+					Department: "ITO",
+				}
+
+				resp, err := busDomain.Invite.Create(ctx, sd.Admins[0].ID, ni)
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(invitebus.Invitation)
+				if !exists {
+					return "error occurred"
+				}
+
+				if busDomain.InviteSender.Token(gotResp) == "" {
+					return "expected an invitation link to be sent"
+				}
+
+				expResp := exp.(invitebus.Invitation)
+
+				expResp.ID = gotResp.ID
+				expResp.TokenHash = gotResp.TokenHash
+				expResp.DateExpires = gotResp.DateExpires
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "user-exists",
+			ExpResp: invitebus.ErrUserExists,
+			ExcFunc: func(ctx context.Context) any {
+				ni := invitebus.NewInvitation{
+					Email: sd.Admins[0].Email,
+					Roles: []role.Role{role.UserRole},
+				}
+
+				_, err := busDomain.Invite.Create(ctx, sd.Admins[0].ID, ni)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if !errors.Is(got.(error), exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func accept(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	email, _ := mail.ParseAddress("ed@ardanlabs.com")
+
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: userbus.User{
+				Name:       name.MustParse("Ed Invitee"),
+				Email:      *email,
+				Roles:      []role.Role{role.UserRole},
+				Department: "ITO",
+				Enabled:    true,
+				Version:    1,
+				OrgID:      tenant.DefaultOrgID,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				ni := invitebus.NewInvitation{
+					Email:      *email,
+					Roles:      []role.Role{role.UserRole},
+					Department: "ITO",
+				}
+
+				inv, err := busDomain.Invite.Create(ctx, sd.Admins[0].ID, ni)
+				if err != nil {
+					return err
+				}
+
+				ai := invitebus.AcceptInvitation{
+					Token:    busDomain.InviteSender.Token(inv),
+					Name:     name.MustParse("Ed Invitee"),
+					Password: "gophers",
+				}
+
+				inv, usr, err := busDomain.Invite.Accept(ctx, ai)
+				if err != nil {
+					return err
+				}
+
+				if inv.Status != invitestatus.Accepted || inv.UserID != usr.ID {
+					return fmt.Errorf("invitation not marked accepted: %+v", inv)
+				}
+
+				// A second attempt with the same link must fail.
+				if _, _, err := busDomain.Invite.Accept(ctx, ai); !errors.Is(err, invitebus.ErrNotPending) {
+					return fmt.Errorf("expected the link to be single use: %w", err)
+				}
+
+				var filter auditbus.QueryFilter
+				filter.WithObjID(inv.ID)
+				filter.WithObjDomain(domain.Invitation)
+
+				n, err := busDomain.Audit.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				if n != 2 {
+					return fmt.Errorf("expected 2 audit records, got %d", n)
+				}
+
+				return usr
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(userbus.User)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				if err := bcrypt.CompareHashAndPassword(gotResp.PasswordHash, []byte("gophers")); err != nil {
+					return err.Error()
+				}
+
+				expResp := exp.(userbus.User)
+
+				expResp.ID = gotResp.ID
+				expResp.PasswordHash = gotResp.PasswordHash
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "other-org",
+			ExpResp: invitebus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				other, _ := mail.ParseAddress("other@ardanlabs.com")
+
+				ni := invitebus.NewInvitation{
+					Email: *other,
+					Roles: []role.Role{role.UserRole},
+				}
+
+				inv, err := busDomain.Invite.Create(ctx, sd.Admins[0].ID, ni)
+				if err != nil {
+					return err
+				}
+
+				ai := invitebus.AcceptInvitation{
+					Token:    busDomain.InviteSender.Token(inv),
+					Name:     name.MustParse("Other Invitee"),
+					Password: "gophers",
+				}
+
+				// A link pointed at another organization can't find the
+				// invitation.
+				_, _, err = busDomain.Invite.Accept(tenant.Set(ctx, uuid.New()), ai)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func revoke(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	email, _ := mail.ParseAddress("revoked@ardanlabs.com")
+
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: invitebus.ErrNotPending,
+			ExcFunc: func(ctx context.Context) any {
+				ni := invitebus.NewInvitation{
+					Email: *email,
+					Roles: []role.Role{role.UserRole},
+				}
+
+				inv, err := busDomain.Invite.Create(ctx, sd.Admins[0].ID, ni)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Invite.Revoke(ctx, sd.Admins[0].ID, inv); err != nil {
+					return err
+				}
+
+				var filter invitebus.QueryFilter
+				filter.WithStatus(invitestatus.Revoked)
+
+				invs, err := busDomain.Invite.Query(ctx, filter, invitebus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(invs) != 1 || invs[0].ID != inv.ID {
+					return fmt.Errorf("expected the revoked invitation, got %d", len(invs))
+				}
+
+				ai := invitebus.AcceptInvitation{
+					Token:    busDomain.InviteSender.Token(inv),
+					Name:     name.MustParse("Revoked User"),
+					Password: "gophers",
+				}
+
+				_, _, err = busDomain.Invite.Accept(ctx, ai)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func inviterPurged(busDomain dbtest.BusDomain, bgn sqldb.Beginner) []unitest.Table {
+	email, _ := mail.ParseAddress("orphan@ardanlabs.com")
+
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: uuid.Nil,
+			ExcFunc: func(ctx context.Context) any {
+				usrs, err := userbus.TestSeedUsers(ctx, 1, role.AdminRole, busDomain.User)
+				if err != nil {
+					return err
+				}
+
+				ni := invitebus.NewInvitation{
+					Email: *email,
+					Roles: []role.Role{role.UserRole},
+				}
+
+				inv, err := busDomain.Invite.Create(ctx, usrs[0].ID, ni)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.User.Delete(ctx, usrs[0].ID, usrs[0]); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Purge(ctx, bgn, time.Now()); err != nil {
+					return err
+				}
+
+				// The invitation outlives the user who sent it and can
+				// still be accepted.
+				got, err := busDomain.Invite.QueryByID(ctx, inv.ID)
+				if err != nil {
+					return err
+				}
+
+				ai := invitebus.AcceptInvitation{
+					Token:    busDomain.InviteSender.Token(inv),
+					Name:     name.MustParse("Orphan User"),
+					Password: "gophers",
+				}
+
+				if _, _, err := busDomain.Invite.Accept(ctx, ai); err != nil {
+					return fmt.Errorf("accept: %w", err)
+				}
+
+				return got.InvitedBy
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
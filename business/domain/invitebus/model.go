@@ -0,0 +1,54 @@
+package invitebus
+
+import (
+	"net/mail"
+	"time"
+
+	"service/business/types/invitestatus"
+	"service/business/types/name"
+	"service/business/types/role"
+
+	"github.com/google/uuid"
+)
+
+// Invitation represents an invitation for someone to join the system as
+// a user. InvitedBy is uuid.Nil once the user who sent the invitation has
+// been purged.
+type Invitation struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	Email       mail.Address
+	Roles       []role.Role
+	Department  string
+	Status      invitestatus.Status
+	TokenHash   []byte
+	InvitedBy   uuid.UUID
+	UserID      uuid.UUID
+	DateExpires time.Time
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Expired reports whether the invitation can no longer be accepted because
+// its link has expired.
+func (inv Invitation) Expired(now time.Time) bool {
+	return !now.Before(inv.DateExpires)
+}
+
+// NewInvitation contains information needed to invite someone. The invitee
+// joins the organization of the invitation, which defaults to the tenant
+// of the call.
+type NewInvitation struct {
+	OrgID      uuid.UUID
+	Email      mail.Address
+	Roles      []role.Role
+	Department string
+}
+
+// AcceptInvitation contains information needed for an invitee to accept
+// an invitation and activate their account.
+type AcceptInvitation struct {
+	Token    string
+	Name     name.Name
+	Password string
+}
@@ -0,0 +1,15 @@
+package invitebus
+
+import "service/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "a"
+	OrderByEmail       = "b"
+	OrderByStatus      = "c"
+	OrderByDateCreated = "d"
+	OrderByDateExpires = "e"
+)
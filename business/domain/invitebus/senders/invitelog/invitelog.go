@@ -0,0 +1,42 @@
+// Package invitelog provides an invitation sender that writes the
+// invitation link to the log. It's meant for development environments where
+// no mail delivery is available.
+package invitelog
+
+import (
+	"context"
+	"net/url"
+	"service/business/domain/invitebus"
+	"service/foundation/logger"
+)
+
+// Sender writes invitation links to the log.
+type Sender struct {
+	log     *logger.Logger
+	baseURL string
+}
+
+// NewSender constructs a sender that will build links off the base url.
+func NewSender(log *logger.Logger, baseURL string) *Sender {
+	return &Sender{
+		log:     log,
+		baseURL: baseURL,
+	}
+}
+
+// Send implements the invitebus.Sender interface.
+func (s *Sender) Send(ctx context.Context, inv invitebus.Invitation, token string) error {
+	u, err := url.Parse(s.baseURL)
+	if err != nil {
+		return err
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	q.Set("org", inv.OrgID.String())
+	u.RawQuery = q.Encode()
+
+	s.log.Info(ctx, "invitation", "status", "sent", "invitation_id", inv.ID, "email", inv.Email.Address, "link", u.String())
+
+	return nil
+}
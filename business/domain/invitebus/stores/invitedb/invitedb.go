@@ -0,0 +1,232 @@
+// Package invitedb contains invitation related CRUD functionality.
+package invitedb
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"service/business/domain/invitebus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/foundation/logger"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for invitation database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db sqlx.ExtContext) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (invitebus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new invitation into the database. The invitation must
+// belong to the tenant in the context unless the call is a system one.
+func (s *Store) Create(ctx context.Context, inv invitebus.Invitation) error {
+	if err := tenant.Check(ctx, inv.OrgID); err != nil {
+		return fmt.Errorf("check: orgID[%s]: %w", inv.OrgID, err)
+	}
+
+	const q = `
+	INSERT INTO invitations
+		(invitation_id, org_id, email, roles, department, status, token_hash, invited_by, user_id, date_expires, date_created, date_updated)
+	VALUES
+		(:invitation_id, :org_id, :email, :roles, :department, :status, :token_hash, :invited_by, :user_id, :date_expires, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBInvitation(inv)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", invitebus.ErrPendingExists)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an invitation document in the database.
+func (s *Store) Update(ctx context.Context, inv invitebus.Invitation) error {
+	if err := tenant.Check(ctx, inv.OrgID); err != nil {
+		if errors.Is(err, tenant.ErrCrossTenant) {
+			return fmt.Errorf("check: %w", invitebus.ErrNotFound)
+		}
+		return fmt.Errorf("check: %w", err)
+	}
+
+	const q = `
+	UPDATE
+		invitations
+	SET
+		"status" = :status,
+		"token_hash" = :token_hash,
+		"user_id" = :user_id,
+		"date_expires" = :date_expires,
+		"date_updated" = :date_updated
+	WHERE
+		invitation_id = :invitation_id AND
+		org_id = :org_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBInvitation(inv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing invitations from the database.
+func (s *Store) Query(ctx context.Context, filter invitebus.QueryFilter, orderBy order.By, page page.Page) ([]invitebus.Invitation, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		invitation_id, org_id, email, roles, department, status, token_hash, invited_by, user_id, date_expires, date_created, date_updated
+	FROM
+		invitations`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, fmt.Errorf("scope: %w", err)
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbInvs []invitation
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbInvs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusInvitations(dbInvs)
+}
+
+// Count returns the total number of invitations in the DB.
+func (s *Store) Count(ctx context.Context, filter invitebus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		invitations`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, fmt.Errorf("scope: %w", err)
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified invitation from the database.
+func (s *Store) QueryByID(ctx context.Context, invitationID uuid.UUID) (invitebus.Invitation, error) {
+	data := map[string]any{
+		"invitation_id": invitationID.String(),
+	}
+
+	const q = `
+	SELECT
+		invitation_id, org_id, email, roles, department, status, token_hash, invited_by, user_id, date_expires, date_created, date_updated
+	FROM
+		invitations
+	WHERE
+		invitation_id = :invitation_id`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return invitebus.Invitation{}, fmt.Errorf("scope: %w", err)
+	}
+
+	var dbInv invitation
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbInv); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return invitebus.Invitation{}, fmt.Errorf("db: %w", invitebus.ErrNotFound)
+		}
+		return invitebus.Invitation{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusInvitation(dbInv)
+}
+
+// QueryByTokenHash gets the invitation matching the token hash from the
+// database. The row is locked for the rest of the transaction so the
+// invitation can only be accepted once.
+func (s *Store) QueryByTokenHash(ctx context.Context, tokenHash []byte) (invitebus.Invitation, error) {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return invitebus.Invitation{}, fmt.Errorf("scope: %w", err)
+	}
+
+	data := struct {
+		TokenHash string    `db:"token_hash" log:"redact"`
+		OrgID     uuid.UUID `db:"org_id"`
+	}{
+		TokenHash: hex.EncodeToString(tokenHash),
+		OrgID:     orgID,
+	}
+
+	const q = `
+	SELECT
+		invitation_id, org_id, email, roles, department, status, token_hash, invited_by, user_id, date_expires, date_created, date_updated
+	FROM
+		invitations
+	WHERE
+		token_hash = :token_hash`
+
+	buf := bytes.NewBufferString(q)
+	if scoped {
+		buf.WriteString(" AND org_id = :org_id")
+	}
+	buf.WriteString(" FOR UPDATE")
+
+	var dbInv invitation
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbInv); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return invitebus.Invitation{}, fmt.Errorf("db: %w", invitebus.ErrNotFound)
+		}
+		return invitebus.Invitation{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusInvitation(dbInv)
+}
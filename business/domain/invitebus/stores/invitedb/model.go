@@ -0,0 +1,104 @@
+package invitedb
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"service/business/domain/invitebus"
+	"service/business/sdk/sqldb/dbarray"
+	"service/business/types/invitestatus"
+	"service/business/types/role"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type invitation struct {
+	ID          uuid.UUID      `db:"invitation_id"`
+	OrgID       uuid.UUID      `db:"org_id"`
+	Email       string         `db:"email" log:"redact"`
+	Roles       dbarray.String `db:"roles"`
+	Department  sql.NullString `db:"department"`
+	Status      string         `db:"status"`
+	TokenHash   string         `db:"token_hash" log:"redact"`
+	InvitedBy   uuid.NullUUID  `db:"invited_by"`
+	UserID      uuid.NullUUID  `db:"user_id"`
+	DateExpires time.Time      `db:"date_expires"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toDBInvitation(bus invitebus.Invitation) invitation {
+	return invitation{
+		ID:    bus.ID,
+		OrgID: bus.OrgID,
+		Email: bus.Email.Address,
+		Roles: role.ParseToString(bus.Roles),
+		Department: sql.NullString{
+			String: bus.Department,
+			Valid:  bus.Department != "",
+		},
+		Status:    bus.Status.String(),
+		TokenHash: hex.EncodeToString(bus.TokenHash),
+		InvitedBy: uuid.NullUUID{
+			UUID:  bus.InvitedBy,
+			Valid: bus.InvitedBy != uuid.Nil,
+		},
+		UserID: uuid.NullUUID{
+			UUID:  bus.UserID,
+			Valid: bus.UserID != uuid.Nil,
+		},
+		DateExpires: bus.DateExpires.UTC(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusInvitation(db invitation) (invitebus.Invitation, error) {
+	roles, err := role.ParseMany(db.Roles)
+	if err != nil {
+		return invitebus.Invitation{}, fmt.Errorf("parse roles: %w", err)
+	}
+
+	status, err := invitestatus.Parse(db.Status)
+	if err != nil {
+		return invitebus.Invitation{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	hash, err := hex.DecodeString(db.TokenHash)
+	if err != nil {
+		return invitebus.Invitation{}, fmt.Errorf("decode token hash: %w", err)
+	}
+
+	bus := invitebus.Invitation{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		Email:       mail.Address{Address: db.Email},
+		Roles:       roles,
+		Department:  db.Department.String,
+		Status:      status,
+		TokenHash:   hash,
+		InvitedBy:   db.InvitedBy.UUID,
+		UserID:      db.UserID.UUID,
+		DateExpires: db.DateExpires.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusInvitations(dbs []invitation) ([]invitebus.Invitation, error) {
+	bus := make([]invitebus.Invitation, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusInvitation(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
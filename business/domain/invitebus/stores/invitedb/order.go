@@ -0,0 +1,24 @@
+package invitedb
+
+import (
+	"fmt"
+	"service/business/domain/invitebus"
+	"service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	invitebus.OrderByID:          "invitation_id",
+	invitebus.OrderByEmail:       "email",
+	invitebus.OrderByStatus:      "status",
+	invitebus.OrderByDateCreated: "date_created",
+	invitebus.OrderByDateExpires: "date_expires",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,34 @@
+package invitebus
+
+import (
+	"context"
+	"sync"
+)
+
+// TestSender is a Sender for testing that remembers the last token
+// sent for each invitation.
+type TestSender struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// Send implements the Sender interface.
+func (s *TestSender) Send(ctx context.Context, inv Invitation, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tokens == nil {
+		s.tokens = make(map[string]string)
+	}
+	s.tokens[inv.ID.String()] = token
+
+	return nil
+}
+
+// Token returns the last token sent for the specified invitation.
+func (s *TestSender) Token(inv Invitation) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens[inv.ID.String()]
+}
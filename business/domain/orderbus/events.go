@@ -0,0 +1,51 @@
+package orderbus
+
+import (
+	"encoding/json"
+	"fmt"
+	"service/business/sdk/delegate"
+
+	"github.com/google/uuid"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "order"
+
+// Set of delegate actions.
+const (
+	ActionStatusChanged = "statuschanged"
+)
+
+// ActionStatusChangedParms represents the parameters for the status changed
+// action. From is empty when the order has just been created.
+type ActionStatusChangedParms struct {
+	OrderID uuid.UUID
+	UserID  uuid.UUID
+	ActorID uuid.UUID
+	From    string
+	To      string
+}
+
+// String returns a string representation of the action parameters.
+func (act *ActionStatusChangedParms) String() string {
+	return fmt.Sprintf("&EventParamsStatusChanged{OrderID:%v, From:%v, To:%v}", act.OrderID, act.From, act.To)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (act *ActionStatusChangedParms) Marshal() ([]byte, error) {
+	return json.Marshal(act)
+}
+
+// ActionStatusChangedData constructs the data for the status changed action.
+func ActionStatusChangedData(params ActionStatusChangedParms) delegate.Data {
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionStatusChanged,
+		RawParams: rawParams,
+	}
+}
@@ -0,0 +1,181 @@
+// Package orderaudit provides an extension for orderbus that adds
+// auditing functionality.
+package orderaudit
+
+import (
+	"context"
+	"fmt"
+	"service/business/domain/auditbus"
+	"service/business/domain/orderbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/types/domain"
+	"service/business/types/name"
+
+	"github.com/google/uuid"
+)
+
+// Set of audit actions recorded by this extension.
+const (
+	ActionCreated   = "created"
+	ActionPaid      = "paid"
+	ActionShipped   = "shipped"
+	ActionCancelled = "cancelled"
+	ActionStockSet  = "stockset"
+)
+
+// Set of audit object names, the objects have no name of their own.
+var (
+	objNameOrder     = name.MustParse("Order")
+	objNameInventory = name.MustParse("Inventory")
+)
+
+// Extension provides a wrapper for audit functionality around the orderbus.
+type Extension struct {
+	bus      orderbus.ExtBusiness
+	auditBus *auditbus.Business
+}
+
+// NewExtension constructs a new extension that wraps the orderbus with audit.
+func NewExtension(auditBus *auditbus.Business) orderbus.Extension {
+	return func(bus orderbus.ExtBusiness) orderbus.ExtBusiness {
+		return &Extension{
+			bus:      bus,
+			auditBus: auditBus,
+		}
+	}
+}
+
+// NewWithTx applies auditing inside the same transaction so an audit record
+// is only kept when the step it describes is committed.
+func (ext *Extension) NewWithTx(tx sqldb.CommitRollbacker) (orderbus.ExtBusiness, error) {
+	bus, err := ext.bus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	auditBus, err := ext.auditBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extension{
+		bus:      bus,
+		auditBus: auditBus,
+	}, nil
+}
+
+// Create applies auditing to the order creation process.
+func (ext *Extension) Create(ctx context.Context, actorID uuid.UUID, no orderbus.NewOrder) (orderbus.Order, error) {
+	ord, err := ext.bus.Create(ctx, actorID, no)
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	if err := ext.audit(ctx, actorID, ord.ID, objNameOrder, ActionCreated, no, "order created"); err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return ord, nil
+}
+
+// Pay applies auditing to the order payment process.
+func (ext *Extension) Pay(ctx context.Context, actorID uuid.UUID, ord orderbus.Order) (orderbus.Order, error) {
+	ord, err := ext.bus.Pay(ctx, actorID, ord)
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	if err := ext.audit(ctx, actorID, ord.ID, objNameOrder, ActionPaid, nil, "order paid"); err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return ord, nil
+}
+
+// Ship applies auditing to the order shipping process.
+func (ext *Extension) Ship(ctx context.Context, actorID uuid.UUID, ord orderbus.Order) (orderbus.Order, error) {
+	ord, err := ext.bus.Ship(ctx, actorID, ord)
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	if err := ext.audit(ctx, actorID, ord.ID, objNameOrder, ActionShipped, nil, "order shipped"); err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return ord, nil
+}
+
+// Cancel applies auditing to the order cancellation process.
+func (ext *Extension) Cancel(ctx context.Context, actorID uuid.UUID, ord orderbus.Order) (orderbus.Order, error) {
+	ord, err := ext.bus.Cancel(ctx, actorID, ord)
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	if err := ext.audit(ctx, actorID, ord.ID, objNameOrder, ActionCancelled, nil, "order cancelled"); err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return ord, nil
+}
+
+// Query does not apply auditing.
+func (ext *Extension) Query(ctx context.Context, filter orderbus.QueryFilter, orderBy order.By, page page.Page) ([]orderbus.Order, error) {
+	return ext.bus.Query(ctx, filter, orderBy, page)
+}
+
+// Count does not apply auditing.
+func (ext *Extension) Count(ctx context.Context, filter orderbus.QueryFilter) (int, error) {
+	return ext.bus.Count(ctx, filter)
+}
+
+// QueryByID does not apply auditing.
+func (ext *Extension) QueryByID(ctx context.Context, orderID uuid.UUID) (orderbus.Order, error) {
+	return ext.bus.QueryByID(ctx, orderID)
+}
+
+// SetStock applies auditing to the stock update process.
+func (ext *Extension) SetStock(ctx context.Context, actorID uuid.UUID, productID uuid.UUID, quantity int) (orderbus.Stock, error) {
+	stock, err := ext.bus.SetStock(ctx, actorID, productID, quantity)
+	if err != nil {
+		return orderbus.Stock{}, err
+	}
+
+	data := struct {
+		Quantity int
+	}{
+		Quantity: quantity,
+	}
+
+	if err := ext.audit(ctx, actorID, productID, objNameInventory, ActionStockSet, data, "stock set"); err != nil {
+		return orderbus.Stock{}, err
+	}
+
+	return stock, nil
+}
+
+// QueryStock does not apply auditing.
+func (ext *Extension) QueryStock(ctx context.Context, productID uuid.UUID) (orderbus.Stock, error) {
+	return ext.bus.QueryStock(ctx, productID)
+}
+
+func (ext *Extension) audit(ctx context.Context, actorID uuid.UUID, objID uuid.UUID, objName name.Name, action string, data any, message string) error {
+	na := auditbus.NewAudit{
+		ObjID:     objID,
+		ObjDomain: domain.Order,
+		ObjName:   objName,
+		ActorID:   actorID,
+		Action:    action,
+		Data:      data,
+		Message:   message,
+	}
+
+	if _, err := ext.auditBus.Create(ctx, na); err != nil {
+		return fmt.Errorf("audit: %s: %w", action, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package orderotel
+
+import (
+	"context"
+	"service/business/domain/orderbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/foundation/otel"
+
+	"github.com/google/uuid"
+)
+
+type Extension struct {
+	bus orderbus.ExtBusiness
+}
+
+// NewExtension constructs a new extension that wraps the orderbus with otel.
+func NewExtension() orderbus.Extension {
+	return func(bus orderbus.ExtBusiness) orderbus.ExtBusiness {
+		return &Extension{
+			bus: bus,
+		}
+	}
+}
+
+// NewWithTx applies otel to the business value using the transaction.
+func (ext *Extension) NewWithTx(tx sqldb.CommitRollbacker) (orderbus.ExtBusiness, error) {
+	bus, err := ext.bus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extension{
+		bus: bus,
+	}, nil
+}
+
+// Create applies otel to the order creation process.
+func (ext *Extension) Create(ctx context.Context, actorID uuid.UUID, no orderbus.NewOrder) (orderbus.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.create")
+	defer span.End()
+
+	return ext.bus.Create(ctx, actorID, no)
+}
+
+// Pay applies otel to the order payment process.
+func (ext *Extension) Pay(ctx context.Context, actorID uuid.UUID, ord orderbus.Order) (orderbus.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.pay")
+	defer span.End()
+
+	return ext.bus.Pay(ctx, actorID, ord)
+}
+
+// Ship applies otel to the order shipping process.
+func (ext *Extension) Ship(ctx context.Context, actorID uuid.UUID, ord orderbus.Order) (orderbus.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.ship")
+	defer span.End()
+
+	return ext.bus.Ship(ctx, actorID, ord)
+}
+
+// Cancel applies otel to the order cancellation process.
+func (ext *Extension) Cancel(ctx context.Context, actorID uuid.UUID, ord orderbus.Order) (orderbus.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.cancel")
+	defer span.End()
+
+	return ext.bus.Cancel(ctx, actorID, ord)
+}
+
+// Query applies otel to the order query process.
+func (ext *Extension) Query(ctx context.Context, filter orderbus.QueryFilter, orderBy order.By, page page.Page) ([]orderbus.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.query")
+	defer span.End()
+
+	return ext.bus.Query(ctx, filter, orderBy, page)
+}
+
+// Count applies otel to the order count process.
+func (ext *Extension) Count(ctx context.Context, filter orderbus.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.count")
+	defer span.End()
+
+	return ext.bus.Count(ctx, filter)
+}
+
+// QueryByID applies otel to the order query by id process.
+func (ext *Extension) QueryByID(ctx context.Context, orderID uuid.UUID) (orderbus.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.querybyid")
+	defer span.End()
+
+	return ext.bus.QueryByID(ctx, orderID)
+}
+
+// SetStock applies otel to the stock update process.
+func (ext *Extension) SetStock(ctx context.Context, actorID uuid.UUID, productID uuid.UUID, quantity int) (orderbus.Stock, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.setstock")
+	defer span.End()
+
+	return ext.bus.SetStock(ctx, actorID, productID, quantity)
+}
+
+// QueryStock applies otel to the stock query process.
+func (ext *Extension) QueryStock(ctx context.Context, productID uuid.UUID) (orderbus.Stock, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orderbus.querystock")
+	defer span.End()
+
+	return ext.bus.QueryStock(ctx, productID)
+}
@@ -0,0 +1,45 @@
+package orderbus
+
+import (
+	"service/business/types/orderstatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ID               *uuid.UUID
+	UserID           *uuid.UUID
+	Status           *orderstatus.Status
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
+
+// WithOrderID sets the ID field of the QueryFilter value.
+func (qf *QueryFilter) WithOrderID(orderID uuid.UUID) {
+	qf.ID = &orderID
+}
+
+// WithUserID sets the UserID field of the QueryFilter value.
+func (qf *QueryFilter) WithUserID(userID uuid.UUID) {
+	qf.UserID = &userID
+}
+
+// WithStatus sets the Status field of the QueryFilter value.
+func (qf *QueryFilter) WithStatus(status orderstatus.Status) {
+	qf.Status = &status
+}
+
+// WithStartDateCreated sets the StartCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithStartDateCreated(startDate time.Time) {
+	d := startDate.UTC()
+	qf.StartCreatedDate = &d
+}
+
+// WithEndCreatedDate sets the EndCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndCreatedDate = &d
+}
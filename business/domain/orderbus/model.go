@@ -0,0 +1,62 @@
+package orderbus
+
+import (
+	"fmt"
+	"service/business/types/money"
+	"service/business/types/orderstatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order represents a sale of a set of products to a user. The order
+// belongs to the organization of its products.
+type Order struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	UserID      uuid.UUID
+	Status      orderstatus.Status
+	Lines       []Line
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Total returns the sum of the lines of the order. The lines of an order
+// are always in a single currency.
+func (o Order) Total() (money.Money, error) {
+	if len(o.Lines) == 0 {
+		return money.Money{}, nil
+	}
+
+	amounts := make([]money.Money, len(o.Lines))
+	for i, line := range o.Lines {
+		amount, err := line.Price.Mul(int64(line.Quantity))
+		if err != nil {
+			return money.Money{}, fmt.Errorf("productID[%s]: %w", line.ProductID, err)
+		}
+		amounts[i] = amount
+	}
+
+	return money.Sum(o.Lines[0].Price.Currency(), amounts...)
+}
+
+// Line represents the quantity of a product being ordered. The price is
+// the cost of the product when the order was placed.
+type Line struct {
+	ProductID uuid.UUID
+	Quantity  int
+	Price     money.Money
+}
+
+// NewOrder contains information needed to create a new order.
+type NewOrder struct {
+	UserID uuid.UUID
+	Lines  []Line
+}
+
+// Stock represents the quantity of a product available to be ordered.
+type Stock struct {
+	ProductID   uuid.UUID
+	Quantity    int
+	DateUpdated time.Time
+}
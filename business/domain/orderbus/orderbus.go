@@ -0,0 +1,370 @@
+// Package orderbus provides business access to the order domain.
+package orderbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/business/domain/productbus"
+	"service/business/sdk/delegate"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/orderstatus"
+	"service/foundation/logger"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound          = errors.New("order not found")
+	ErrNoLines           = errors.New("order has no lines")
+	ErrInvalidQuantity   = errors.New("quantity not valid")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidTransition = errors.New("order status transition not allowed")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, ord Order) error
+	UpdateStatus(ctx context.Context, ord Order, from orderstatus.Status) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error)
+	QueryStock(ctx context.Context, productID uuid.UUID) (Stock, error)
+	QueryStockForUpdate(ctx context.Context, productIDs []uuid.UUID) ([]Stock, error)
+	UpdateStock(ctx context.Context, stock Stock) error
+}
+
+// ExtBusiness interface provides support for extensions that wrap extra functionality
+// around the core busines logic.
+type ExtBusiness interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (ExtBusiness, error)
+	Create(ctx context.Context, actorID uuid.UUID, no NewOrder) (Order, error)
+	Pay(ctx context.Context, actorID uuid.UUID, ord Order) (Order, error)
+	Ship(ctx context.Context, actorID uuid.UUID, ord Order) (Order, error)
+	Cancel(ctx context.Context, actorID uuid.UUID, ord Order) (Order, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error)
+	SetStock(ctx context.Context, actorID uuid.UUID, productID uuid.UUID, quantity int) (Stock, error)
+	QueryStock(ctx context.Context, productID uuid.UUID) (Stock, error)
+}
+
+// Extension is a function that wraps a new layer of business logic
+// around the existing business logic.
+type Extension func(ExtBusiness) ExtBusiness
+
+// Business manages the set of APIs for order access.
+type Business struct {
+	log        *logger.Logger
+	productBus productbus.ExtBusiness
+	delegate   *delegate.Delegate
+	storer     Storer
+}
+
+// NewBusiness constructs an order business API for use.
+func NewBusiness(log *logger.Logger, productBus productbus.ExtBusiness, delegate *delegate.Delegate, storer Storer, extensions ...Extension) ExtBusiness {
+	b := ExtBusiness(&Business{
+		log:        log,
+		productBus: productBus,
+		delegate:   delegate,
+		storer:     storer,
+	})
+
+	for i := len(extensions) - 1; i >= 0; i-- {
+		ext := extensions[i]
+		if ext != nil {
+			b = ext(b)
+		}
+	}
+
+	return b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (ExtBusiness, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	productBus, err := b.productBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:        b.log,
+		productBus: productBus,
+		delegate:   b.delegate,
+		storer:     storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new pending order to the system and reserves the stock for
+// its lines. The call must run inside a transaction so the stock rows stay
+// locked until the order is committed.
+func (b *Business) Create(ctx context.Context, actorID uuid.UUID, no NewOrder) (Order, error) {
+	lines, err := mergeLines(no.Lines)
+	if err != nil {
+		return Order{}, err
+	}
+
+	var orgID uuid.UUID
+	for i, line := range lines {
+		prd, err := b.productBus.QueryByID(ctx, line.ProductID)
+		if err != nil {
+			return Order{}, fmt.Errorf("product.querybyid: %s: %w", line.ProductID, err)
+		}
+
+		// The products of an order must all belong to the same
+		// organization.
+		if i > 0 && prd.OrgID != orgID {
+			return Order{}, fmt.Errorf("product: %s: %w", line.ProductID, tenant.ErrCrossTenant)
+		}
+
+		orgID = prd.OrgID
+		lines[i].Price = prd.Cost
+	}
+
+	if err := b.adjustStock(ctx, lines, -1); err != nil {
+		return Order{}, err
+	}
+
+	now := time.Now()
+
+	ord := Order{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		UserID:      no.UserID,
+		Status:      orderstatus.Pending,
+		Lines:       lines,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	// An order is paid in a single currency.
+	if _, err := ord.Total(); err != nil {
+		return Order{}, fmt.Errorf("total: %w", err)
+	}
+
+	if err := b.storer.Create(ctx, ord); err != nil {
+		return Order{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := b.statusChanged(ctx, actorID, ord, orderstatus.Status{}); err != nil {
+		return Order{}, err
+	}
+
+	return ord, nil
+}
+
+// Pay marks a pending order as paid.
+func (b *Business) Pay(ctx context.Context, actorID uuid.UUID, ord Order) (Order, error) {
+	return b.transition(ctx, actorID, ord, orderstatus.Paid)
+}
+
+// Ship marks a paid order as shipped.
+func (b *Business) Ship(ctx context.Context, actorID uuid.UUID, ord Order) (Order, error) {
+	return b.transition(ctx, actorID, ord, orderstatus.Shipped)
+}
+
+// Cancel cancels an order that has not shipped and returns its reserved
+// stock. The call must run inside a transaction.
+func (b *Business) Cancel(ctx context.Context, actorID uuid.UUID, ord Order) (Order, error) {
+	return b.transition(ctx, actorID, ord, orderstatus.Cancelled)
+}
+
+// Query retrieves a list of existing orders.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error) {
+	ords, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return ords, nil
+}
+
+// Count returns the total number of orders.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the order by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error) {
+	ord, err := b.storer.QueryByID(ctx, orderID)
+	if err != nil {
+		return Order{}, fmt.Errorf("query: orderID[%s]: %w", orderID, err)
+	}
+
+	return ord, nil
+}
+
+// SetStock sets the quantity of the specified product available to be
+// ordered.
+func (b *Business) SetStock(ctx context.Context, actorID uuid.UUID, productID uuid.UUID, quantity int) (Stock, error) {
+	if quantity < 0 {
+		return Stock{}, fmt.Errorf("quantity[%d]: %w", quantity, ErrInvalidQuantity)
+	}
+
+	if _, err := b.productBus.QueryByID(ctx, productID); err != nil {
+		return Stock{}, fmt.Errorf("product.querybyid: %s: %w", productID, err)
+	}
+
+	stock := Stock{
+		ProductID:   productID,
+		Quantity:    quantity,
+		DateUpdated: time.Now(),
+	}
+
+	if err := b.storer.UpdateStock(ctx, stock); err != nil {
+		return Stock{}, fmt.Errorf("updatestock: %w", err)
+	}
+
+	return stock, nil
+}
+
+// QueryStock returns the quantity of the specified product available to be
+// ordered. A product with no stock recorded has none available.
+func (b *Business) QueryStock(ctx context.Context, productID uuid.UUID) (Stock, error) {
+	if _, err := b.productBus.QueryByID(ctx, productID); err != nil {
+		return Stock{}, fmt.Errorf("product.querybyid: %s: %w", productID, err)
+	}
+
+	stock, err := b.storer.QueryStock(ctx, productID)
+	if err != nil {
+		return Stock{}, fmt.Errorf("querystock: productID[%s]: %w", productID, err)
+	}
+
+	return stock, nil
+}
+
+// =============================================================================
+
+// transition moves the order to the specified status if the state machine
+// allows it. The store only applies the change if the order is still in
+// the status it was read in, so concurrent transitions can't both succeed.
+func (b *Business) transition(ctx context.Context, actorID uuid.UUID, ord Order, to orderstatus.Status) (Order, error) {
+	from := ord.Status
+
+	if err := checkTransition(from, to); err != nil {
+		return Order{}, err
+	}
+
+	ord.Status = to
+	ord.DateUpdated = time.Now()
+
+	if err := b.storer.UpdateStatus(ctx, ord, from); err != nil {
+		return Order{}, fmt.Errorf("updatestatus: %w", err)
+	}
+
+	// A cancelled order gives back the stock it reserved. Only pending and
+	// paid orders can be cancelled and both are still holding it.
+	if to == orderstatus.Cancelled {
+		if err := b.adjustStock(ctx, ord.Lines, 1); err != nil {
+			return Order{}, err
+		}
+	}
+
+	if err := b.statusChanged(ctx, actorID, ord, from); err != nil {
+		return Order{}, err
+	}
+
+	return ord, nil
+}
+
+// adjustStock locks the stock for the lines and applies the line quantities
+// in the specified direction, -1 to reserve and 1 to release.
+func (b *Business) adjustStock(ctx context.Context, lines []Line, direction int) error {
+	productIDs := make([]uuid.UUID, len(lines))
+	for i, line := range lines {
+		productIDs[i] = line.ProductID
+	}
+
+	stocks, err := b.storer.QueryStockForUpdate(ctx, productIDs)
+	if err != nil {
+		return fmt.Errorf("querystockforupdate: %w", err)
+	}
+
+	available := make(map[uuid.UUID]Stock, len(stocks))
+	for _, stock := range stocks {
+		available[stock.ProductID] = stock
+	}
+
+	now := time.Now()
+
+	for _, line := range lines {
+		stock, exists := available[line.ProductID]
+		if !exists {
+			stock = Stock{ProductID: line.ProductID}
+		}
+
+		stock.Quantity += direction * line.Quantity
+		stock.DateUpdated = now
+
+		if stock.Quantity < 0 {
+			return fmt.Errorf("productID[%s]: %w", line.ProductID, ErrInsufficientStock)
+		}
+
+		if err := b.storer.UpdateStock(ctx, stock); err != nil {
+			return fmt.Errorf("updatestock: productID[%s]: %w", line.ProductID, err)
+		}
+	}
+
+	return nil
+}
+
+// statusChanged lets other domains know the order moved to a new status.
+func (b *Business) statusChanged(ctx context.Context, actorID uuid.UUID, ord Order, from orderstatus.Status) error {
+	params := ActionStatusChangedParms{
+		OrderID: ord.ID,
+		UserID:  ord.UserID,
+		ActorID: actorID,
+		From:    from.String(),
+		To:      ord.Status.String(),
+	}
+
+	if err := b.delegate.Call(ctx, ActionStatusChangedData(params)); err != nil {
+		return fmt.Errorf("failed to execute `%s` action: %w", ActionStatusChanged, err)
+	}
+
+	return nil
+}
+
+// mergeLines validates the lines of a new order and combines the lines for
+// the same product. The lines are sorted by product so stock rows are
+// always locked in the same order.
+func mergeLines(lines []Line) ([]Line, error) {
+	if len(lines) == 0 {
+		return nil, ErrNoLines
+	}
+
+	qty := make(map[uuid.UUID]int, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("productID[%s]: quantity[%d]: %w", line.ProductID, line.Quantity, ErrInvalidQuantity)
+		}
+		qty[line.ProductID] += line.Quantity
+	}
+
+	merged := make([]Line, 0, len(qty))
+	for productID, quantity := range qty {
+		merged = append(merged, Line{ProductID: productID, Quantity: quantity})
+	}
+
+	slices.SortFunc(merged, func(a, b Line) int {
+		return slices.Compare(a.ProductID[:], b.ProductID[:])
+	})
+
+	return merged, nil
+}
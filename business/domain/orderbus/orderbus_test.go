@@ -0,0 +1,275 @@
+package orderbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"service/business/domain/orderbus"
+	"service/business/domain/productbus"
+	"service/business/domain/userbus"
+	"service/business/sdk/dbtest"
+	"service/business/sdk/delegate"
+	"service/business/sdk/tenant"
+	"service/business/sdk/unitest"
+	"service/business/types/orderstatus"
+	"service/business/types/role"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Order(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Order")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, transition(db.BusDomain, sd), "transition")
+	unitest.Run(t, cancel(db.BusDomain, sd), "cancel")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := tenant.System(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 1, role.UserRole, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productbus.TestSeedProducts(ctx, 3, usrs[0].ID, busDomain.Product)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	for _, prd := range prds {
+		if _, err := busDomain.Order.SetStock(ctx, usrs[0].ID, prd.ID, 10); err != nil {
+			return unitest.SeedData{}, fmt.Errorf("seeding stock : %w", err)
+		}
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0], Products: prds}},
+	}
+
+	return sd, nil
+}
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0]
+
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: orderbus.Order{
+				OrgID:  usr.OrgID,
+				UserID: usr.ID,
+				Status: orderstatus.Pending,
+				Lines: []orderbus.Line{
+					{ProductID: usr.Products[0].ID, Quantity: 4, Price: usr.Products[0].Cost},
+				},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				no := orderbus.NewOrder{
+					UserID: usr.ID,
+					Lines: []orderbus.Line{
+						{ProductID: usr.Products[0].ID, Quantity: 1},
+						{ProductID: usr.Products[0].ID, Quantity: 3},
+					},
+				}
+
+				ord, err := busDomain.Order.Create(ctx, usr.ID, no)
+				if err != nil {
+					return err
+				}
+
+				stock, err := busDomain.Order.QueryStock(ctx, usr.Products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				if stock.Quantity != 6 {
+					return fmt.Errorf("expected 6 left in stock, got %d", stock.Quantity)
+				}
+
+				return ord
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(orderbus.Order)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				expResp := exp.(orderbus.Order)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "insufficient-stock",
+			ExpResp: orderbus.ErrInsufficientStock,
+			ExcFunc: func(ctx context.Context) any {
+				no := orderbus.NewOrder{
+					UserID: usr.ID,
+					Lines: []orderbus.Line{
+						{ProductID: usr.Products[1].ID, Quantity: 11},
+					},
+				}
+
+				_, err := busDomain.Order.Create(ctx, usr.ID, no)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func transition(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "illegal",
+			ExpResp: orderbus.ErrInvalidTransition,
+			ExcFunc: func(ctx context.Context) any {
+				no := orderbus.NewOrder{
+					UserID: usr.ID,
+					Lines: []orderbus.Line{
+						{ProductID: usr.Products[1].ID, Quantity: 1},
+					},
+				}
+
+				ord, err := busDomain.Order.Create(ctx, usr.ID, no)
+				if err != nil {
+					return err
+				}
+
+				_, err = busDomain.Order.Ship(ctx, usr.ID, ord)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "stale",
+			ExpResp: orderbus.ErrInvalidTransition,
+			ExcFunc: func(ctx context.Context) any {
+				no := orderbus.NewOrder{
+					UserID: usr.ID,
+					Lines: []orderbus.Line{
+						{ProductID: usr.Products[1].ID, Quantity: 1},
+					},
+				}
+
+				ord, err := busDomain.Order.Create(ctx, usr.ID, no)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Order.Pay(ctx, usr.ID, ord); err != nil {
+					return err
+				}
+
+				// The copy still reads pending, but the order has moved on.
+				_, err = busDomain.Order.Pay(ctx, usr.ID, ord)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func cancel(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "basic",
+			ExpResp: []string{"/pending", "pending/paid", "paid/cancelled"},
+			ExcFunc: func(ctx context.Context) any {
+				var changes []string
+				busDomain.Delegate.Register(orderbus.DomainName, orderbus.ActionStatusChanged, func(ctx context.Context, data delegate.Data) error {
+					var params orderbus.ActionStatusChangedParms
+					if err := json.Unmarshal(data.RawParams, &params); err != nil {
+						return err
+					}
+
+					changes = append(changes, params.From+"/"+params.To)
+					return nil
+				})
+
+				no := orderbus.NewOrder{
+					UserID: usr.ID,
+					Lines: []orderbus.Line{
+						{ProductID: usr.Products[2].ID, Quantity: 5},
+					},
+				}
+
+				ord, err := busDomain.Order.Create(ctx, usr.ID, no)
+				if err != nil {
+					return err
+				}
+
+				ord, err = busDomain.Order.Pay(ctx, usr.ID, ord)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Order.Cancel(ctx, usr.ID, ord); err != nil {
+					return err
+				}
+
+				stock, err := busDomain.Order.QueryStock(ctx, usr.Products[2].ID)
+				if err != nil {
+					return err
+				}
+
+				if stock.Quantity != 10 {
+					return fmt.Errorf("expected the stock to be released, got %d", stock.Quantity)
+				}
+
+				return changes
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,24 @@
+package orderbus
+
+import (
+	"fmt"
+	"service/business/types/orderstatus"
+)
+
+// transitions defines the statuses an order can move to from each status.
+// Shipped and cancelled orders are final.
+var transitions = map[orderstatus.Status][]orderstatus.Status{
+	orderstatus.Pending: {orderstatus.Paid, orderstatus.Cancelled},
+	orderstatus.Paid:    {orderstatus.Shipped, orderstatus.Cancelled},
+}
+
+// checkTransition validates the order can move from one status to another.
+func checkTransition(from orderstatus.Status, to orderstatus.Status) error {
+	for _, s := range transitions[from] {
+		if s == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s -> %s: %w", from, to, ErrInvalidTransition)
+}
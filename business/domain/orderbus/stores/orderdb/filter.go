@@ -0,0 +1,51 @@
+package orderdb
+
+import (
+	"bytes"
+	"context"
+	"service/business/domain/orderbus"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+)
+
+// applyTenant adds the condition restricting a statement that already has
+// a WHERE clause to the tenant in the context.
+func applyTenant(ctx context.Context, data map[string]any, buf *bytes.Buffer) error {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	if scoped {
+		data["org_id"] = orgID
+		buf.WriteString(" AND org_id = :org_id")
+	}
+
+	return nil
+}
+
+func applyFilter(ctx context.Context, filter orderbus.QueryFilter, data map[string]any, buf *bytes.Buffer) error {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	f := sqldb.NewFilter(data)
+
+	f.Eq("order_id", filter.ID)
+	f.Eq("user_id", filter.UserID)
+
+	if filter.Status != nil {
+		f.Eq("status", filter.Status.String())
+	}
+
+	f.Range("date_created", filter.StartCreatedDate, filter.EndCreatedDate)
+
+	if scoped {
+		f.Eq("org_id", &orgID)
+	}
+
+	f.Apply(buf)
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package orderdb
+
+import (
+	"fmt"
+	"service/business/domain/orderbus"
+	"service/business/types/money"
+	"service/business/types/orderstatus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type dbOrder struct {
+	ID          uuid.UUID `db:"order_id"`
+	OrgID       uuid.UUID `db:"org_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Status      string    `db:"status"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+type line struct {
+	OrderID   uuid.UUID   `db:"order_id"`
+	ProductID uuid.UUID   `db:"product_id"`
+	Quantity  int         `db:"quantity"`
+	Price     money.Money `db:"price"`
+}
+
+type stock struct {
+	ProductID   uuid.UUID `db:"product_id"`
+	Quantity    int       `db:"quantity"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBOrder(bus orderbus.Order) dbOrder {
+	return dbOrder{
+		ID:          bus.ID,
+		OrgID:       bus.OrgID,
+		UserID:      bus.UserID,
+		Status:      bus.Status.String(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toDBLines(bus orderbus.Order) []line {
+	lines := make([]line, len(bus.Lines))
+	for i, l := range bus.Lines {
+		lines[i] = line{
+			OrderID:   bus.ID,
+			ProductID: l.ProductID,
+			Quantity:  l.Quantity,
+			Price:     l.Price,
+		}
+	}
+
+	return lines
+}
+
+func toBusOrder(db dbOrder, dbLines []line) (orderbus.Order, error) {
+	status, err := orderstatus.Parse(db.Status)
+	if err != nil {
+		return orderbus.Order{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	lines := make([]orderbus.Line, len(dbLines))
+	for i, l := range dbLines {
+		lines[i] = orderbus.Line{
+			ProductID: l.ProductID,
+			Quantity:  l.Quantity,
+			Price:     l.Price,
+		}
+	}
+
+	bus := orderbus.Order{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		UserID:      db.UserID,
+		Status:      status,
+		Lines:       lines,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusOrders(dbOrds []dbOrder, dbLines []line) ([]orderbus.Order, error) {
+	byOrder := make(map[uuid.UUID][]line, len(dbOrds))
+	for _, l := range dbLines {
+		byOrder[l.OrderID] = append(byOrder[l.OrderID], l)
+	}
+
+	bus := make([]orderbus.Order, len(dbOrds))
+	for i, dbOrd := range dbOrds {
+		var err error
+		bus[i], err = toBusOrder(dbOrd, byOrder[dbOrd.ID])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+func toDBStock(bus orderbus.Stock) stock {
+	return stock{
+		ProductID:   bus.ProductID,
+		Quantity:    bus.Quantity,
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusStock(db stock) orderbus.Stock {
+	return orderbus.Stock{
+		ProductID:   db.ProductID,
+		Quantity:    db.Quantity,
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+}
+
+func toBusStocks(dbStocks []stock) []orderbus.Stock {
+	bus := make([]orderbus.Stock, len(dbStocks))
+	for i, s := range dbStocks {
+		bus[i] = toBusStock(s)
+	}
+
+	return bus
+}
@@ -0,0 +1,23 @@
+package orderdb
+
+import (
+	"fmt"
+	"service/business/domain/orderbus"
+	"service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	orderbus.OrderByID:          "order_id",
+	orderbus.OrderByUserID:      "user_id",
+	orderbus.OrderByStatus:      "status",
+	orderbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,339 @@
+// Package orderdb contains order and inventory related CRUD functionality.
+package orderdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"service/business/domain/orderbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/orderstatus"
+	"service/foundation/logger"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for order database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db sqlx.ExtContext) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (orderbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new order and its lines into the database. The order
+// must belong to the tenant in the context unless the call is a system one.
+func (s *Store) Create(ctx context.Context, ord orderbus.Order) error {
+	if err := tenant.Check(ctx, ord.OrgID); err != nil {
+		return fmt.Errorf("check: orgID[%s]: %w", ord.OrgID, err)
+	}
+
+	const q = `
+	INSERT INTO orders
+		(order_id, org_id, user_id, status, date_created, date_updated)
+	VALUES
+		(:order_id, :org_id, :user_id, :status, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBOrder(ord)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	const ql = `
+	INSERT INTO order_lines
+		(order_id, product_id, quantity, price, currency)
+	VALUES
+		(:order_id, :product_id, :quantity, (CAST(:price AS money_amount)).amount, (CAST(:price AS money_amount)).currency)`
+
+	for _, l := range toDBLines(ord) {
+		if err := sqldb.NamedExecContext(ctx, s.log, s.db, ql, l); err != nil {
+			return fmt.Errorf("namedexeccontext: line: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateStatus writes the status of the order if it is still in the
+// specified status, otherwise ErrInvalidTransition is returned.
+func (s *Store) UpdateStatus(ctx context.Context, ord orderbus.Order, from orderstatus.Status) error {
+	dbOrd := toDBOrder(ord)
+
+	data := map[string]any{
+		"order_id":     dbOrd.ID,
+		"status":       dbOrd.Status,
+		"from_status":  from.String(),
+		"date_updated": dbOrd.DateUpdated,
+	}
+
+	const q = `
+	UPDATE
+		orders
+	SET
+		"status" = :status,
+		"date_updated" = :date_updated
+	WHERE
+		order_id = :order_id AND
+		status = :from_status`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return fmt.Errorf("scope: %w", err)
+	}
+	buf.WriteString(" RETURNING order_id")
+
+	var updated struct {
+		ID string `db:"order_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &updated); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: status changed: %w", orderbus.ErrInvalidTransition)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing orders from the database.
+func (s *Store) Query(ctx context.Context, filter orderbus.QueryFilter, orderBy order.By, page page.Page) ([]orderbus.Order, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		order_id, org_id, user_id, status, date_created, date_updated
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, fmt.Errorf("scope: %w", err)
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbOrds []dbOrder
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbOrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	if len(dbOrds) == 0 {
+		return nil, nil
+	}
+
+	orderIDs := make([]string, len(dbOrds))
+	for i, dbOrd := range dbOrds {
+		orderIDs[i] = dbOrd.ID.String()
+	}
+
+	dbLines, err := s.queryLines(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBusOrders(dbOrds, dbLines)
+}
+
+// Count returns the total number of orders in the DB.
+func (s *Store) Count(ctx context.Context, filter orderbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, fmt.Errorf("scope: %w", err)
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified order and its lines from the database.
+func (s *Store) QueryByID(ctx context.Context, orderID uuid.UUID) (orderbus.Order, error) {
+	data := map[string]any{
+		"order_id": orderID.String(),
+	}
+
+	const q = `
+	SELECT
+		order_id, org_id, user_id, status, date_created, date_updated
+	FROM
+		orders
+	WHERE
+		order_id = :order_id`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return orderbus.Order{}, fmt.Errorf("scope: %w", err)
+	}
+
+	var dbOrd dbOrder
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbOrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orderbus.Order{}, fmt.Errorf("db: %w", orderbus.ErrNotFound)
+		}
+		return orderbus.Order{}, fmt.Errorf("db: %w", err)
+	}
+
+	dbLines, err := s.queryLines(ctx, []string{dbOrd.ID.String()})
+	if err != nil {
+		return orderbus.Order{}, err
+	}
+
+	return toBusOrder(dbOrd, dbLines)
+}
+
+// QueryStock gets the stock for the specified product from the database. A
+// product with no stock row has a quantity of zero.
+func (s *Store) QueryStock(ctx context.Context, productID uuid.UUID) (orderbus.Stock, error) {
+	data := struct {
+		ProductID string `db:"product_id"`
+	}{
+		ProductID: productID.String(),
+	}
+
+	const q = `
+	SELECT
+		product_id, quantity, date_updated
+	FROM
+		inventory
+	WHERE
+		product_id = :product_id`
+
+	var dbStock stock
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbStock); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return orderbus.Stock{ProductID: productID}, nil
+		}
+		return orderbus.Stock{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusStock(dbStock), nil
+}
+
+// QueryStockForUpdate gets the stock for the specified products and locks
+// the rows until the transaction ends. The rows are locked in product order
+// so concurrent orders for the same products can't deadlock. Products with
+// no stock row are not returned.
+func (s *Store) QueryStockForUpdate(ctx context.Context, productIDs []uuid.UUID) ([]orderbus.Stock, error) {
+	ids := make([]string, len(productIDs))
+	for i, id := range productIDs {
+		ids[i] = id.String()
+	}
+
+	data := struct {
+		ProductIDs []string `db:"product_ids"`
+	}{
+		ProductIDs: ids,
+	}
+
+	const q = `
+	SELECT
+		product_id, quantity, date_updated
+	FROM
+		inventory
+	WHERE
+		product_id IN (:product_ids)
+	ORDER BY
+		product_id
+	FOR UPDATE`
+
+	var dbStocks []stock
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbStocks); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusStocks(dbStocks), nil
+}
+
+// UpdateStock writes the stock for a product, adding the row if the
+// product has none.
+func (s *Store) UpdateStock(ctx context.Context, stk orderbus.Stock) error {
+	const q = `
+	INSERT INTO inventory
+		(product_id, quantity, date_updated)
+	VALUES
+		(:product_id, :quantity, :date_updated)
+	ON CONFLICT (product_id) DO UPDATE SET
+		quantity = EXCLUDED.quantity,
+		date_updated = EXCLUDED.date_updated`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBStock(stk)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+func (s *Store) queryLines(ctx context.Context, orderIDs []string) ([]line, error) {
+	data := struct {
+		OrderIDs []string `db:"order_ids"`
+	}{
+		OrderIDs: orderIDs,
+	}
+
+	const q = `
+	SELECT
+		order_id, product_id, quantity, CAST((price, currency) AS money_amount) AS price
+	FROM
+		order_lines
+	WHERE
+		order_id IN (:order_ids)
+	ORDER BY
+		product_id`
+
+	var dbLines []line
+	if err := sqldb.NamedQuerySliceUsingIn(ctx, s.log, s.db, q, data, &dbLines); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: lines: %w", err)
+	}
+
+	return dbLines, nil
+}
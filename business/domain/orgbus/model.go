@@ -0,0 +1,20 @@
+package orgbus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Org represents an organization that owns a set of users.
+type Org struct {
+	ID          uuid.UUID
+	Name        string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewOrg contains information needed to create a new organization.
+type NewOrg struct {
+	Name string
+}
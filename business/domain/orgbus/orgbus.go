@@ -0,0 +1,135 @@
+// Package orgbus provides business access to the organization domain.
+package orgbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/foundation/logger"
+	"service/foundation/otel"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound   = errors.New("organization not found")
+	ErrUniqueName = errors.New("organization name is not unique")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, org Org) error
+	Query(ctx context.Context, page page.Page) ([]Org, error)
+	QueryByID(ctx context.Context, orgID uuid.UUID) (Org, error)
+}
+
+// Business manages the set of APIs for organization access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs an organization business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new organization to the system.
+func (b *Business) Create(ctx context.Context, no NewOrg) (Org, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.create")
+	defer span.End()
+
+	now := time.Now()
+
+	org := Org{
+		ID:          uuid.New(),
+		Name:        no.Name,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, org); err != nil {
+		return Org{}, fmt.Errorf("create: %w", err)
+	}
+
+	return org, nil
+}
+
+// QueryByID finds the organization by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, orgID uuid.UUID) (Org, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.querybyid")
+	defer span.End()
+
+	org, err := b.storer.QueryByID(ctx, orgID)
+	if err != nil {
+		return Org{}, fmt.Errorf("query: orgID[%s]: %w", orgID, err)
+	}
+
+	return org, nil
+}
+
+// Query retrieves a list of existing organizations in the order they were
+// created.
+func (b *Business) Query(ctx context.Context, page page.Page) ([]Org, error) {
+	ctx, span := otel.AddSpan(ctx, "business.orgbus.query")
+	defer span.End()
+
+	orgs, err := b.storer.Query(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// ForEach runs the function once for every organization with the
+// organization set as the tenant of the context. It lets a background job
+// reach the data of every tenant when each one lives in a schema of its
+// own. A failure for one organization doesn't stop the others from running
+// and the errors are returned together.
+func (b *Business) ForEach(ctx context.Context, fn func(ctx context.Context) error) error {
+	var errs []error
+	for n := 1; ; n++ {
+		orgs, err := b.Query(ctx, page.MustParse(strconv.Itoa(n), "100"))
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+
+		for _, org := range orgs {
+			if err := fn(tenant.Set(ctx, org.ID)); err != nil {
+				errs = append(errs, fmt.Errorf("orgID[%s]: %w", org.ID, err))
+			}
+		}
+
+		if len(orgs) < 100 {
+			return errors.Join(errs...)
+		}
+	}
+}
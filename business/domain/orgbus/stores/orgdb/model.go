@@ -0,0 +1,43 @@
+package orgdb
+
+import (
+	"service/business/domain/orgbus"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type org struct {
+	ID          uuid.UUID `db:"org_id"`
+	Name        string    `db:"name"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBOrg(bus orgbus.Org) org {
+	return org{
+		ID:          bus.ID,
+		Name:        bus.Name,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusOrg(db org) orgbus.Org {
+	return orgbus.Org{
+		ID:          db.ID,
+		Name:        db.Name,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+}
+
+func toBusOrgs(dbs []org) []orgbus.Org {
+	bus := make([]orgbus.Org, len(dbs))
+
+	for i, db := range dbs {
+		bus[i] = toBusOrg(db)
+	}
+
+	return bus
+}
@@ -0,0 +1,64 @@
+package productbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"service/business/domain/userbus"
+	"service/business/sdk/delegate"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "product"
+
+// registerDelegateFunctions subscribes this domain to the actions of other
+// domains it needs to react to.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(userbus.DomainName, userbus.ActionDeleted, b.actionUserDeleted)
+	}
+}
+
+// actionUserDeleted removes the products owned by a user that is being
+// permanently removed from the system. When the user is removed inside a
+// transaction the products are removed in it as well.
+func (b *Business) actionUserDeleted(ctx context.Context, data delegate.Data) error {
+	var params userbus.ActionDeletedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	b.log.Info(ctx, "action-userdeleted", "user_id", params.UserID)
+
+	storer := b.storer
+	if tx, ok := sqldb.GetTx(ctx); ok {
+		var err error
+		if storer, err = b.storer.NewWithTx(tx); err != nil {
+			return err
+		}
+	}
+
+	var filter QueryFilter
+	filter.WithUserID(params.UserID)
+
+	pg := page.MustParse("1", "100")
+
+	for {
+		prds, err := storer.Query(ctx, filter, DefaultOrderBy, pg)
+		if err != nil {
+			return fmt.Errorf("query: userID[%s]: %w", params.UserID, err)
+		}
+
+		if len(prds) == 0 {
+			return nil
+		}
+
+		for _, prd := range prds {
+			if err := storer.Delete(ctx, prd); err != nil {
+				return fmt.Errorf("delete: productID[%s]: %w", prd.ID, err)
+			}
+		}
+	}
+}
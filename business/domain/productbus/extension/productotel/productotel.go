@@ -0,0 +1,85 @@
+package productotel
+
+import (
+	"context"
+	"service/business/domain/productbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/foundation/otel"
+
+	"github.com/google/uuid"
+)
+
+type Extension struct {
+	bus productbus.ExtBusiness
+}
+
+// NewExtension constructs a new extension that wraps the productbus with otel.
+func NewExtension() productbus.Extension {
+	return func(bus productbus.ExtBusiness) productbus.ExtBusiness {
+		return &Extension{
+			bus: bus,
+		}
+	}
+}
+
+// NewWithTx applies otel to the business value using the transaction.
+func (ext *Extension) NewWithTx(tx sqldb.CommitRollbacker) (productbus.ExtBusiness, error) {
+	bus, err := ext.bus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extension{
+		bus: bus,
+	}, nil
+}
+
+// Create applies otel to the product creation process.
+func (ext *Extension) Create(ctx context.Context, actorID uuid.UUID, np productbus.NewProduct) (productbus.Product, error) {
+	ctx, span := otel.AddSpan(ctx, "business.productbus.create")
+	defer span.End()
+
+	return ext.bus.Create(ctx, actorID, np)
+}
+
+// Update applies otel to the product update process.
+func (ext *Extension) Update(ctx context.Context, actorID uuid.UUID, prd productbus.Product, up productbus.UpdateProduct) (productbus.Product, error) {
+	ctx, span := otel.AddSpan(ctx, "business.productbus.update")
+	defer span.End()
+
+	return ext.bus.Update(ctx, actorID, prd, up)
+}
+
+// Delete applies otel to the product deletion process.
+func (ext *Extension) Delete(ctx context.Context, actorID uuid.UUID, prd productbus.Product) error {
+	ctx, span := otel.AddSpan(ctx, "business.productbus.delete")
+	defer span.End()
+
+	return ext.bus.Delete(ctx, actorID, prd)
+}
+
+// Query applies otel to the product query process.
+func (ext *Extension) Query(ctx context.Context, filter productbus.QueryFilter, orderBy order.By, page page.Page) ([]productbus.Product, error) {
+	ctx, span := otel.AddSpan(ctx, "business.productbus.query")
+	defer span.End()
+
+	return ext.bus.Query(ctx, filter, orderBy, page)
+}
+
+// Count applies otel to the product count process.
+func (ext *Extension) Count(ctx context.Context, filter productbus.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.productbus.count")
+	defer span.End()
+
+	return ext.bus.Count(ctx, filter)
+}
+
+// QueryByID applies otel to the product query by id process.
+func (ext *Extension) QueryByID(ctx context.Context, productID uuid.UUID) (productbus.Product, error) {
+	ctx, span := otel.AddSpan(ctx, "business.productbus.querybyid")
+	defer span.End()
+
+	return ext.bus.QueryByID(ctx, productID)
+}
@@ -0,0 +1,53 @@
+package productbus
+
+import (
+	"fmt"
+	"service/app/sdk/errs"
+	"service/business/types/money"
+
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ID       *uuid.UUID
+	UserID   *uuid.UUID
+	Name     *string `validate:"omitempty,min=3"`
+	Cost     *money.Money
+	Quantity *int
+}
+
+// Validate can perform a check of the data against the validate tags.
+func (qf *QueryFilter) Validate() error {
+	if err := errs.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// WithProductID sets the ID field of the QueryFilter value.
+func (qf *QueryFilter) WithProductID(productID uuid.UUID) {
+	qf.ID = &productID
+}
+
+// WithUserID sets the UserID field of the QueryFilter value.
+func (qf *QueryFilter) WithUserID(userID uuid.UUID) {
+	qf.UserID = &userID
+}
+
+// WithName sets the Name field of the QueryFilter value.
+func (qf *QueryFilter) WithName(name string) {
+	qf.Name = &name
+}
+
+// WithCost sets the Cost field of the QueryFilter value.
+func (qf *QueryFilter) WithCost(cost money.Money) {
+	qf.Cost = &cost
+}
+
+// WithQuantity sets the Quantity field of the QueryFilter value.
+func (qf *QueryFilter) WithQuantity(quantity int) {
+	qf.Quantity = &quantity
+}
@@ -0,0 +1,37 @@
+package productbus
+
+import (
+	"service/business/types/money"
+	"service/business/types/name"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Product represents an individual product owned by a user. The product
+// belongs to the organization of that user.
+type Product struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	UserID      uuid.UUID
+	Name        name.Name
+	Cost        money.Money
+	Quantity    int
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewProduct contains information needed to create a new product.
+type NewProduct struct {
+	UserID   uuid.UUID
+	Name     name.Name
+	Cost     money.Money
+	Quantity int
+}
+
+// UpdateProduct contains information needed to update a product.
+type UpdateProduct struct {
+	Name     *name.Name
+	Cost     *money.Money
+	Quantity *int
+}
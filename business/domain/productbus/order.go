@@ -0,0 +1,16 @@
+package productbus
+
+import "service/business/sdk/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "a"
+	OrderByUserID      = "b"
+	OrderByName        = "c"
+	OrderByCost        = "d"
+	OrderByQuantity    = "e"
+	OrderByDateCreated = "f"
+)
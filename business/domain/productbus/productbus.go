@@ -0,0 +1,220 @@
+// Package productbus provides business access to the product domain.
+package productbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/business/domain/userbus"
+	"service/business/sdk/delegate"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/types/money"
+	"service/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound     = errors.New("product not found")
+	ErrUserDisabled = errors.New("user disabled")
+	ErrInvalidCost  = errors.New("cost not valid")
+	ErrInvalidQty   = errors.New("quantity not valid")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, prd Product) error
+	Update(ctx context.Context, prd Product) error
+	Delete(ctx context.Context, prd Product) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, productID uuid.UUID) (Product, error)
+}
+
+// ExtBusiness interface provides support for extensions that wrap extra functionality
+// around the core busines logic.
+type ExtBusiness interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (ExtBusiness, error)
+	Create(ctx context.Context, actorID uuid.UUID, np NewProduct) (Product, error)
+	Update(ctx context.Context, actorID uuid.UUID, prd Product, up UpdateProduct) (Product, error)
+	Delete(ctx context.Context, actorID uuid.UUID, prd Product) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, productID uuid.UUID) (Product, error)
+}
+
+// Extension is a function that wraps a new layer of business logic
+// around the existing business logic.
+type Extension func(ExtBusiness) ExtBusiness
+
+// Business manages the set of APIs for product access.
+type Business struct {
+	log      *logger.Logger
+	userBus  userbus.ExtBusiness
+	delegate *delegate.Delegate
+	storer   Storer
+}
+
+// NewBusiness constructs a product business API for use. The products of a
+// user are removed when the user is permanently deleted.
+func NewBusiness(log *logger.Logger, userBus userbus.ExtBusiness, delegate *delegate.Delegate, storer Storer, extensions ...Extension) ExtBusiness {
+	bus := Business{
+		log:      log,
+		userBus:  userBus,
+		delegate: delegate,
+		storer:   storer,
+	}
+
+	bus.registerDelegateFunctions()
+
+	b := ExtBusiness(&bus)
+
+	for i := len(extensions) - 1; i >= 0; i-- {
+		ext := extensions[i]
+		if ext != nil {
+			b = ext(b)
+		}
+	}
+
+	return b
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (ExtBusiness, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := b.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:      b.log,
+		userBus:  userBus,
+		delegate: b.delegate,
+		storer:   storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new product to the system. The product must be owned by an
+// enabled user.
+func (b *Business) Create(ctx context.Context, actorID uuid.UUID, np NewProduct) (Product, error) {
+	if err := validate(np.Cost, np.Quantity); err != nil {
+		return Product{}, err
+	}
+
+	usr, err := b.userBus.QueryByID(ctx, np.UserID)
+	if err != nil {
+		return Product{}, fmt.Errorf("user.querybyid: %s: %w", np.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return Product{}, ErrUserDisabled
+	}
+
+	now := time.Now()
+
+	prd := Product{
+		ID:          uuid.New(),
+		OrgID:       usr.OrgID,
+		UserID:      np.UserID,
+		Name:        np.Name,
+		Cost:        np.Cost,
+		Quantity:    np.Quantity,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.storer.Create(ctx, prd); err != nil {
+		return Product{}, fmt.Errorf("create: %w", err)
+	}
+
+	return prd, nil
+}
+
+// Update modifies information about a product.
+func (b *Business) Update(ctx context.Context, actorID uuid.UUID, prd Product, up UpdateProduct) (Product, error) {
+	if up.Name != nil {
+		prd.Name = *up.Name
+	}
+
+	if up.Cost != nil {
+		prd.Cost = *up.Cost
+	}
+
+	if up.Quantity != nil {
+		prd.Quantity = *up.Quantity
+	}
+
+	if err := validate(prd.Cost, prd.Quantity); err != nil {
+		return Product{}, err
+	}
+
+	prd.DateUpdated = time.Now()
+
+	if err := b.storer.Update(ctx, prd); err != nil {
+		return Product{}, fmt.Errorf("update: %w", err)
+	}
+
+	return prd, nil
+}
+
+// Delete removes the specified product.
+func (b *Business) Delete(ctx context.Context, actorID uuid.UUID, prd Product) error {
+	if err := b.storer.Delete(ctx, prd); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing products.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error) {
+	prds, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return prds, nil
+}
+
+// Count returns the total number of products.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the product by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, productID uuid.UUID) (Product, error) {
+	prd, err := b.storer.QueryByID(ctx, productID)
+	if err != nil {
+		return Product{}, fmt.Errorf("query: productID[%s]: %w", productID, err)
+	}
+
+	return prd, nil
+}
+
+// =============================================================================
+
+func validate(cost money.Money, quantity int) error {
+	if cost.IsNegative() || cost.Currency().String() == "" {
+		return fmt.Errorf("cost[%v]: %w", cost, ErrInvalidCost)
+	}
+
+	if quantity < 0 {
+		return fmt.Errorf("quantity[%d]: %w", quantity, ErrInvalidQty)
+	}
+
+	return nil
+}
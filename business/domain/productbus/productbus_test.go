@@ -0,0 +1,221 @@
+package productbus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"service/business/domain/productbus"
+	"service/business/domain/userbus"
+	"service/business/sdk/dbtest"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/sdk/unitest"
+	"service/business/types/money"
+	"service/business/types/name"
+	"service/business/types/role"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Product(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Product")
+
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain, sd), "create")
+	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, userDeleted(db.BusDomain, sqldb.NewBeginner(db.DB), sd), "userdeleted")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
+	ctx := tenant.System(context.Background())
+
+	usrs, err := userbus.TestSeedUsers(ctx, 2, role.UserRole, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds1, err := productbus.TestSeedProducts(ctx, 2, usrs[0].ID, busDomain.Product)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	prds2, err := productbus.TestSeedProducts(ctx, 2, usrs[1].ID, busDomain.Product)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{
+			{User: usrs[0], Products: prds1},
+			{User: usrs[1], Products: prds2},
+		},
+	}
+
+	return sd, nil
+}
+
+func create(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: productbus.Product{
+				OrgID:    sd.Users[0].OrgID,
+				UserID:   sd.Users[0].ID,
+				Name:     name.MustParse("Guitar"),
+				Cost:     money.MustParse("10.34", "USD"),
+				Quantity: 10,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				np := productbus.NewProduct{
+					UserID:   sd.Users[0].ID,
+					Name:     name.MustParse("Guitar"),
+					Cost:     money.MustParse("10.34", "USD"),
+					Quantity: 10,
+				}
+
+				prd, err := busDomain.Product.Create(ctx, sd.Users[0].ID, np)
+				if err != nil {
+					return err
+				}
+
+				return prd
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(productbus.Product)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(productbus.Product)
+
+				expResp.ID = gotResp.ID
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "invalid-cost",
+			ExpResp: productbus.ErrInvalidCost,
+			ExcFunc: func(ctx context.Context) any {
+				np := productbus.NewProduct{
+					UserID:   sd.Users[0].ID,
+					Name:     name.MustParse("Guitar"),
+					Cost:     money.New(-1, money.USD),
+					Quantity: 10,
+				}
+
+				_, err := busDomain.Product.Create(ctx, sd.Users[0].ID, np)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if !errors.Is(got.(error), exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	nme := name.MustParse("Guitar Stand")
+	cost := money.MustParse("25.50", "USD")
+
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: productbus.Product{
+				ID:          sd.Users[0].Products[0].ID,
+				OrgID:       sd.Users[0].OrgID,
+				UserID:      sd.Users[0].ID,
+				Name:        nme,
+				Cost:        cost,
+				Quantity:    sd.Users[0].Products[0].Quantity,
+				DateCreated: sd.Users[0].Products[0].DateCreated,
+			},
+			ExcFunc: func(ctx context.Context) any {
+				up := productbus.UpdateProduct{
+					Name: &nme,
+					Cost: &cost,
+				}
+
+				if _, err := busDomain.Product.Update(ctx, sd.Users[0].ID, sd.Users[0].Products[0], up); err != nil {
+					return err
+				}
+
+				prd, err := busDomain.Product.QueryByID(ctx, sd.Users[0].Products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return prd
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(productbus.Product)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(productbus.Product)
+
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+	}
+
+	return table
+}
+
+func userDeleted(busDomain dbtest.BusDomain, bgn sqldb.Beginner, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "purge",
+			ExpResp: 0,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.Delete(ctx, sd.Users[0].ID, sd.Users[1].User); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.Purge(ctx, bgn, time.Now()); err != nil {
+					return err
+				}
+
+				var filter productbus.QueryFilter
+				filter.WithUserID(sd.Users[1].ID)
+
+				prds, err := busDomain.Product.Query(ctx, filter, productbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return len(prds)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,143 @@
+// Package productcache contains product related CRUD functionality with
+// caching.
+package productcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/business/domain/productbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/foundation/logger"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viccon/sturdyc"
+)
+
+// Store manages the set of APIs for product data and caching.
+type Store struct {
+	log    *logger.Logger
+	storer productbus.Storer
+	cache  *sturdyc.Client[productbus.Product]
+}
+
+// NewStore constructs the api for data and caching access.
+func NewStore(log *logger.Logger, storer productbus.Storer, ttl time.Duration) *Store {
+	const capacity = 10000
+	const numShards = 10
+	const evictionPercentage = 10
+
+	return &Store{
+		log:    log,
+		storer: storer,
+		cache:  sturdyc.New[productbus.Product](capacity, numShards, ttl, evictionPercentage),
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (productbus.Storer, error) {
+	txStorer, err := s.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log:    s.log,
+		storer: txStorer,
+		cache:  s.cache,
+	}
+
+	return &store, nil
+}
+
+// Create adds a product to the database.
+func (s *Store) Create(ctx context.Context, prd productbus.Product) error {
+	if err := s.storer.Create(ctx, prd); err != nil {
+		return err
+	}
+
+	s.writeCache(prd)
+
+	return nil
+}
+
+// Update modifies data about a product in the database.
+func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
+	if err := s.storer.Update(ctx, prd); err != nil {
+		return err
+	}
+
+	s.writeCache(prd)
+
+	return nil
+}
+
+// Delete removes the product identified by a given ID.
+func (s *Store) Delete(ctx context.Context, prd productbus.Product) error {
+	if err := s.storer.Delete(ctx, prd); err != nil {
+		return err
+	}
+
+	s.deleteCache(prd)
+
+	return nil
+}
+
+// Query gets all products from the database.
+func (s *Store) Query(ctx context.Context, filter productbus.QueryFilter, orderBy order.By, page page.Page) ([]productbus.Product, error) {
+	return s.storer.Query(ctx, filter, orderBy, page)
+}
+
+// Count returns the total number of products in the DB.
+func (s *Store) Count(ctx context.Context, filter productbus.QueryFilter) (int, error) {
+	return s.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the product identified by a given ID. The cache is shared
+// by all tenants, so a cached product is only returned to its own tenant.
+func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (productbus.Product, error) {
+	cachedPrd, ok := s.readCache(productID.String())
+	if ok {
+		if err := tenant.Check(ctx, cachedPrd.OrgID); err != nil {
+			if errors.Is(err, tenant.ErrCrossTenant) {
+				return productbus.Product{}, fmt.Errorf("check: %w", productbus.ErrNotFound)
+			}
+			return productbus.Product{}, fmt.Errorf("check: %w", err)
+		}
+		return cachedPrd, nil
+	}
+
+	prd, err := s.storer.QueryByID(ctx, productID)
+	if err != nil {
+		return productbus.Product{}, err
+	}
+
+	s.writeCache(prd)
+
+	return prd, nil
+}
+
+// readCache performs a safe search in the cache for the specified key.
+func (s *Store) readCache(key string) (productbus.Product, bool) {
+	prd, exists := s.cache.Get(key)
+	if !exists {
+		return productbus.Product{}, false
+	}
+
+	return prd, true
+}
+
+// writeCache performs a safe write to the cache for the specified product.
+func (s *Store) writeCache(prd productbus.Product) {
+	s.cache.Set(prd.ID.String(), prd)
+}
+
+// deleteCache performs a safe removal from the cache for the specified product.
+func (s *Store) deleteCache(prd productbus.Product) {
+	s.cache.Delete(prd.ID.String())
+}
@@ -0,0 +1,53 @@
+package productdb
+
+import (
+	"bytes"
+	"context"
+	"service/business/domain/productbus"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+)
+
+// applyTenant adds the condition restricting a statement that already has
+// a WHERE clause to the tenant in the context.
+func applyTenant(ctx context.Context, data map[string]any, buf *bytes.Buffer) error {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	if scoped {
+		data["org_id"] = orgID
+		buf.WriteString(" AND org_id = :org_id")
+	}
+
+	return nil
+}
+
+func applyFilter(ctx context.Context, filter productbus.QueryFilter, data map[string]any, buf *bytes.Buffer) error {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	f := sqldb.NewFilter(data)
+
+	f.Eq("product_id", filter.ID)
+	f.Eq("user_id", filter.UserID)
+	f.Like("name", filter.Name)
+
+	if filter.Cost != nil {
+		f.Eq("cost", filter.Cost.Decimal())
+		f.Eq("currency", filter.Cost.Currency().String())
+	}
+
+	f.Eq("quantity", filter.Quantity)
+
+	if scoped {
+		f.Eq("org_id", &orgID)
+	}
+
+	f.Apply(buf)
+
+	return nil
+}
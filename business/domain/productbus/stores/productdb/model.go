@@ -0,0 +1,69 @@
+package productdb
+
+import (
+	"fmt"
+	"service/business/domain/productbus"
+	"service/business/types/money"
+	"service/business/types/name"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type product struct {
+	ID          uuid.UUID   `db:"product_id"`
+	OrgID       uuid.UUID   `db:"org_id"`
+	UserID      uuid.UUID   `db:"user_id"`
+	Name        string      `db:"name"`
+	Cost        money.Money `db:"cost"`
+	Quantity    int         `db:"quantity"`
+	DateCreated time.Time   `db:"date_created"`
+	DateUpdated time.Time   `db:"date_updated"`
+}
+
+func toDBProduct(bus productbus.Product) product {
+	return product{
+		ID:          bus.ID,
+		OrgID:       bus.OrgID,
+		UserID:      bus.UserID,
+		Name:        bus.Name.String(),
+		Cost:        bus.Cost,
+		Quantity:    bus.Quantity,
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusProduct(db product) (productbus.Product, error) {
+	nme, err := name.Parse(db.Name)
+	if err != nil {
+		return productbus.Product{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	bus := productbus.Product{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		UserID:      db.UserID,
+		Name:        nme,
+		Cost:        db.Cost,
+		Quantity:    db.Quantity,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusProducts(dbPrds []product) ([]productbus.Product, error) {
+	bus := make([]productbus.Product, len(dbPrds))
+
+	for i, dbPrd := range dbPrds {
+		var err error
+		bus[i], err = toBusProduct(dbPrd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,25 @@
+package productdb
+
+import (
+	"fmt"
+	"service/business/domain/productbus"
+	"service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	productbus.OrderByID:          "product_id",
+	productbus.OrderByUserID:      "user_id",
+	productbus.OrderByName:        "name",
+	productbus.OrderByCost:        "cost",
+	productbus.OrderByQuantity:    "quantity",
+	productbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,217 @@
+// Package productdb contains product related CRUD functionality.
+package productdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"service/business/domain/productbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/foundation/logger"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for product database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db sqlx.ExtContext) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (productbus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create adds a product to the database. The product must belong to the
+// tenant in the context unless the call is a system one.
+func (s *Store) Create(ctx context.Context, prd productbus.Product) error {
+	if err := tenant.Check(ctx, prd.OrgID); err != nil {
+		return fmt.Errorf("check: orgID[%s]: %w", prd.OrgID, err)
+	}
+
+	const q = `
+	INSERT INTO products
+		(product_id, org_id, user_id, name, cost, currency, quantity, date_created, date_updated)
+	VALUES
+		(:product_id, :org_id, :user_id, :name, (CAST(:cost AS money_amount)).amount, (CAST(:cost AS money_amount)).currency, :quantity, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBProduct(prd)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update modifies data about a product in the database.
+func (s *Store) Update(ctx context.Context, prd productbus.Product) error {
+	dbPrd := toDBProduct(prd)
+
+	data := map[string]any{
+		"product_id":   dbPrd.ID,
+		"name":         dbPrd.Name,
+		"cost":         dbPrd.Cost,
+		"quantity":     dbPrd.Quantity,
+		"date_updated": dbPrd.DateUpdated,
+	}
+
+	const q = `
+	UPDATE
+		products
+	SET
+		"name" = :name,
+		"cost" = (CAST(:cost AS money_amount)).amount,
+		"currency" = (CAST(:cost AS money_amount)).currency,
+		"quantity" = :quantity,
+		"date_updated" = :date_updated
+	WHERE
+		product_id = :product_id`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return fmt.Errorf("scope: %w", err)
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the product identified by a given ID.
+func (s *Store) Delete(ctx context.Context, prd productbus.Product) error {
+	data := map[string]any{
+		"product_id": prd.ID,
+	}
+
+	const q = `
+	DELETE FROM
+		products
+	WHERE
+		product_id = :product_id`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return fmt.Errorf("scope: %w", err)
+	}
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query gets all products from the database.
+func (s *Store) Query(ctx context.Context, filter productbus.QueryFilter, orderBy order.By, page page.Page) ([]productbus.Product, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		product_id, org_id, user_id, name, CAST((cost, currency) AS money_amount) AS cost, quantity, date_created, date_updated
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, fmt.Errorf("scope: %w", err)
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbPrds []product
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusProducts(dbPrds)
+}
+
+// Count returns the total number of products in the DB.
+func (s *Store) Count(ctx context.Context, filter productbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, fmt.Errorf("scope: %w", err)
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID finds the product identified by a given ID.
+func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (productbus.Product, error) {
+	data := map[string]any{
+		"product_id": productID.String(),
+	}
+
+	const q = `
+	SELECT
+		product_id, org_id, user_id, name, CAST((cost, currency) AS money_amount) AS cost, quantity, date_created, date_updated
+	FROM
+		products
+	WHERE
+		product_id = :product_id`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return productbus.Product{}, fmt.Errorf("scope: %w", err)
+	}
+
+	var dbPrd product
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbPrd); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return productbus.Product{}, fmt.Errorf("db: %w", productbus.ErrNotFound)
+		}
+		return productbus.Product{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusProduct(dbPrd)
+}
@@ -0,0 +1,49 @@
+package productbus
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"service/business/types/money"
+	"service/business/types/name"
+
+	"github.com/google/uuid"
+)
+
+// TestNewProducts is a helper method for testing.
+func TestNewProducts(n int, userID uuid.UUID) []NewProduct {
+	newPrds := make([]NewProduct, n)
+
+	idx := rand.Intn(10000)
+	for i := range n {
+		idx++
+
+		np := NewProduct{
+			UserID:   userID,
+			Name:     name.MustParse(fmt.Sprintf("Name%d", idx)),
+			Cost:     money.New(int64(rand.Intn(50000)), money.USD),
+			Quantity: rand.Intn(50),
+		}
+
+		newPrds[i] = np
+	}
+
+	return newPrds
+}
+
+// TestSeedProducts is a helper method for testing.
+func TestSeedProducts(ctx context.Context, n int, userID uuid.UUID, api ExtBusiness) ([]Product, error) {
+	newPrds := TestNewProducts(n, userID)
+
+	prds := make([]Product, len(newPrds))
+	for i, np := range newPrds {
+		prd, err := api.Create(ctx, uuid.UUID{}, np)
+		if err != nil {
+			return nil, fmt.Errorf("seeding product: idx: %d : %w", i, err)
+		}
+
+		prds[i] = prd
+	}
+
+	return prds, nil
+}
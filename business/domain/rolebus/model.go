@@ -0,0 +1,33 @@
+package rolebus
+
+import (
+	"service/business/types/role"
+	"time"
+)
+
+// Role represents a role with the permissions it grants. A role also grants
+// the permissions of the roles it inherits.
+type Role struct {
+	Name        role.Role
+	Description string
+	Permissions []string
+	Inherits    []role.Role
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewRole contains information needed to create a new role.
+type NewRole struct {
+	Name        role.Role
+	Description string
+	Permissions []string
+	Inherits    []role.Role
+}
+
+// UpdateRole contains information needed to update a role. A nil slice
+// leaves the field unchanged.
+type UpdateRole struct {
+	Description *string
+	Permissions []string
+	Inherits    []role.Role
+}
@@ -0,0 +1,258 @@
+// Package rolebus provides business access to the role domain.
+package rolebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"service/business/sdk/sqldb"
+	"service/business/types/role"
+	"service/foundation/logger"
+	"service/foundation/otel"
+	"slices"
+	"time"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound          = errors.New("role not found")
+	ErrUniqueName        = errors.New("role name is not unique")
+	ErrBuiltIn           = errors.New("built-in roles can't be removed")
+	ErrInUse             = errors.New("role is inherited by other roles")
+	ErrCycle             = errors.New("role inheritance has a cycle")
+	ErrUnknownRole       = errors.New("role does not exist")
+	ErrInvalidPermission = errors.New("permission not valid")
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, rl Role) error
+	Update(ctx context.Context, rl Role) error
+	Delete(ctx context.Context, rl Role) error
+	QueryAll(ctx context.Context) ([]Role, error)
+	QueryByName(ctx context.Context, name role.Role) (Role, error)
+}
+
+// Business manages the set of APIs for role access.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness constructs a role business API for use.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new business value that will use the
+// specified transaction in any store related calls.
+func (b *Business) NewWithTx(tx sqldb.CommitRollbacker) (*Business, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bus := Business{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &bus, nil
+}
+
+// Create adds a new role to the system.
+func (b *Business) Create(ctx context.Context, nr NewRole) (Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rolebus.create")
+	defer span.End()
+
+	now := time.Now()
+
+	rl := Role{
+		Name:        nr.Name,
+		Description: nr.Description,
+		Permissions: nr.Permissions,
+		Inherits:    nr.Inherits,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := b.validate(ctx, rl); err != nil {
+		return Role{}, err
+	}
+
+	if err := b.storer.Create(ctx, rl); err != nil {
+		return Role{}, fmt.Errorf("create: %w", err)
+	}
+
+	return rl, nil
+}
+
+// Update modifies information about a role.
+func (b *Business) Update(ctx context.Context, rl Role, ur UpdateRole) (Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rolebus.update")
+	defer span.End()
+
+	if ur.Description != nil {
+		rl.Description = *ur.Description
+	}
+
+	if ur.Permissions != nil {
+		rl.Permissions = ur.Permissions
+	}
+
+	if ur.Inherits != nil {
+		rl.Inherits = ur.Inherits
+	}
+
+	rl.DateUpdated = time.Now()
+
+	if err := b.validate(ctx, rl); err != nil {
+		return Role{}, err
+	}
+
+	if err := b.storer.Update(ctx, rl); err != nil {
+		return Role{}, fmt.Errorf("update: %w", err)
+	}
+
+	return rl, nil
+}
+
+// Delete removes the specified role. Built-in roles and roles inherited by
+// other roles can't be removed.
+func (b *Business) Delete(ctx context.Context, rl Role) error {
+	ctx, span := otel.AddSpan(ctx, "business.rolebus.delete")
+	defer span.End()
+
+	if rl.Name.BuiltIn() {
+		return ErrBuiltIn
+	}
+
+	rls, err := b.storer.QueryAll(ctx)
+	if err != nil {
+		return fmt.Errorf("queryall: %w", err)
+	}
+
+	for _, other := range rls {
+		if slices.Contains(other.Inherits, rl.Name) {
+			return fmt.Errorf("inherited by %s: %w", other.Name, ErrInUse)
+		}
+	}
+
+	if err := b.storer.Delete(ctx, rl); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// QueryAll retrieves all the roles in the system.
+func (b *Business) QueryAll(ctx context.Context) ([]Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rolebus.queryall")
+	defer span.End()
+
+	rls, err := b.storer.QueryAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("queryall: %w", err)
+	}
+
+	return rls, nil
+}
+
+// QueryByName finds the role by the specified name.
+func (b *Business) QueryByName(ctx context.Context, name role.Role) (Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rolebus.querybyname")
+	defer span.End()
+
+	rl, err := b.storer.QueryByName(ctx, name)
+	if err != nil {
+		return Role{}, fmt.Errorf("query: name[%s]: %w", name, err)
+	}
+
+	return rl, nil
+}
+
+// Check validates that all the specified roles exist so they can be
+// assigned to a user.
+func (b *Business) Check(ctx context.Context, roles []role.Role) error {
+	ctx, span := otel.AddSpan(ctx, "business.rolebus.check")
+	defer span.End()
+
+	for _, r := range roles {
+		if r.BuiltIn() {
+			continue
+		}
+
+		if _, err := b.storer.QueryByName(ctx, r); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return fmt.Errorf("%s: %w", r, ErrUnknownRole)
+			}
+			return fmt.Errorf("query: name[%s]: %w", r, err)
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+var permissionRegEx = regexp.MustCompile("^[a-z][a-z0-9_:.-]{0,63}$")
+
+// validate checks the permissions of the role and that the roles it
+// inherits exist without creating a cycle.
+func (b *Business) validate(ctx context.Context, rl Role) error {
+	for _, p := range rl.Permissions {
+		if !permissionRegEx.MatchString(p) {
+			return fmt.Errorf("permission[%s]: %w", p, ErrInvalidPermission)
+		}
+	}
+
+	rls, err := b.storer.QueryAll(ctx)
+	if err != nil {
+		return fmt.Errorf("queryall: %w", err)
+	}
+
+	graph := make(map[role.Role][]role.Role, len(rls)+1)
+	for _, other := range rls {
+		graph[other.Name] = other.Inherits
+	}
+	graph[rl.Name] = rl.Inherits
+
+	for _, parent := range rl.Inherits {
+		if _, exists := graph[parent]; !exists {
+			return fmt.Errorf("inherits %s: %w", parent, ErrUnknownRole)
+		}
+	}
+
+	if reaches(graph, rl.Inherits, rl.Name, make(map[role.Role]bool)) {
+		return ErrCycle
+	}
+
+	return nil
+}
+
+// reaches reports whether the target role can be reached by following the
+// inheritance of the specified roles.
+func reaches(graph map[role.Role][]role.Role, from []role.Role, target role.Role, seen map[role.Role]bool) bool {
+	for _, r := range from {
+		if r == target {
+			return true
+		}
+
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+
+		if reaches(graph, graph[r], target, seen) {
+			return true
+		}
+	}
+
+	return false
+}
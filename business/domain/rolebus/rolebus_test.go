@@ -0,0 +1,179 @@
+package rolebus_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"service/business/domain/rolebus"
+	"service/business/sdk/dbtest"
+	"service/business/sdk/unitest"
+	"service/business/types/role"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Role(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Role")
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.BusDomain), "create")
+	unitest.Run(t, hierarchy(db.BusDomain), "hierarchy")
+	unitest.Run(t, remove(db.BusDomain), "delete")
+}
+
+// =============================================================================
+
+func create(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name: "basic",
+			ExpResp: rolebus.Role{
+				Name:        role.MustParse("MANAGER"),
+				Description: "Managers of a department",
+				Permissions: []string{"reports:read"},
+				Inherits:    []role.Role{role.UserRole},
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nr := rolebus.NewRole{
+					Name:        role.MustParse("MANAGER"),
+					Description: "Managers of a department",
+					Permissions: []string{"reports:read"},
+					Inherits:    []role.Role{role.UserRole},
+				}
+
+				if _, err := busDomain.Role.Create(ctx, nr); err != nil {
+					return err
+				}
+
+				rl, err := busDomain.Role.QueryByName(ctx, nr.Name)
+				if err != nil {
+					return err
+				}
+
+				return rl
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(rolebus.Role)
+				if !exists {
+					return fmt.Sprintf("error occurred: %v", got)
+				}
+
+				expResp := exp.(rolebus.Role)
+
+				expResp.DateCreated = gotResp.DateCreated
+				expResp.DateUpdated = gotResp.DateUpdated
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "unknown-parent",
+			ExpResp: rolebus.ErrUnknownRole,
+			ExcFunc: func(ctx context.Context) any {
+				nr := rolebus.NewRole{
+					Name:     role.MustParse("AUDITOR"),
+					Inherits: []role.Role{role.MustParse("GHOST")},
+				}
+
+				_, err := busDomain.Role.Create(ctx, nr)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func hierarchy(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "cycle",
+			ExpResp: rolebus.ErrCycle,
+			ExcFunc: func(ctx context.Context) any {
+				lead, err := busDomain.Role.Create(ctx, rolebus.NewRole{
+					Name:     role.MustParse("TEAM_LEAD"),
+					Inherits: []role.Role{role.UserRole},
+				})
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Role.Create(ctx, rolebus.NewRole{
+					Name:     role.MustParse("DIRECTOR"),
+					Inherits: []role.Role{lead.Name},
+				}); err != nil {
+					return err
+				}
+
+				ur := rolebus.UpdateRole{
+					Inherits: []role.Role{role.MustParse("DIRECTOR")},
+				}
+
+				_, err = busDomain.Role.Update(ctx, lead, ur)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "check",
+			ExpResp: rolebus.ErrUnknownRole,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Role.Check(ctx, []role.Role{role.AdminRole, role.MustParse("DIRECTOR")}); err != nil {
+					return err
+				}
+
+				return busDomain.Role.Check(ctx, []role.Role{role.MustParse("GHOST")})
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func remove(busDomain dbtest.BusDomain) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "built-in",
+			ExpResp: rolebus.ErrBuiltIn,
+			ExcFunc: func(ctx context.Context) any {
+				rl, err := busDomain.Role.QueryByName(ctx, role.UserRole)
+				if err != nil {
+					return err
+				}
+
+				return busDomain.Role.Delete(ctx, rl)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "in-use",
+			ExpResp: rolebus.ErrInUse,
+			ExcFunc: func(ctx context.Context) any {
+				rl, err := busDomain.Role.QueryByName(ctx, role.MustParse("TEAM_LEAD"))
+				if err != nil {
+					return err
+				}
+
+				return busDomain.Role.Delete(ctx, rl)
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	err, ok := got.(error)
+	if !ok || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
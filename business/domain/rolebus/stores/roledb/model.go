@@ -0,0 +1,71 @@
+package roledb
+
+import (
+	"fmt"
+	"service/business/domain/rolebus"
+	"service/business/sdk/sqldb/dbarray"
+	"service/business/types/role"
+	"time"
+)
+
+type dbRole struct {
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Permissions dbarray.String `db:"permissions"`
+	Inherits    dbarray.String `db:"inherits"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toDBRole(bus rolebus.Role) dbRole {
+	permissions := bus.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return dbRole{
+		Name:        bus.Name.String(),
+		Description: bus.Description,
+		Permissions: permissions,
+		Inherits:    role.ParseToString(bus.Inherits),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusRole(db dbRole) (rolebus.Role, error) {
+	name, err := role.Parse(db.Name)
+	if err != nil {
+		return rolebus.Role{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	inherits, err := role.ParseMany(db.Inherits)
+	if err != nil {
+		return rolebus.Role{}, fmt.Errorf("parse inherits: %w", err)
+	}
+
+	bus := rolebus.Role{
+		Name:        name,
+		Description: db.Description,
+		Permissions: db.Permissions,
+		Inherits:    inherits,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusRoles(dbRoles []dbRole) ([]rolebus.Role, error) {
+	bus := make([]rolebus.Role, len(dbRoles))
+
+	for i, db := range dbRoles {
+		var err error
+		bus[i], err = toBusRole(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
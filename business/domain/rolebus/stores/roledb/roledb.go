@@ -0,0 +1,148 @@
+// Package roledb contains role related CRUD functionality.
+package roledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/business/domain/rolebus"
+	"service/business/sdk/sqldb"
+	"service/business/types/role"
+	"service/foundation/logger"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for role database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db sqlx.ExtContext) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (rolebus.Storer, error) {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new role into the database.
+func (s *Store) Create(ctx context.Context, rl rolebus.Role) error {
+	const q = `
+	INSERT INTO roles
+		(name, description, permissions, inherits, date_created, date_updated)
+	VALUES
+		(:name, :description, :permissions, :inherits, :date_created, :date_updated)`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRole(rl)); err != nil {
+		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", rolebus.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a role document in the database.
+func (s *Store) Update(ctx context.Context, rl rolebus.Role) error {
+	const q = `
+	UPDATE
+		roles
+	SET
+		"description" = :description,
+		"permissions" = :permissions,
+		"inherits" = :inherits,
+		"date_updated" = :date_updated
+	WHERE
+		name = :name`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBRole(rl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a role from the database.
+func (s *Store) Delete(ctx context.Context, rl rolebus.Role) error {
+	data := struct {
+		Name string `db:"name"`
+	}{
+		Name: rl.Name.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		roles
+	WHERE
+		name = :name`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryAll retrieves all the roles from the database.
+func (s *Store) QueryAll(ctx context.Context) ([]rolebus.Role, error) {
+	const q = `
+	SELECT
+		name, description, permissions, inherits, date_created, date_updated
+	FROM
+		roles
+	ORDER BY
+		name`
+
+	var dbRoles []dbRole
+	if err := sqldb.QuerySlice(ctx, s.log, s.db, q, &dbRoles); err != nil {
+		return nil, fmt.Errorf("queryslice: %w", err)
+	}
+
+	return toBusRoles(dbRoles)
+}
+
+// QueryByName gets the specified role from the database.
+func (s *Store) QueryByName(ctx context.Context, name role.Role) (rolebus.Role, error) {
+	data := struct {
+		Name string `db:"name"`
+	}{
+		Name: name.String(),
+	}
+
+	const q = `
+	SELECT
+		name, description, permissions, inherits, date_created, date_updated
+	FROM
+		roles
+	WHERE
+		name = :name`
+
+	var dbRl dbRole
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRl); err != nil {
+		if errors.Is(err, sqldb.ErrDBNotFound) {
+			return rolebus.Role{}, fmt.Errorf("db: %w", rolebus.ErrNotFound)
+		}
+		return rolebus.Role{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusRole(dbRl)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"net/mail"
 	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
 	"service/business/sdk/sqldb"
 	"service/foundation/otel"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -41,6 +44,14 @@ func (ext *Extension) Create(ctx context.Context, actorID uuid.UUID, nu userbus.
 	return usr, nil
 }
 
+// Update applies otel to the user update process.
+func (ext *Extension) Update(ctx context.Context, actorID uuid.UUID, usr userbus.User, uu userbus.UpdateUser) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.update")
+	defer span.End()
+
+	return ext.bus.Update(ctx, actorID, usr, uu)
+}
+
 // Delete applies otel to the user deletion process.
 func (ext *Extension) Delete(ctx context.Context, actorID uuid.UUID, usr userbus.User) error {
 	ctx, span := otel.AddSpan(ctx, "business.userbus.delete")
@@ -53,6 +64,86 @@ func (ext *Extension) Delete(ctx context.Context, actorID uuid.UUID, usr userbus
 	return nil
 }
 
+// Restore applies otel to the user restore process.
+func (ext *Extension) Restore(ctx context.Context, actorID uuid.UUID, usr userbus.User) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.restore")
+	defer span.End()
+
+	return ext.bus.Restore(ctx, actorID, usr)
+}
+
+// Deactivate applies otel to the user deactivation process.
+func (ext *Extension) Deactivate(ctx context.Context, actorID uuid.UUID, usr userbus.User) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.deactivate")
+	defer span.End()
+
+	return ext.bus.Deactivate(ctx, actorID, usr)
+}
+
+// Activate applies otel to the user activation process.
+func (ext *Extension) Activate(ctx context.Context, actorID uuid.UUID, usr userbus.User) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.activate")
+	defer span.End()
+
+	return ext.bus.Activate(ctx, actorID, usr)
+}
+
+// Purge applies otel to the user purge process.
+func (ext *Extension) Purge(ctx context.Context, beginner sqldb.Beginner, deletedBefore time.Time) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.purge")
+	defer span.End()
+
+	return ext.bus.Purge(ctx, beginner, deletedBefore)
+}
+
+// Erase applies otel to the user erasure process.
+func (ext *Extension) Erase(ctx context.Context, actorID uuid.UUID, usr userbus.User) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.erase")
+	defer span.End()
+
+	return ext.bus.Erase(ctx, actorID, usr)
+}
+
+// Query applies otel to the user query process.
+func (ext *Extension) Query(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, page page.Page) ([]userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.query")
+	defer span.End()
+
+	return ext.bus.Query(ctx, filter, orderBy, page)
+}
+
+// Count applies otel to the user count process.
+func (ext *Extension) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.count")
+	defer span.End()
+
+	return ext.bus.Count(ctx, filter)
+}
+
+// Search applies otel to the user search process.
+func (ext *Extension) Search(ctx context.Context, query string, page page.Page) ([]userbus.SearchHit, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.search")
+	defer span.End()
+
+	return ext.bus.Search(ctx, query, page)
+}
+
+// SearchCount applies otel to the user search count process.
+func (ext *Extension) SearchCount(ctx context.Context, query string) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.searchcount")
+	defer span.End()
+
+	return ext.bus.SearchCount(ctx, query)
+}
+
+// QueryByID applies otel to the user query by id process.
+func (ext *Extension) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.userbus.querybyid")
+	defer span.End()
+
+	return ext.bus.QueryByID(ctx, userID)
+}
+
 // QueryByEmail applies otel to the user query by email process.
 func (ext *Extension) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
 	ctx, span := otel.AddSpan(ctx, "business.userbus.querybyemail")
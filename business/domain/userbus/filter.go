@@ -13,10 +13,14 @@ import (
 // We are using pointer semantics because the With API mutates the value.
 type QueryFilter struct {
 	ID               *uuid.UUID
+	OrgID            *uuid.UUID
 	Name             *string `validate:"omitempty,min=3"`
 	Email            *mail.Address
+	Emails           []mail.Address
 	StartCreatedDate *time.Time
 	EndCreatedDate   *time.Time
+	EndDeletedDate   *time.Time
+	IncludeDeleted   *bool
 }
 
 // Validate can perform a check of the data against the validate tags.
@@ -28,6 +32,12 @@ func (qf *QueryFilter) Validate() error {
 	return nil
 }
 
+// WithOrgID sets the OrgID field of the QueryFilter value. When the
+// context carries a tenant, the store always scopes to that tenant instead.
+func (qf *QueryFilter) WithOrgID(orgID uuid.UUID) {
+	qf.OrgID = &orgID
+}
+
 // WithUserID sets the ID field of the QueryFilter value.
 func (qf *QueryFilter) WithUserID(userID uuid.UUID) {
 	qf.ID = &userID
@@ -43,6 +53,12 @@ func (qf *QueryFilter) WithEmail(email mail.Address) {
 	qf.Email = &email
 }
 
+// WithEmails sets the Emails field of the QueryFilter value so a user with
+// any of the emails matches.
+func (qf *QueryFilter) WithEmails(emails []mail.Address) {
+	qf.Emails = emails
+}
+
 // WithStartDateCreated sets the StartCreatedDate field of the QueryFilter value.
 func (qf *QueryFilter) WithStartDateCreated(startDate time.Time) {
 	d := startDate.UTC()
@@ -54,3 +70,15 @@ func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
 	d := endDate.UTC()
 	qf.EndCreatedDate = &d
 }
+
+// WithEndDeletedDate sets the EndDeletedDate field of the QueryFilter value.
+// Only soft deleted users that were deleted on or before this date match.
+func (qf *QueryFilter) WithEndDeletedDate(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndDeletedDate = &d
+}
+
+// WithIncludeDeleted sets the IncludeDeleted field of the QueryFilter value.
+func (qf *QueryFilter) WithIncludeDeleted(include bool) {
+	qf.IncludeDeleted = &include
+}
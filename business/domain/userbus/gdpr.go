@@ -0,0 +1,88 @@
+package userbus
+
+import (
+	"context"
+	"fmt"
+	"service/business/sdk/gdpr"
+	"service/business/sdk/page"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exportUser represents the user data included in a data subject export.
+type exportUser struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Roles       []string  `json:"roles"`
+	Department  string    `json:"department"`
+	Enabled     bool      `json:"enabled"`
+	DateCreated time.Time `json:"dateCreated"`
+	DateUpdated time.Time `json:"dateUpdated"`
+	DateDeleted time.Time `json:"dateDeleted,omitzero"`
+}
+
+// RegisterGDPR registers the user domain as a contributor to data subject
+// export and erasure requests.
+func RegisterGDPR(registry *gdpr.Registry, bus ExtBusiness) {
+	registry.Register(DomainName, gdpr.Contributor{
+		Export: func(ctx context.Context, userID uuid.UUID) (any, error) {
+			usr, exists, err := queryIncludeDeleted(ctx, bus, userID)
+			if err != nil || !exists {
+				return nil, err
+			}
+
+			roles := make([]string, len(usr.Roles))
+			for i, role := range usr.Roles {
+				roles[i] = role.String()
+			}
+
+			exp := exportUser{
+				ID:          usr.ID,
+				Name:        usr.Name.String(),
+				Email:       usr.Email.Address,
+				Roles:       roles,
+				Department:  usr.Department,
+				Enabled:     usr.Enabled,
+				DateCreated: usr.DateCreated,
+				DateUpdated: usr.DateUpdated,
+				DateDeleted: usr.DateDeleted,
+			}
+
+			return exp, nil
+		},
+
+		Erase: func(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error {
+			usr, exists, err := queryIncludeDeleted(ctx, bus, userID)
+			if err != nil || !exists {
+				return err
+			}
+
+			if _, err := bus.Erase(ctx, actorID, usr); err != nil {
+				return fmt.Errorf("erase: userID[%s]: %w", userID, err)
+			}
+
+			return nil
+		},
+	})
+}
+
+// queryIncludeDeleted finds the user by the specified ID even if the user
+// has been soft deleted.
+func queryIncludeDeleted(ctx context.Context, bus ExtBusiness, userID uuid.UUID) (User, bool, error) {
+	var filter QueryFilter
+	filter.WithUserID(userID)
+	filter.WithIncludeDeleted(true)
+
+	usrs, err := bus.Query(ctx, filter, DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		return User{}, false, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	if len(usrs) == 0 {
+		return User{}, false, nil
+	}
+
+	return usrs[0], true, nil
+}
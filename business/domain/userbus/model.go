@@ -12,6 +12,7 @@ import (
 
 type User struct {
 	ID           uuid.UUID
+	OrgID        uuid.UUID
 	Name         name.Name
 	Email        mail.Address
 	Roles        []role.Role
@@ -20,10 +21,18 @@ type User struct {
 	Enabled      bool
 	DateCreated  time.Time
 	DateUpdated  time.Time
+	DateDeleted  time.Time
+	Version      int
+}
+
+// Deleted reports whether the user has been soft deleted.
+func (u User) Deleted() bool {
+	return !u.DateDeleted.IsZero()
 }
 
 // NewUser contains information needed to create a new user.
 type NewUser struct {
+	OrgID      uuid.UUID
 	Name       name.Name
 	Email      mail.Address
 	Roles      []role.Role
@@ -40,3 +49,18 @@ type UpdateUser struct {
 	Password   *string
 	Enabled    *bool
 }
+
+// SearchHit represents a user matching a search along with how well it
+// matched.
+type SearchHit struct {
+	User       User
+	Rank       float64
+	Highlights Highlights
+}
+
+// Highlights holds the searched fields with the matching terms marked.
+type Highlights struct {
+	Name       string
+	Email      string
+	Department string
+}
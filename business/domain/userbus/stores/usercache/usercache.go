@@ -2,12 +2,18 @@ package usercache
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/mail"
 	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
 	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
 	"service/foundation/logger"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/viccon/sturdyc"
 )
 
@@ -59,7 +65,27 @@ func (s *Store) Create(ctx context.Context, usr userbus.User) error {
 	return nil
 }
 
-// Delete removes a user from the database.
+// Update replaces a user document in the database.
+func (s *Store) Update(ctx context.Context, usr userbus.User) error {
+	if err := s.storer.Update(ctx, usr); err != nil {
+		// The cached copy may be the stale version that caused the conflict.
+		if errors.Is(err, userbus.ErrVersionConflict) {
+			s.deleteCache(usr)
+		}
+		return err
+	}
+
+	if usr.Deleted() {
+		s.deleteCache(usr)
+		return nil
+	}
+
+	s.writeCache(usr)
+
+	return nil
+}
+
+// Delete marks a user as deleted in the database.
 func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 	if err := s.storer.Delete(ctx, usr); err != nil {
 		return err
@@ -70,13 +96,76 @@ func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 	return nil
 }
 
-// QueryByEmail gets the specified user from the database by email.
-func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
-	cachedUsr, ok := s.readCache(email.Address)
+// Purge permanently removes a user from the database.
+func (s *Store) Purge(ctx context.Context, usr userbus.User) error {
+	if err := s.storer.Purge(ctx, usr); err != nil {
+		return err
+	}
+
+	s.deleteCache(usr)
+
+	return nil
+}
+
+// Query retrieves a list of existing users from the database.
+func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, page page.Page) ([]userbus.User, error) {
+	return s.storer.Query(ctx, filter, orderBy, page)
+}
+
+// Count returns the total number of users in the DB.
+func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	return s.storer.Count(ctx, filter)
+}
+
+// Search finds the users matching the query in the database.
+func (s *Store) Search(ctx context.Context, query string, page page.Page) ([]userbus.SearchHit, error) {
+	return s.storer.Search(ctx, query, page)
+}
+
+// SearchCount returns the total number of users matching the query in the DB.
+func (s *Store) SearchCount(ctx context.Context, query string) (int, error) {
+	return s.storer.SearchCount(ctx, query)
+}
+
+// QueryByID gets the specified user from the database. The cache is shared
+// by all tenants, so a cached user is only returned to its own tenant.
+func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
+	cachedUsr, ok := s.readCache(userID.String())
 	if ok {
+		if err := tenant.Check(ctx, cachedUsr.OrgID); err != nil {
+			if errors.Is(err, tenant.ErrCrossTenant) {
+				return userbus.User{}, fmt.Errorf("check: %w", userbus.ErrNotFound)
+			}
+			return userbus.User{}, fmt.Errorf("check: %w", err)
+		}
 		return cachedUsr, nil
 	}
 
+	usr, err := s.storer.QueryByID(ctx, userID)
+	if err != nil {
+		return userbus.User{}, err
+	}
+
+	s.writeCache(usr)
+
+	return usr, nil
+}
+
+// QueryByEmail gets the specified user from the database by email. Emails
+// are only unique within an org, so the cache is keyed by the tenant and a
+// system call always goes to the database.
+func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return userbus.User{}, fmt.Errorf("scope: %w", err)
+	}
+
+	if scoped {
+		if cachedUsr, ok := s.readCache(emailKey(orgID, email)); ok {
+			return cachedUsr, nil
+		}
+	}
+
 	usr, err := s.storer.QueryByEmail(ctx, email)
 	if err != nil {
 		return userbus.User{}, err
@@ -100,11 +189,16 @@ func (s *Store) readCache(key string) (userbus.User, bool) {
 // writeCache performs a safe write to the cache for the specified userbus.
 func (s *Store) writeCache(bus userbus.User) {
 	s.cache.Set(bus.ID.String(), bus)
-	s.cache.Set(bus.Email.Address, bus)
+	s.cache.Set(emailKey(bus.OrgID, bus.Email), bus)
 }
 
 // deleteCache performs a safe removal from the cache for the specified userbus.
 func (s *Store) deleteCache(bus userbus.User) {
 	s.cache.Delete(bus.ID.String())
-	s.cache.Delete(bus.Email.Address)
+	s.cache.Delete(emailKey(bus.OrgID, bus.Email))
+}
+
+// emailKey returns the cache key of the user with the email in the org.
+func emailKey(orgID uuid.UUID, email mail.Address) string {
+	return orgID.String() + ":" + email.Address
 }
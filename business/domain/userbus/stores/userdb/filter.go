@@ -0,0 +1,111 @@
+package userdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"service/business/domain/userbus"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/name"
+
+	"github.com/google/uuid"
+)
+
+// scopeFilter restricts the filter to the tenant in the context. Any
+// organization requested by the caller is replaced so a tenant can never
+// read the users of another.
+func scopeFilter(ctx context.Context, filter userbus.QueryFilter) (userbus.QueryFilter, error) {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return userbus.QueryFilter{}, err
+	}
+
+	if scoped {
+		filter.OrgID = &orgID
+	}
+
+	return filter, nil
+}
+
+// applyTenant adds the condition restricting a statement that already has
+// a WHERE clause to the tenant in the context.
+func applyTenant(ctx context.Context, data map[string]any, buf *bytes.Buffer) error {
+	orgID, scoped, err := tenant.Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	if scoped {
+		data["org_id"] = orgID
+		buf.WriteString(" AND org_id = :org_id")
+	}
+
+	return nil
+}
+
+// checkTenant validates the user belongs to the tenant in the context. A
+// user of another tenant is reported as not found so its existence isn't
+// revealed.
+func checkTenant(ctx context.Context, orgID uuid.UUID) error {
+	if err := tenant.Check(ctx, orgID); err != nil {
+		if errors.Is(err, tenant.ErrCrossTenant) {
+			return userbus.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func applyFilter(filter userbus.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	newFilter(filter, data).Apply(buf)
+}
+
+// applySearch adds the condition matching users against a search to the
+// conditions of the filter, for a statement that has a tsq query in scope.
+// Names are matched through their case folded key. The trigram operators
+// are qualified since tenant schemas don't include public in the search
+// path.
+func applySearch(filter userbus.QueryFilter, query string, data map[string]any, buf *bytes.Buffer) {
+	f := newFilter(filter, data)
+
+	data["query"] = query
+	data["query_key"] = name.Fold(query)
+	f.Cond(`(search @@ tsq OR name_key OPERATOR(public.%) :query_key OR :query OPERATOR(public.<%) email OR department OPERATOR(public.%) :query)`)
+
+	f.Apply(buf)
+}
+
+func newFilter(filter userbus.QueryFilter, data map[string]any) *sqldb.Filter {
+	f := sqldb.NewFilter(data)
+
+	f.Eq("user_id", filter.ID)
+	f.Eq("org_id", filter.OrgID)
+
+	if filter.Name != nil {
+		f.Like("name_key", name.Fold(*filter.Name))
+	}
+
+	if filter.Email != nil {
+		f.Eq("email", filter.Email.Address)
+	}
+
+	if filter.Emails != nil {
+		emails := make([]string, len(filter.Emails))
+		for i, email := range filter.Emails {
+			emails[i] = email.Address
+		}
+		f.In("email", emails)
+	}
+
+	f.Range("date_created", filter.StartCreatedDate, filter.EndCreatedDate)
+	f.Range("date_deleted", nil, filter.EndDeletedDate)
+
+	// Soft deleted users are hidden unless explicitly requested.
+	if filter.IncludeDeleted == nil || !*filter.IncludeDeleted {
+		f.Cond("date_deleted IS NULL")
+	}
+
+	return f
+}
@@ -15,14 +15,18 @@ import (
 
 type user struct {
 	ID           uuid.UUID      `db:"user_id"`
+	OrgID        uuid.UUID      `db:"org_id"`
 	Name         string         `db:"name"`
-	Email        string         `db:"email"`
+	NameKey      string         `db:"name_key"`
+	Email        string         `db:"email" log:"redact"`
 	Roles        dbarray.String `db:"roles"`
-	PasswordHash []byte         `db:"password_hash"`
+	PasswordHash []byte         `db:"password_hash" log:"redact"`
 	Department   sql.NullString `db:"department"`
 	Enabled      bool           `db:"enabled"`
 	DateCreated  time.Time      `db:"date_created"`
 	DateUpdated  time.Time      `db:"date_updated"`
+	DateDeleted  sql.NullTime   `db:"date_deleted"`
+	Version      int            `db:"version"`
 }
 
 func toDBUser(usr userbus.User) user {
@@ -33,7 +37,9 @@ func toDBUser(usr userbus.User) user {
 
 	return user{
 		ID:           usr.ID,
+		OrgID:        usr.OrgID,
 		Name:         usr.Name.String(),
+		NameKey:      usr.Name.Key(),
 		Email:        usr.Email.Address,
 		Roles:        roles,
 		PasswordHash: usr.PasswordHash,
@@ -44,6 +50,11 @@ func toDBUser(usr userbus.User) user {
 		Enabled:     usr.Enabled,
 		DateCreated: usr.DateCreated.UTC(),
 		DateUpdated: usr.DateUpdated.UTC(),
+		DateDeleted: sql.NullTime{
+			Time:  usr.DateDeleted.UTC(),
+			Valid: usr.Deleted(),
+		},
+		Version: usr.Version,
 	}
 }
 
@@ -68,6 +79,7 @@ func toBusUser(dbUsr user) (userbus.User, error) {
 
 	bus := userbus.User{
 		ID:           dbUsr.ID,
+		OrgID:        dbUsr.OrgID,
 		Name:         nme,
 		Email:        addr,
 		Roles:        roles,
@@ -76,6 +88,11 @@ func toBusUser(dbUsr user) (userbus.User, error) {
 		Department:   dbUsr.Department.String,
 		DateCreated:  dbUsr.DateCreated.In(time.Local),
 		DateUpdated:  dbUsr.DateUpdated.In(time.Local),
+		Version:      dbUsr.Version,
+	}
+
+	if dbUsr.DateDeleted.Valid {
+		bus.DateDeleted = dbUsr.DateDeleted.Time.In(time.Local)
 	}
 
 	return bus, nil
@@ -94,3 +111,36 @@ func toBusUsers(dbUsers []user) ([]userbus.User, error) {
 
 	return bus, nil
 }
+
+// =============================================================================
+
+type searchHit struct {
+	user
+	Rank                float64        `db:"rank"`
+	HighlightName       string         `db:"highlight_name"`
+	HighlightEmail      string         `db:"highlight_email"`
+	HighlightDepartment sql.NullString `db:"highlight_department"`
+}
+
+func toBusSearchHits(dbHits []searchHit) ([]userbus.SearchHit, error) {
+	bus := make([]userbus.SearchHit, len(dbHits))
+
+	for i, dbHit := range dbHits {
+		usr, err := toBusUser(dbHit.user)
+		if err != nil {
+			return nil, err
+		}
+
+		bus[i] = userbus.SearchHit{
+			User: usr,
+			Rank: dbHit.Rank,
+			Highlights: userbus.Highlights{
+				Name:       dbHit.HighlightName,
+				Email:      dbHit.HighlightEmail,
+				Department: dbHit.HighlightDepartment.String,
+			},
+		}
+	}
+
+	return bus, nil
+}
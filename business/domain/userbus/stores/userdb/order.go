@@ -0,0 +1,25 @@
+package userdb
+
+import (
+	"fmt"
+	"service/business/domain/userbus"
+	"service/business/sdk/order"
+)
+
+var orderByFields = map[string]string{
+	userbus.OrderByID:          "user_id",
+	userbus.OrderByName:        "name",
+	userbus.OrderByEmail:       "email",
+	userbus.OrderByRoles:       "roles",
+	userbus.OrderByEnabled:     "enabled",
+	userbus.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
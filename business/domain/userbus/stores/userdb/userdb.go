@@ -1,14 +1,19 @@
 package userdb
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net/mail"
 	"service/business/domain/userbus"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
 	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
 	"service/foundation/logger"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -19,7 +24,7 @@ type Store struct {
 }
 
 // NewStore constructs the api for data access.
-func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+func NewStore(log *logger.Logger, db sqlx.ExtContext) *Store {
 	return &Store{
 		log: log,
 		db:  db,
@@ -42,11 +47,17 @@ func (s *Store) NewWithTx(tx sqldb.CommitRollbacker) (userbus.Storer, error) {
 	return &store, nil
 }
 
+// Create inserts a new user into the database. The user must belong to the
+// tenant in the context unless the call is a system one.
 func (s *Store) Create(ctx context.Context, usr userbus.User) error {
+	if err := tenant.Check(ctx, usr.OrgID); err != nil {
+		return fmt.Errorf("check: orgID[%s]: %w", usr.OrgID, err)
+	}
+
 	const q = `INSERT INTO users
-		(user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated)
+		(user_id, org_id, name, name_key, email, password_hash, roles, department, enabled, date_created, date_updated, version)
 	VALUES
-		(:user_id, :name, :email, :password_hash, :roles, :department, :enabled, :date_created, :date_updated)`
+		(:user_id, :org_id, :name, :name_key, :email, :password_hash, :roles, :department, :enabled, :date_created, :date_updated, :version)`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
 		if errors.Is(err, sqldb.ErrDBDuplicatedEntry) {
@@ -58,13 +69,85 @@ func (s *Store) Create(ctx context.Context, usr userbus.User) error {
 
 }
 
-// Delete removes a user from the database.
+// Update replaces a user document in the database. The usr value carries
+// the new version and the row is only written if the stored version is the
+// one before it, otherwise ErrVersionConflict is returned.
+func (s *Store) Update(ctx context.Context, usr userbus.User) error {
+	if err := checkTenant(ctx, usr.OrgID); err != nil {
+		return fmt.Errorf("check: %w", err)
+	}
+
+	const q = `
+	UPDATE
+		users
+	SET
+		"name" = :name,
+		"name_key" = :name_key,
+		"email" = :email,
+		"roles" = :roles,
+		"password_hash" = :password_hash,
+		"department" = :department,
+		"enabled" = :enabled,
+		"date_updated" = :date_updated,
+		"date_deleted" = :date_deleted,
+		"version" = :version
+	WHERE
+		user_id = :user_id AND
+		org_id = :org_id AND
+		version = :version - 1
+	RETURNING
+		user_id`
+
+	var updated struct {
+		ID string `db:"user_id"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, toDBUser(usr), &updated); err != nil {
+		switch {
+		case errors.Is(err, sqldb.ErrDBNotFound):
+			return fmt.Errorf("namedquerystruct: %w", userbus.ErrVersionConflict)
+		case errors.Is(err, sqldb.ErrDBDuplicatedEntry):
+			return fmt.Errorf("namedquerystruct: %w", userbus.ErrUniqueEmail)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// Delete marks a user as deleted in the database.
 func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
+	if err := checkTenant(ctx, usr.OrgID); err != nil {
+		return fmt.Errorf("check: %w", err)
+	}
+
+	const q = `
+	UPDATE
+		users
+	SET
+		"date_deleted" = :date_deleted
+	WHERE
+		user_id = :user_id AND
+		org_id = :org_id`
+
+	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Purge permanently removes a user from the database.
+func (s *Store) Purge(ctx context.Context, usr userbus.User) error {
+	if err := checkTenant(ctx, usr.OrgID); err != nil {
+		return fmt.Errorf("check: %w", err)
+	}
+
 	const q = `
 	DELETE FROM
 		users
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND
+		org_id = :org_id`
 
 	if err := sqldb.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -73,24 +156,164 @@ func (s *Store) Delete(ctx context.Context, usr userbus.User) error {
 	return nil
 }
 
-// QueryByEmail gets the specified user from the database by email.
-func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
-	data := struct {
-		Email string `db:"email"`
-	}{
-		Email: email.Address,
+// Query retrieves a list of existing users from the database.
+func (s *Store) Query(ctx context.Context, filter userbus.QueryFilter, orderBy order.By, page page.Page) ([]userbus.User, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		user_id, org_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, date_deleted, version
+	FROM
+		users`
+
+	filter, err := scopeFilter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("scope: %w", err)
+	}
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbUsrs []user
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusUsers(dbUsrs)
+}
+
+// Count returns the total number of users in the DB.
+func (s *Store) Count(ctx context.Context, filter userbus.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		users`
+
+	filter, err := scopeFilter(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("scope: %w", err)
+	}
+
+	buf := bytes.NewBufferString(q)
+	applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// Search retrieves the users matching the query ranked by relevance. Whole
+// words are matched against the search column and misspellings through
+// trigram similarity.
+func (s *Store) Search(ctx context.Context, query string, page page.Page) ([]userbus.SearchHit, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
 	}
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated
+		user_id, org_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, date_deleted, version,
+		ts_rank(search, tsq) + greatest(
+			public.similarity(name_key, :query_key),
+			public.word_similarity(:query, email),
+			public.similarity(coalesce(department, ''), :query)
+		) AS rank,
+		ts_headline('simple', name, tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight_name,
+		ts_headline('simple', email, tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight_email,
+		ts_headline('simple', department, tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight_department
+	FROM
+		users, websearch_to_tsquery('simple', :query) AS tsq`
+
+	filter, err := scopeFilter(ctx, userbus.QueryFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("scope: %w", err)
+	}
+
+	buf := bytes.NewBufferString(q)
+	applySearch(filter, query, data, buf)
+
+	buf.WriteString(" ORDER BY rank DESC, user_id")
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbHits []searchHit
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbHits); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusSearchHits(dbHits)
+}
+
+// SearchCount returns the total number of users matching the query.
+func (s *Store) SearchCount(ctx context.Context, query string) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		users, websearch_to_tsquery('simple', :query) AS tsq`
+
+	filter, err := scopeFilter(ctx, userbus.QueryFilter{})
+	if err != nil {
+		return 0, fmt.Errorf("scope: %w", err)
+	}
+
+	buf := bytes.NewBufferString(q)
+	applySearch(filter, query, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified user from the database. Soft deleted users
+// are not returned.
+func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (userbus.User, error) {
+	data := map[string]any{
+		"user_id": userID.String(),
+	}
+
+	const q = `
+	SELECT
+        user_id, org_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, date_deleted, version
 	FROM
 		users
 	WHERE
-		email = :email`
+		user_id = :user_id AND
+		date_deleted IS NULL`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return userbus.User{}, fmt.Errorf("scope: %w", err)
+	}
 
 	var dbUsr user
-	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsr); err != nil {
+	if err := sqldb.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbUsr); err != nil {
 		if errors.Is(err, sqldb.ErrDBNotFound) {
 			return userbus.User{}, fmt.Errorf("db: %w", userbus.ErrNotFound)
 		}
@@ -99,3 +322,42 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.U
 
 	return toBusUser(dbUsr)
 }
+
+// QueryByEmail gets the specified user from the database by email. Soft
+// deleted users are not returned. Emails are only unique within an org, so
+// an unscoped call matching users of several orgs returns ErrAmbiguousEmail.
+func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (userbus.User, error) {
+	data := map[string]any{
+		"email": email.Address,
+	}
+
+	const q = `
+	SELECT
+        user_id, org_id, name, email, password_hash, roles, department, enabled, date_created, date_updated, date_deleted, version
+	FROM
+		users
+	WHERE
+		email = :email AND
+		date_deleted IS NULL`
+
+	buf := bytes.NewBufferString(q)
+	if err := applyTenant(ctx, data, buf); err != nil {
+		return userbus.User{}, fmt.Errorf("scope: %w", err)
+	}
+
+	buf.WriteString(" LIMIT 2")
+
+	var dbUsrs []user
+	if err := sqldb.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
+		return userbus.User{}, fmt.Errorf("db: %w", err)
+	}
+
+	switch len(dbUsrs) {
+	case 0:
+		return userbus.User{}, fmt.Errorf("db: %w", userbus.ErrNotFound)
+	case 1:
+		return toBusUser(dbUsrs[0])
+	}
+
+	return userbus.User{}, fmt.Errorf("db: %w", userbus.ErrAmbiguousEmail)
+}
@@ -2,11 +2,17 @@ package userbus
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/mail"
 	"service/business/sdk/delegate"
+	"service/business/sdk/order"
+	"service/business/sdk/page"
 	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/types/name"
 	"service/foundation/logger"
 
 	"time"
@@ -18,17 +24,27 @@ import (
 var (
 	ErrNotFound              = errors.New("user not found")
 	ErrUniqueEmail           = errors.New("email is not unique")
+	ErrAmbiguousEmail        = errors.New("email belongs to users of more than one organization")
 	ErrAuthenticationFailure = errors.New("authenticaton failed")
+	ErrDeleted               = errors.New("user is deleted")
+	ErrNotDeleted            = errors.New("user is not deleted")
+	ErrVersionConflict       = errors.New("user has been modified by another request")
 )
 
+// erasedName replaces the name of a user whose data has been erased.
+var erasedName = name.MustParse("Erased User")
+
 type Storer interface {
 	NewWithTx(tx sqldb.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, usr User) error
-	// Update(ctx context.Context, usr User) error
+	Update(ctx context.Context, usr User) error
 	Delete(ctx context.Context, usr User) error
-	// Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]User, error)
-	// Count(ctx context.Context, filter QueryFilter) (int, error)
-	// QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
+	Purge(ctx context.Context, usr User) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]User, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	Search(ctx context.Context, query string, page page.Page) ([]SearchHit, error)
+	SearchCount(ctx context.Context, query string) (int, error)
+	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
 	// QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]User, error)
 	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
 }
@@ -38,11 +54,18 @@ type Storer interface {
 type ExtBusiness interface {
 	NewWithTx(tx sqldb.CommitRollbacker) (ExtBusiness, error)
 	Create(ctx context.Context, actorID uuid.UUID, nu NewUser) (User, error)
-	// Update(ctx context.Context, actorID uuid.UUID, usr User, uu UpdateUser) (User, error)
+	Update(ctx context.Context, actorID uuid.UUID, usr User, uu UpdateUser) (User, error)
 	Delete(ctx context.Context, actorID uuid.UUID, usr User) error
-	// Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]User, error)
-	// Count(ctx context.Context, filter QueryFilter) (int, error)
-	// QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
+	Restore(ctx context.Context, actorID uuid.UUID, usr User) (User, error)
+	Deactivate(ctx context.Context, actorID uuid.UUID, usr User) (User, error)
+	Activate(ctx context.Context, actorID uuid.UUID, usr User) (User, error)
+	Purge(ctx context.Context, beginner sqldb.Beginner, deletedBefore time.Time) (int, error)
+	Erase(ctx context.Context, actorID uuid.UUID, usr User) (User, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]User, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	Search(ctx context.Context, query string, page page.Page) ([]SearchHit, error)
+	SearchCount(ctx context.Context, query string) (int, error)
+	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
 	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
 	Authenticate(ctx context.Context, email mail.Address, password string) (User, error)
 }
@@ -97,10 +120,21 @@ func (b *Business) Create(ctx context.Context, actorID uuid.UUID, nu NewUser) (U
 		return User{}, fmt.Errorf("generatefrompassword: %w", err)
 	}
 
+	// A user created on behalf of a tenant belongs to it unless told
+	// otherwise, and the store rejects an organization that differs.
+	orgID := nu.OrgID
+	if orgID == uuid.Nil {
+		orgID = tenant.DefaultOrgID
+		if v, ok := tenant.Get(ctx); ok {
+			orgID = v
+		}
+	}
+
 	now := time.Now()
 
 	usr := User{
 		ID:           uuid.New(),
+		OrgID:        orgID,
 		Name:         nu.Name,
 		Email:        nu.Email,
 		PasswordHash: hash,
@@ -109,6 +143,7 @@ func (b *Business) Create(ctx context.Context, actorID uuid.UUID, nu NewUser) (U
 		Enabled:      true,
 		DateCreated:  now,
 		DateUpdated:  now,
+		Version:      1,
 	}
 
 	if err := b.storer.Create(ctx, usr); err != nil {
@@ -118,22 +153,220 @@ func (b *Business) Create(ctx context.Context, actorID uuid.UUID, nu NewUser) (U
 	return usr, nil
 }
 
-// Delete removes the specified user.
+// Update modifies information about a user. The update only succeeds if the
+// user has not changed since the specified user value was read, otherwise
+// ErrVersionConflict is returned.
+func (b *Business) Update(ctx context.Context, actorID uuid.UUID, usr User, uu UpdateUser) (User, error) {
+	if uu.Name != nil {
+		usr.Name = *uu.Name
+	}
+
+	if uu.Email != nil {
+		usr.Email = *uu.Email
+	}
+
+	if uu.Roles != nil {
+		usr.Roles = uu.Roles
+	}
+
+	if uu.Password != nil {
+		pw, err := bcrypt.GenerateFromPassword([]byte(*uu.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return User{}, fmt.Errorf("generatefrompassword: %w", err)
+		}
+		usr.PasswordHash = pw
+	}
+
+	if uu.Department != nil {
+		usr.Department = *uu.Department
+	}
+
+	if uu.Enabled != nil {
+		usr.Enabled = *uu.Enabled
+	}
+
+	usr.DateUpdated = time.Now()
+
+	return b.update(ctx, usr)
+}
+
+// Delete soft deletes the specified user. The user is hidden from all
+// queries until restored or permanently removed by Purge.
 func (b *Business) Delete(ctx context.Context, actorID uuid.UUID, usr User) error {
+	if usr.Deleted() {
+		return ErrDeleted
+	}
+
+	usr.DateDeleted = time.Now()
+
 	if err := b.storer.Delete(ctx, usr); err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	return nil
+}
+
+// Restore brings back a soft deleted user.
+func (b *Business) Restore(ctx context.Context, actorID uuid.UUID, usr User) (User, error) {
+	if !usr.Deleted() {
+		return User{}, ErrNotDeleted
+	}
+
+	usr.DateDeleted = time.Time{}
+	usr.DateUpdated = time.Now()
+
+	return b.update(ctx, usr)
+}
+
+// Deactivate disables the specified user so they can no longer authenticate.
+func (b *Business) Deactivate(ctx context.Context, actorID uuid.UUID, usr User) (User, error) {
+	return b.setEnabled(ctx, usr, false)
+}
+
+// Activate enables the specified user so they can authenticate again.
+func (b *Business) Activate(ctx context.Context, actorID uuid.UUID, usr User) (User, error) {
+	return b.setEnabled(ctx, usr, true)
+}
+
+// Purge permanently removes the users that were soft deleted before the
+// specified time and returns the number of users removed. Each user is
+// removed in a transaction of its own that the domains reacting to the
+// deleted action take part in, so the user and the data referencing it
+// are removed together or not at all.
+func (b *Business) Purge(ctx context.Context, beginner sqldb.Beginner, deletedBefore time.Time) (int, error) {
+	var filter QueryFilter
+	filter.WithIncludeDeleted(true)
+	filter.WithEndDeletedDate(deletedBefore)
+
+	pg := page.MustParse("1", "100")
+
+	var purged int
+	for {
+		usrs, err := b.storer.Query(ctx, filter, DefaultOrderBy, pg)
+		if err != nil {
+			return purged, fmt.Errorf("query: %w", err)
+		}
+
+		if len(usrs) == 0 {
+			return purged, nil
+		}
+
+		for _, usr := range usrs {
+			if err := b.purge(ctx, beginner, usr); err != nil {
+				return purged, fmt.Errorf("purge: userID[%s]: %w", usr.ID, err)
+			}
+
+			purged++
+		}
+	}
+}
+
+func (b *Business) purge(ctx context.Context, beginner sqldb.Beginner, usr User) error {
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return err
+	}
+
+	ctx = sqldb.WithTx(ctx, tx)
+
 	// Other domains may need to know when a user is deleted so business
 	// logic can be applied. This represents a delegate call to other domains.
+	// It happens first so data referencing the user can be removed.
 	if err := b.delegate.Call(ctx, ActionDeletedData(usr.ID)); err != nil {
 		return fmt.Errorf("failed to execute `%s` action: %w", ActionDeleted, err)
 	}
 
+	if err := storer.Purge(ctx, usr); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
 	return nil
 }
 
-// QueryByEmail finds the user by a specified user email.
+// Erase pseudonymizes the personal data held for the specified user and
+// disables and soft deletes the account so Purge removes it later.
+func (b *Business) Erase(ctx context.Context, actorID uuid.UUID, usr User) (User, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return User{}, fmt.Errorf("random: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, fmt.Errorf("generatefrompassword: %w", err)
+	}
+
+	now := time.Now()
+
+	usr.Name = erasedName
+	usr.Email = mail.Address{Address: fmt.Sprintf("erased-%s@erased.invalid", usr.ID)}
+	usr.PasswordHash = hash
+	usr.Department = ""
+	usr.Enabled = false
+	usr.DateUpdated = now
+
+	if !usr.Deleted() {
+		usr.DateDeleted = now
+	}
+
+	return b.update(ctx, usr)
+}
+
+// Query retrieves a list of existing users.
+func (b *Business) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]User, error) {
+	users, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return users, nil
+}
+
+// Count returns the total number of users.
+func (b *Business) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return b.storer.Count(ctx, filter)
+}
+
+// Search finds the users whose name, email or department match the query,
+// best matches first. Words are matched as full text and misspelled words
+// are matched by similarity.
+func (b *Business) Search(ctx context.Context, query string, page page.Page) ([]SearchHit, error) {
+	hits, err := b.storer.Search(ctx, query, page)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	return hits, nil
+}
+
+// SearchCount returns the total number of users matching the query.
+func (b *Business) SearchCount(ctx context.Context, query string) (int, error) {
+	return b.storer.SearchCount(ctx, query)
+}
+
+// QueryByID finds the user by the specified ID.
+func (b *Business) QueryByID(ctx context.Context, userID uuid.UUID) (User, error) {
+	user, err := b.storer.QueryByID(ctx, userID)
+	if err != nil {
+		return User{}, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return user, nil
+}
+
+// QueryByEmail finds the user by a specified user email. Emails are only
+// unique within an organization, so a system call returns ErrAmbiguousEmail
+// when more than one organization has a user with the email.
 func (b *Business) QueryByEmail(ctx context.Context, email mail.Address) (User, error) {
 	user, err := b.storer.QueryByEmail(ctx, email)
 	if err != nil {
@@ -156,5 +389,31 @@ func (b *Business) Authenticate(ctx context.Context, email mail.Address, passwor
 		return User{}, fmt.Errorf("comparehashandpassword: %w", ErrAuthenticationFailure)
 	}
 
+	if !usr.Enabled {
+		return User{}, fmt.Errorf("disabled: %w", ErrAuthenticationFailure)
+	}
+
+	return usr, nil
+}
+
+// =============================================================================
+
+func (b *Business) setEnabled(ctx context.Context, usr User, enabled bool) (User, error) {
+	usr.Enabled = enabled
+	usr.DateUpdated = time.Now()
+
+	return b.update(ctx, usr)
+}
+
+// update stores the user as the next version. The store rejects the write
+// with ErrVersionConflict if the stored version is not the one the user
+// value was read at.
+func (b *Business) update(ctx context.Context, usr User) (User, error) {
+	usr.Version++
+
+	if err := b.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
 	return usr, nil
 }
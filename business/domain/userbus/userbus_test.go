@@ -2,13 +2,21 @@ package userbus_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/mail"
 	"testing"
+	"time"
 
+	"service/business/domain/orgbus"
 	"service/business/domain/userbus"
 
 	"service/business/sdk/dbtest"
+	"service/business/sdk/delegate"
+	"service/business/sdk/page"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
 	"service/business/sdk/unitest"
 	"service/business/types/name"
 	"service/business/types/role"
@@ -23,21 +31,26 @@ func Test_User(t *testing.T) {
 
 	db := dbtest.New(t, "Test_User")
 
-	// sd, err := insertSeedData(db.BusDomain)
-	// if err != nil {
-	// 	t.Fatalf("Seeding error: %s", err)
-	// }
+	sd, err := insertSeedData(db.BusDomain)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
 
 	// -------------------------------------------------------------------------
 
 	unitest.Run(t, create(db.BusDomain), "create")
-
+	unitest.Run(t, update(db.BusDomain, sd), "update")
+	unitest.Run(t, softDelete(db.BusDomain, sd), "delete")
+	unitest.Run(t, restore(db.BusDomain, sd), "restore")
+	unitest.Run(t, purge(db.BusDomain, sqldb.NewBeginner(db.DB), sd), "purge")
+	unitest.Run(t, crossTenant(db.BusDomain, sd), "crosstenant")
+	unitest.Run(t, search(db.BusDomain), "search")
 }
 
 // =============================================================================
 
 func insertSeedData(busDomain dbtest.BusDomain) (unitest.SeedData, error) {
-	ctx := context.Background()
+	ctx := tenant.System(context.Background())
 
 	usrs, err := userbus.TestSeedUsers(ctx, 2, role.AdminRole, busDomain.User)
 	if err != nil {
@@ -89,6 +102,8 @@ func create(busDomain dbtest.BusDomain) []unitest.Table {
 				Roles:      []role.Role{role.AdminRole},
 				Department: "ITO",
 				Enabled:    true,
+				Version:    1,
+				OrgID:      tenant.DefaultOrgID,
 			},
 			ExcFunc: func(ctx context.Context) any {
 				nu := userbus.NewUser{
@@ -130,3 +145,456 @@ func create(busDomain dbtest.BusDomain) []unitest.Table {
 
 	return table
 }
+
+func update(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "version-conflict",
+			ExpResp: userbus.ErrVersionConflict,
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := busDomain.User.QueryByID(ctx, sd.Admins[0].ID)
+				if err != nil {
+					return err
+				}
+
+				dept := "Sales"
+				updUsr, err := busDomain.User.Update(ctx, sd.Admins[1].ID, usr, userbus.UpdateUser{Department: &dept})
+				if err != nil {
+					return err
+				}
+
+				if updUsr.Version != usr.Version+1 {
+					return fmt.Errorf("expected version %d, got %d", usr.Version+1, updUsr.Version)
+				}
+
+				// A second admin still holding the original value must not
+				// overwrite the first update.
+				dept = "Marketing"
+				_, err = busDomain.User.Update(ctx, sd.Admins[1].ID, usr, userbus.UpdateUser{Department: &dept})
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func softDelete(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "user",
+			ExpResp: sd.Users[0].ID,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, sd.Users[0].User); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.User.QueryByID(ctx, sd.Users[0].ID); !errors.Is(err, userbus.ErrNotFound) {
+					return fmt.Errorf("expected deleted user to be hidden: %w", err)
+				}
+
+				if _, err := busDomain.User.QueryByEmail(ctx, sd.Users[0].Email); !errors.Is(err, userbus.ErrNotFound) {
+					return fmt.Errorf("expected deleted user to be hidden by email: %w", err)
+				}
+
+				var filter userbus.QueryFilter
+				filter.WithUserID(sd.Users[0].ID)
+
+				n, err := busDomain.User.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				if n != 0 {
+					return fmt.Errorf("expected deleted user to be excluded from count, got %d", n)
+				}
+
+				filter.WithIncludeDeleted(true)
+
+				usrs, err := busDomain.User.Query(ctx, filter, userbus.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(usrs) != 1 || !usrs[0].Deleted() {
+					return fmt.Errorf("expected the deleted user when including deleted, got %d", len(usrs))
+				}
+
+				return usrs[0].ID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "email-reuse",
+			ExpResp: userbus.ErrUniqueEmail,
+			ExcFunc: func(ctx context.Context) any {
+				nu := userbus.TestNewUsers(1, role.UserRole)[0]
+
+				usr, err := busDomain.User.Create(ctx, sd.Admins[0].ID, nu)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, usr); err != nil {
+					return err
+				}
+
+				// The email of a deleted user is free for a new one.
+				if _, err := busDomain.User.Create(ctx, sd.Admins[0].ID, nu); err != nil {
+					return fmt.Errorf("create: expected the email of a deleted user to be free: %w", err)
+				}
+
+				var filter userbus.QueryFilter
+				filter.WithUserID(usr.ID)
+				filter.WithIncludeDeleted(true)
+
+				usrs, err := busDomain.User.Query(ctx, filter, userbus.DefaultOrderBy, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				if len(usrs) != 1 {
+					return fmt.Errorf("expected the deleted user, got %d", len(usrs))
+				}
+
+				// The deleted user can't come back while another has its email.
+				_, err = busDomain.User.Restore(ctx, sd.Admins[0].ID, usrs[0])
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func restore(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "user",
+			ExpResp: sd.Users[1].ID,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, sd.Users[1].User); err != nil {
+					return err
+				}
+
+				var filter userbus.QueryFilter
+				filter.WithUserID(sd.Users[1].ID)
+				filter.WithIncludeDeleted(true)
+
+				usrs, err := busDomain.User.Query(ctx, filter, userbus.DefaultOrderBy, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				if len(usrs) != 1 {
+					return fmt.Errorf("expected the deleted user, got %d", len(usrs))
+				}
+
+				if _, err := busDomain.User.Restore(ctx, sd.Admins[0].ID, usrs[0]); err != nil {
+					return err
+				}
+
+				usr, err := busDomain.User.QueryByID(ctx, sd.Users[1].ID)
+				if err != nil {
+					return err
+				}
+
+				if usr.Deleted() {
+					return errors.New("expected the user to be restored")
+				}
+
+				return usr.ID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func purge(busDomain dbtest.BusDomain, bgn sqldb.Beginner, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "retention",
+			ExpResp: []uuid.UUID{sd.Admins[1].ID},
+			ExcFunc: func(ctx context.Context) any {
+				var deleted []uuid.UUID
+				busDomain.Delegate.Register(userbus.DomainName, userbus.ActionDeleted, func(ctx context.Context, data delegate.Data) error {
+					var params userbus.ActionDeletedParms
+					if err := json.Unmarshal(data.RawParams, &params); err != nil {
+						return err
+					}
+					deleted = append(deleted, params.UserID)
+					return nil
+				})
+
+				if err := busDomain.User.Delete(ctx, sd.Admins[0].ID, sd.Admins[1].User); err != nil {
+					return err
+				}
+
+				// Nothing has been deleted long enough to be purged yet.
+				n, err := busDomain.User.Purge(ctx, bgn, time.Now().Add(-time.Hour))
+				if err != nil {
+					return err
+				}
+
+				if n != 0 {
+					return fmt.Errorf("expected nothing to be purged, got %d", n)
+				}
+
+				// Users deleted by earlier tests are purged along with this one.
+				if _, err := busDomain.User.Purge(ctx, bgn, time.Now()); err != nil {
+					return err
+				}
+
+				var filter userbus.QueryFilter
+				filter.WithUserID(sd.Admins[1].ID)
+				filter.WithIncludeDeleted(true)
+
+				n, err = busDomain.User.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				if n != 0 {
+					return fmt.Errorf("expected the user to be removed, got %d", n)
+				}
+
+				for _, id := range deleted {
+					if id == sd.Admins[1].ID {
+						return []uuid.UUID{id}
+					}
+				}
+
+				return deleted
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func crossTenant(busDomain dbtest.BusDomain, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "isolated",
+			ExpResp: userbus.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				org, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: "Other Org"})
+				if err != nil {
+					return err
+				}
+
+				otherCtx := tenant.Set(ctx, org.ID)
+
+				other, err := userbus.TestSeedUsers(otherCtx, 1, role.UserRole, busDomain.User)
+				if err != nil {
+					return err
+				}
+
+				if other[0].OrgID != org.ID {
+					return fmt.Errorf("expected the user to belong to the tenant, got %s", other[0].OrgID)
+				}
+
+				defaultCtx := tenant.Set(ctx, tenant.DefaultOrgID)
+
+				// The user of the other tenant can't be seen by id, email or
+				// any query, even when the filter asks for its organization.
+				if _, err := busDomain.User.QueryByEmail(defaultCtx, other[0].Email); !errors.Is(err, userbus.ErrNotFound) {
+					return fmt.Errorf("query by email: expected not found, got %v", err)
+				}
+
+				var filter userbus.QueryFilter
+				filter.WithOrgID(org.ID)
+				filter.WithIncludeDeleted(true)
+
+				usrs, err := busDomain.User.Query(defaultCtx, filter, userbus.DefaultOrderBy, page.MustParse("1", "100"))
+				if err != nil {
+					return err
+				}
+
+				for _, usr := range usrs {
+					if usr.OrgID != tenant.DefaultOrgID {
+						return fmt.Errorf("query returned user %s of org %s", usr.ID, usr.OrgID)
+					}
+				}
+
+				n, err := busDomain.User.Count(otherCtx, userbus.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				if n != 1 {
+					return fmt.Errorf("expected the other tenant to see 1 user, got %d", n)
+				}
+
+				// Writes are just as isolated.
+				if err := busDomain.User.Delete(defaultCtx, sd.Admins[0].ID, other[0]); !errors.Is(err, userbus.ErrNotFound) {
+					return fmt.Errorf("delete: expected not found, got %v", err)
+				}
+
+				nu := userbus.TestNewUsers(1, role.UserRole)[0]
+				nu.OrgID = org.ID
+
+				if _, err := busDomain.User.Create(defaultCtx, sd.Admins[0].ID, nu); !errors.Is(err, tenant.ErrCrossTenant) {
+					return fmt.Errorf("create: expected cross tenant, got %v", err)
+				}
+
+				// And the other tenant can't see the users of the default one.
+				_, err = busDomain.User.QueryByID(otherCtx, sd.Users[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "email-per-org",
+			ExpResp: userbus.ErrAmbiguousEmail,
+			ExcFunc: func(ctx context.Context) any {
+				org, err := busDomain.Org.Create(ctx, orgbus.NewOrg{Name: "Email Org"})
+				if err != nil {
+					return err
+				}
+
+				otherCtx := tenant.Set(ctx, org.ID)
+
+				// The email of a user of the default org is free in another one.
+				nu := userbus.TestNewUsers(1, role.UserRole)[0]
+				nu.Email = sd.Users[0].Email
+
+				usr, err := busDomain.User.Create(otherCtx, sd.Admins[0].ID, nu)
+				if err != nil {
+					return err
+				}
+
+				got, err := busDomain.User.QueryByEmail(otherCtx, nu.Email)
+				if err != nil {
+					return err
+				}
+
+				if got.ID != usr.ID {
+					return fmt.Errorf("expected user %s of the org, got %s", usr.ID, got.ID)
+				}
+
+				if _, err := busDomain.User.Create(otherCtx, sd.Admins[0].ID, nu); !errors.Is(err, userbus.ErrUniqueEmail) {
+					return fmt.Errorf("create: expected unique email, got %v", err)
+				}
+
+				// Without a tenant the email can't pick a user.
+				_, err = busDomain.User.QueryByEmail(ctx, nu.Email)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "no-tenant",
+			ExpResp: tenant.ErrNoTenant,
+			ExcFunc: func(ctx context.Context) any {
+				// A call with neither a tenant nor the system mark, like a
+				// route missing the tenant middleware, can't read any user.
+				if _, err := busDomain.User.Count(context.Background(), userbus.QueryFilter{}); !errors.Is(err, tenant.ErrNoTenant) {
+					return fmt.Errorf("count: expected no tenant, got %v", err)
+				}
+
+				_, err := busDomain.User.QueryByID(context.Background(), sd.Users[0].ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func search(busDomain dbtest.BusDomain) []unitest.Table {
+	email, _ := mail.ParseAddress("jacqueline@ardanlabs.com")
+
+	table := []unitest.Table{
+		{
+			Name: "fuzzy",
+			ExpResp: userbus.Highlights{
+				Name:       "Jacqueline Bouvier",
+				Email:      "jacqueline@ardanlabs.com",
+				Department: "<mark>Marketing</mark>",
+			},
+			ExcFunc: func(ctx context.Context) any {
+				nu := userbus.NewUser{
+					Name:       name.MustParse("Jacqueline Bouvier"),
+					Email:      *email,
+					Roles:      []role.Role{role.UserRole},
+					Department: "Marketing",
+					Password:   "123",
+				}
+
+				usr, err := busDomain.User.Create(ctx, uuid.UUID{}, nu)
+				if err != nil {
+					return err
+				}
+
+				// The misspelling is only found through trigram similarity.
+				hits, err := busDomain.User.Search(ctx, "jaqueline", page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(hits) == 0 || hits[0].User.ID != usr.ID {
+					return fmt.Errorf("expected %s as the best match, got %d hits", usr.ID, len(hits))
+				}
+
+				hits, err = busDomain.User.Search(ctx, "marketing", page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(hits) != 1 {
+					return fmt.Errorf("expected 1 hit, got %d", len(hits))
+				}
+
+				return hits[0].Highlights
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -1,10 +1,28 @@
 package dbtest
 
 import (
+	"service/business/domain/auditbus"
+	"service/business/domain/auditbus/stores/auditdb"
+	"service/business/domain/gdprbus"
+	"service/business/domain/gdprbus/stores/gdprdb"
+	"service/business/domain/invitebus"
+	"service/business/domain/invitebus/extension/inviteaudit"
+	"service/business/domain/invitebus/stores/invitedb"
+	"service/business/domain/orderbus"
+	"service/business/domain/orderbus/extension/orderaudit"
+	"service/business/domain/orderbus/stores/orderdb"
+	"service/business/domain/orgbus"
+	"service/business/domain/orgbus/stores/orgdb"
+	"service/business/domain/productbus"
+	"service/business/domain/productbus/stores/productdb"
+	"service/business/domain/rolebus"
+	"service/business/domain/rolebus/stores/roledb"
 	"service/business/domain/userbus"
 	"service/business/domain/userbus/stores/userdb"
 	"service/business/sdk/delegate"
+	"service/business/sdk/gdpr"
 	"service/foundation/logger"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -13,16 +31,47 @@ import (
 type BusDomain struct {
 	Delegate *delegate.Delegate
 
-	User userbus.ExtBusiness
+	Audit        *auditbus.Business
+	Org          *orgbus.Business
+	User         userbus.ExtBusiness
+	Role         *rolebus.Business
+	Product      productbus.ExtBusiness
+	Order        orderbus.ExtBusiness
+	Invite       invitebus.ExtBusiness
+	InviteSender *invitebus.TestSender
+	GDPR         *gdprbus.Business
 }
 
 func newBusDomains(log *logger.Logger, db *sqlx.DB) BusDomain {
 
 	delegate := delegate.New(log)
 
+	auditBus := auditbus.NewBusiness(log, auditdb.NewStore(log, db))
+	orgBus := orgbus.NewBusiness(log, orgdb.NewStore(log, db))
 	userBus := userbus.NewBusiness(log, delegate, userdb.NewStore(log, db))
+	roleBus := rolebus.NewBusiness(log, roledb.NewStore(log, db))
+	productBus := productbus.NewBusiness(log, userBus, delegate, productdb.NewStore(log, db))
+	orderBus := orderbus.NewBusiness(log, productBus, delegate, orderdb.NewStore(log, db), orderaudit.NewExtension(auditBus))
+
+	inviteSender := invitebus.TestSender{}
+	inviteBus := invitebus.NewBusiness(log, userBus, &inviteSender, invitedb.NewStore(log, db), time.Hour, inviteaudit.NewExtension(auditBus))
+
+	registry := gdpr.New(log)
+	userbus.RegisterGDPR(registry, userBus)
+	auditbus.RegisterGDPR(registry, auditBus, invitebus.GDPRInvitations(inviteBus, userBus))
+
+	gdprBus := gdprbus.NewBusiness(log, userBus, registry, gdprdb.NewStore(log, db))
 
 	return BusDomain{
-		User: userBus,
+		Delegate:     delegate,
+		Audit:        auditBus,
+		Org:          orgBus,
+		User:         userBus,
+		Role:         roleBus,
+		Product:      productBus,
+		Order:        orderBus,
+		Invite:       inviteBus,
+		InviteSender: &inviteSender,
+		GDPR:         gdprBus,
 	}
 }
@@ -18,6 +18,7 @@ import (
 // Database owns state for running and shutting down tests.
 type Database struct {
 	DB        *sqlx.DB
+	Config    sqldb.Config
 	Log       *logger.Logger
 	BusDomain BusDomain
 }
@@ -71,13 +72,15 @@ func New(t *testing.T, testName string) *Database {
 	}
 	// -------------------------------------------------------------------------
 
-	db, err := sqldb.Open(sqldb.Config{
+	cfg := sqldb.Config{
 		User:       "postgres",
 		Password:   "postgres",
 		Host:       c.HostPort,
 		Name:       dbName,
 		DisableTLS: true,
-	})
+	}
+
+	db, err := sqldb.Open(cfg)
 	if err != nil {
 		t.Fatalf("Opening database connection: %v", err)
 	}
@@ -112,6 +115,7 @@ func New(t *testing.T, testName string) *Database {
 
 	return &Database{
 		DB:        db,
+		Config:    cfg,
 		Log:       log,
 		BusDomain: newBusDomains(log, db),
 	}
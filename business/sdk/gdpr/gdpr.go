@@ -0,0 +1,114 @@
+// Package gdpr provides a registry of the domains that hold data about a
+// user so a data subject request can export or erase it in one place.
+package gdpr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"service/foundation/logger"
+
+	"github.com/google/uuid"
+)
+
+// ExportFunc returns the data a domain holds about the specified user. The
+// value is marshaled to JSON for the export archive.
+type ExportFunc func(ctx context.Context, userID uuid.UUID) (any, error)
+
+// EraseFunc erases or pseudonymizes the data a domain holds about the
+// specified user. It must be safe to call more than once.
+type EraseFunc func(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error
+
+// Contributor represents the functions a domain registers to take part in
+// data subject requests.
+type Contributor struct {
+	Export ExportFunc
+	Erase  EraseFunc
+}
+
+// Section represents the exported data from a single domain.
+type Section struct {
+	Domain string
+	Data   json.RawMessage
+}
+
+// Registry manages the set of contributors called for data subject requests.
+type Registry struct {
+	log          *logger.Logger
+	domains      []string
+	contributors map[string]Contributor
+}
+
+// New constructs a registry for data subject requests.
+func New(log *logger.Logger) *Registry {
+	return &Registry{
+		log:          log,
+		contributors: make(map[string]Contributor),
+	}
+}
+
+// Register adds the contributor for the specified domain. Contributors are
+// called in the order they are registered.
+func (r *Registry) Register(domainType string, c Contributor) {
+	if _, exists := r.contributors[domainType]; !exists {
+		r.domains = append(r.domains, domainType)
+	}
+
+	r.contributors[domainType] = c
+}
+
+// Export calls every registered contributor and returns the data each
+// domain holds about the specified user. Unlike delegate calls, any failure
+// stops the export since a partial export is not acceptable.
+func (r *Registry) Export(ctx context.Context, userID uuid.UUID) ([]Section, error) {
+	sections := make([]Section, 0, len(r.domains))
+
+	for _, domain := range r.domains {
+		c := r.contributors[domain]
+		if c.Export == nil {
+			continue
+		}
+
+		r.log.Info(ctx, "gdpr export", "status", "started", "domain", domain, "userID", userID)
+
+		v, err := c.Export(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("export: domain[%s]: %w", domain, err)
+		}
+
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal: domain[%s]: %w", domain, err)
+		}
+
+		sections = append(sections, Section{
+			Domain: domain,
+			Data:   data,
+		})
+	}
+
+	return sections, nil
+}
+
+// Erase calls every registered contributor to erase the data each domain
+// holds about the specified user. Contributors are called in the reverse
+// order they were registered, so the user domain, registered first, is
+// erased last and the others can still find data through the user's
+// details. Any failure stops the erasure so the request can be retried.
+func (r *Registry) Erase(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error {
+	for i := len(r.domains) - 1; i >= 0; i-- {
+		domain := r.domains[i]
+		c := r.contributors[domain]
+		if c.Erase == nil {
+			continue
+		}
+
+		r.log.Info(ctx, "gdpr erase", "status", "started", "domain", domain, "userID", userID)
+
+		if err := c.Erase(ctx, actorID, userID); err != nil {
+			return fmt.Errorf("erase: domain[%s]: %w", domain, err)
+		}
+	}
+
+	return nil
+}
@@ -7,12 +7,16 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"strings"
 
 	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
 
 	"github.com/ardanlabs/darwin/v3"
 	"github.com/ardanlabs/darwin/v3/dialects/postgres"
 	"github.com/ardanlabs/darwin/v3/drivers/generic"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -24,9 +28,21 @@ var (
 	seedDoc string
 )
 
+// sharedSeeds are the statements of the migrations that insert data owned by
+// the shared schema. They are left out of the migrations of a tenant schema,
+// which only gets its own organization, copied in by MigrateTenant.
+var sharedSeeds = map[float64]string{
+	1.07: `INSERT INTO orgs (org_id, name, date_created, date_updated) VALUES
+	('00000000-0000-0000-0000-000000000001', 'Default', NOW(), NOW());`,
+}
+
 // Migrate attempts to bring the database up to date with the migrations
 // defined in this package.
 func Migrate(ctx context.Context, db *sqlx.DB) error {
+	return migrate(ctx, db, darwin.ParseMigrations(migrateDoc))
+}
+
+func migrate(ctx context.Context, db *sqlx.DB, migrations []darwin.Migration) error {
 	if err := sqldb.StatusCheck(ctx, db); err != nil {
 		return fmt.Errorf("status check database: %w", err)
 	}
@@ -36,10 +52,115 @@ func Migrate(ctx context.Context, db *sqlx.DB) error {
 		return fmt.Errorf("construct darwin driver: %w", err)
 	}
 
-	d := darwin.New(driver, darwin.ParseMigrations(migrateDoc))
+	d := darwin.New(driver, migrations)
 	return d.Migrate()
 }
 
+// tenantMigrations returns the migrations defined in this package without
+// the statements in sharedSeeds.
+func tenantMigrations() ([]darwin.Migration, error) {
+	migrations := darwin.ParseMigrations(migrateDoc)
+
+	for i, mig := range migrations {
+		stmt, exists := sharedSeeds[mig.Version]
+		if !exists {
+			continue
+		}
+
+		if !strings.Contains(mig.Script, stmt) {
+			return nil, fmt.Errorf("version[%v]: shared seed not found", mig.Version)
+		}
+
+		migrations[i].Script = strings.Replace(mig.Script, stmt, "", 1)
+	}
+
+	return migrations, nil
+}
+
+// MigrateTenants brings the schema of every organization up to date with
+// the migrations defined in this package. It is used when the database
+// runs with a schema per tenant, the schema in the config holding the
+// organizations themselves.
+func MigrateTenants(ctx context.Context, cfg sqldb.Config) error {
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	var orgIDs []uuid.UUID
+	if err := db.SelectContext(ctx, &orgIDs, `SELECT org_id FROM orgs ORDER BY date_created`); err != nil {
+		return fmt.Errorf("query orgs: %w", err)
+	}
+
+	for _, orgID := range orgIDs {
+		if err := MigrateTenant(ctx, cfg, orgID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MigrateTenant creates the schema for the specified organization if it
+// doesn't exist and brings it up to date with the migrations defined in
+// this package, leaving out the data owned by the shared schema. The
+// organization is copied into the tenant schema so the users stored there
+// can reference it.
+func MigrateTenant(ctx context.Context, cfg sqldb.Config, orgID uuid.UUID) error {
+	migrations, err := tenantMigrations()
+	if err != nil {
+		return fmt.Errorf("tenant migrations: %w", err)
+	}
+
+	shared := cfg.Schema
+	if shared == "" {
+		shared = "public"
+	}
+
+	schema := tenant.SchemaName(orgID)
+
+	db, err := sqldb.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		return fmt.Errorf("create schema[%s]: %w", schema, err)
+	}
+
+	tenantCfg := cfg
+	tenantCfg.Schema = schema
+
+	tenantDB, err := sqldb.Open(tenantCfg)
+	if err != nil {
+		return fmt.Errorf("connect database: schema[%s]: %w", schema, err)
+	}
+	defer tenantDB.Close()
+
+	if err := migrate(ctx, tenantDB, migrations); err != nil {
+		return fmt.Errorf("migrate: schema[%s]: %w", schema, err)
+	}
+
+	q := fmt.Sprintf(`
+	INSERT INTO %s.orgs
+		(org_id, name, date_created, date_updated)
+	SELECT
+		org_id, name, date_created, date_updated
+	FROM
+		%s.orgs
+	WHERE
+		org_id = $1
+	ON CONFLICT (org_id) DO NOTHING`, pgx.Identifier{schema}.Sanitize(), pgx.Identifier{shared}.Sanitize())
+
+	if _, err := db.ExecContext(ctx, q, orgID); err != nil {
+		return fmt.Errorf("copy org: schema[%s]: %w", schema, err)
+	}
+
+	return nil
+}
+
 // Seed runs the seed document defined in this package against db. The queries
 // are run in a transaction and rolled back if any fail.
 func Seed(ctx context.Context, db *sqlx.DB) (err error) {
@@ -0,0 +1,41 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/darwin/v3"
+)
+
+func Test_TenantMigrations(t *testing.T) {
+	shared := darwin.ParseMigrations(migrateDoc)
+
+	tenant, err := tenantMigrations()
+	if err != nil {
+		t.Fatalf("Should be able to build the tenant migrations: %s", err)
+	}
+
+	if len(tenant) != len(shared) {
+		t.Fatalf("Should have the same migrations: got %d, exp %d", len(tenant), len(shared))
+	}
+
+	for i, mig := range tenant {
+		if mig.Version != shared[i].Version {
+			t.Fatalf("Should keep the versions in order: got %v, exp %v", mig.Version, shared[i].Version)
+		}
+
+		if strings.Contains(mig.Script, "INSERT INTO orgs") {
+			t.Errorf("Should not seed an org in a tenant schema: version %v", mig.Version)
+		}
+
+		if _, exists := sharedSeeds[mig.Version]; !exists && mig.Script != shared[i].Script {
+			t.Errorf("Should leave the migrations without shared seeds alone: version %v", mig.Version)
+		}
+	}
+
+	for _, mig := range shared {
+		if stmt, exists := sharedSeeds[mig.Version]; exists && !strings.Contains(mig.Script, stmt) {
+			t.Errorf("Should still seed the shared schema: version %v", mig.Version)
+		}
+	}
+}
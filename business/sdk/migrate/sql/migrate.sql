@@ -13,3 +13,272 @@ CREATE TABLE users (
 
 	PRIMARY KEY (user_id)
 );
+
+-- Version: 1.02
+-- Description: Create table audit
+CREATE TABLE audit (
+	id          UUID      NOT NULL,
+	obj_id      UUID      NOT NULL,
+	obj_domain  TEXT      NOT NULL,
+	obj_name    TEXT      NOT NULL,
+	actor_id    UUID      NOT NULL,
+	action      TEXT      NOT NULL,
+	data        JSONB     NULL,
+	message     TEXT      NULL,
+	timestamp   TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (id)
+);
+
+-- Version: 1.03
+-- Description: Create table invitations
+CREATE TABLE invitations (
+	invitation_id UUID      NOT NULL,
+	email         TEXT      NOT NULL,
+	roles         TEXT[]    NOT NULL,
+	department    TEXT      NULL,
+	status        TEXT      NOT NULL,
+	token_hash    TEXT      NOT NULL,
+	invited_by    UUID      NOT NULL,
+	user_id       UUID      NULL,
+	date_expires  TIMESTAMP NOT NULL,
+	date_created  TIMESTAMP NOT NULL,
+	date_updated  TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (invitation_id),
+	FOREIGN KEY (invited_by) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX invitations_token_hash_idx ON invitations (token_hash);
+CREATE UNIQUE INDEX invitations_pending_email_idx ON invitations (email) WHERE status = 'PENDING';
+
+-- Version: 1.04
+-- Description: Add soft delete support to users
+ALTER TABLE users ADD COLUMN date_deleted TIMESTAMP NULL;
+
+CREATE INDEX users_date_deleted_idx ON users (date_deleted) WHERE date_deleted IS NOT NULL;
+
+-- Version: 1.05
+-- Description: Create table gdpr_jobs
+CREATE TABLE gdpr_jobs (
+	job_id         UUID      NOT NULL,
+	user_id        UUID      NOT NULL,
+	kind           TEXT      NOT NULL,
+	status         TEXT      NOT NULL,
+	archive        BYTEA     NULL,
+	error          TEXT      NULL,
+	requested_by   UUID      NOT NULL,
+	date_created   TIMESTAMP NOT NULL,
+	date_updated   TIMESTAMP NOT NULL,
+	date_completed TIMESTAMP NULL,
+
+	PRIMARY KEY (job_id)
+);
+
+CREATE INDEX gdpr_jobs_pending_idx ON gdpr_jobs (date_created) WHERE status = 'PENDING';
+
+-- Version: 1.06
+-- Description: Add optimistic concurrency version to users
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- Version: 1.07
+-- Description: Create table orgs and make users belong to an org
+CREATE TABLE orgs (
+	org_id       UUID        NOT NULL,
+	name         TEXT UNIQUE NOT NULL,
+	date_created TIMESTAMP   NOT NULL,
+	date_updated TIMESTAMP   NOT NULL,
+
+	PRIMARY KEY (org_id)
+);
+
+INSERT INTO orgs (org_id, name, date_created, date_updated) VALUES
+	('00000000-0000-0000-0000-000000000001', 'Default', NOW(), NOW());
+
+ALTER TABLE users ADD COLUMN org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES orgs(org_id);
+ALTER TABLE users ALTER COLUMN org_id DROP DEFAULT;
+
+CREATE INDEX users_org_id_idx ON users (org_id);
+
+-- Version: 1.08
+-- Description: Create table products
+CREATE TABLE products (
+	product_id   UUID           NOT NULL,
+	user_id      UUID           NOT NULL,
+	name         TEXT           NOT NULL,
+	cost         NUMERIC(10, 2) NOT NULL,
+	quantity     INT            NOT NULL,
+	date_created TIMESTAMP      NOT NULL,
+	date_updated TIMESTAMP      NOT NULL,
+
+	PRIMARY KEY (product_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id)
+);
+
+CREATE INDEX products_user_id_idx ON products (user_id);
+
+-- Version: 1.09
+-- Description: Create tables inventory, orders and order_lines
+CREATE TABLE inventory (
+	product_id   UUID      NOT NULL,
+	quantity     INT       NOT NULL CHECK (quantity >= 0),
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (product_id),
+	FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE
+);
+
+CREATE TABLE orders (
+	order_id     UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	status       TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (order_id)
+);
+
+CREATE INDEX orders_user_id_idx ON orders (user_id);
+
+CREATE TABLE order_lines (
+	order_id   UUID NOT NULL,
+	product_id UUID NOT NULL,
+	quantity   INT  NOT NULL CHECK (quantity > 0),
+
+	PRIMARY KEY (order_id, product_id),
+	FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE
+);
+
+-- Version: 1.10
+-- Description: Add currencies to product costs and priced order lines
+ALTER TABLE products ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE products ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE order_lines ADD COLUMN price NUMERIC(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE order_lines ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE order_lines ALTER COLUMN price DROP DEFAULT;
+ALTER TABLE order_lines ALTER COLUMN currency DROP DEFAULT;
+
+-- Version: 1.11
+-- Description: Create table roles
+CREATE TABLE roles (
+	name         TEXT      NOT NULL,
+	description  TEXT      NOT NULL,
+	permissions  TEXT[]    NOT NULL,
+	inherits     TEXT[]    NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (name)
+);
+
+INSERT INTO roles (name, description, permissions, inherits, date_created, date_updated) VALUES
+	('ADMIN', 'Administrators of the system', '{admin}', '{}', NOW(), NOW()),
+	('USER', 'Users of the system', '{user}', '{}', NOW(), NOW());
+
+-- Version: 1.12
+-- Description: Add full text and trigram search over users
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+ALTER TABLE users ADD COLUMN search TSVECTOR;
+
+CREATE FUNCTION users_search_update() RETURNS TRIGGER AS $$
+BEGIN
+	NEW.search :=
+		setweight(to_tsvector('simple', coalesce(NEW.name, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(NEW.email, '')), 'B') ||
+		setweight(to_tsvector('simple', coalesce(NEW.department, '')), 'C');
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_search_update BEFORE INSERT OR UPDATE OF name, email, department ON users
+	FOR EACH ROW EXECUTE FUNCTION users_search_update();
+
+UPDATE users SET name = name;
+
+CREATE INDEX users_search_idx ON users USING GIN (search);
+CREATE INDEX users_name_trgm_idx ON users USING GIN (name public.gin_trgm_ops);
+CREATE INDEX users_email_trgm_idx ON users USING GIN (email public.gin_trgm_ops);
+CREATE INDEX users_department_trgm_idx ON users USING GIN (department public.gin_trgm_ops);
+
+-- Version: 1.13
+-- Description: Store money amounts with the precision and scale of their currency
+ALTER TABLE products ALTER COLUMN cost TYPE NUMERIC;
+ALTER TABLE order_lines ALTER COLUMN price TYPE NUMERIC;
+
+-- Version: 1.14
+-- Description: Make invitations, products and orders belong to an org
+ALTER TABLE invitations ADD COLUMN org_id UUID NULL REFERENCES orgs(org_id);
+UPDATE invitations AS i SET org_id = u.org_id FROM users AS u WHERE u.user_id = i.invited_by;
+ALTER TABLE invitations ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE products ADD COLUMN org_id UUID NULL REFERENCES orgs(org_id);
+UPDATE products AS p SET org_id = u.org_id FROM users AS u WHERE u.user_id = p.user_id;
+ALTER TABLE products ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE orders ADD COLUMN org_id UUID NULL REFERENCES orgs(org_id);
+UPDATE orders AS o SET org_id = u.org_id FROM users AS u WHERE u.user_id = o.user_id;
+UPDATE orders SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE orders ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX invitations_org_id_idx ON invitations (org_id);
+CREATE INDEX products_org_id_idx ON products (org_id);
+CREATE INDEX orders_org_id_idx ON orders (org_id);
+
+-- Version: 1.15
+-- Description: Make user and pending invitation emails unique per org
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+ALTER TABLE users ADD CONSTRAINT users_org_id_email_key UNIQUE (org_id, email);
+
+DROP INDEX invitations_pending_email_idx;
+CREATE UNIQUE INDEX invitations_pending_email_idx ON invitations (org_id, email) WHERE status = 'PENDING';
+
+-- Version: 1.16
+-- Description: Make gdpr jobs belong to the org of their user
+ALTER TABLE gdpr_jobs ADD COLUMN org_id UUID NULL REFERENCES orgs(org_id);
+UPDATE gdpr_jobs AS j SET org_id = u.org_id FROM users AS u WHERE u.user_id = j.user_id;
+UPDATE gdpr_jobs SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE gdpr_jobs ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX gdpr_jobs_org_id_idx ON gdpr_jobs (org_id);
+CREATE INDEX gdpr_jobs_running_idx ON gdpr_jobs (date_updated) WHERE status = 'RUNNING';
+
+-- Version: 1.17
+-- Description: Remove the products of a user when the user is purged
+ALTER TABLE products DROP CONSTRAINT products_user_id_fkey;
+ALTER TABLE products ADD CONSTRAINT products_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
+
+-- Version: 1.18
+-- Description: Add a composite type to read and write an amount with its currency
+CREATE TYPE money_amount AS (
+	amount   NUMERIC,
+	currency CHAR(3)
+);
+
+-- Version: 1.19
+-- Description: Add the case folded key of user names for filtering and search
+ALTER TABLE users ADD COLUMN name_key TEXT NOT NULL DEFAULT '';
+UPDATE users SET name_key = lower(normalize(name, NFKC));
+ALTER TABLE users ALTER COLUMN name_key DROP DEFAULT;
+
+DROP INDEX users_name_trgm_idx;
+CREATE INDEX users_name_key_trgm_idx ON users USING GIN (name_key public.gin_trgm_ops);
+
+-- Version: 1.20
+-- Description: Let admins manage the orders and products of every user through permissions
+UPDATE roles SET permissions = permissions || '{order:manage_any,product:manage_any}' WHERE name = 'ADMIN';
+
+-- Version: 1.21
+-- Description: Free the email of a soft deleted user for a new user of the org
+ALTER TABLE users DROP CONSTRAINT users_org_id_email_key;
+CREATE UNIQUE INDEX users_org_id_email_key ON users (org_id, email) WHERE date_deleted IS NULL;
+
+-- Version: 1.22
+-- Description: Leave removing products to the product domain and keep invitations when their inviter is purged
+ALTER TABLE products DROP CONSTRAINT products_user_id_fkey;
+ALTER TABLE products ADD CONSTRAINT products_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(user_id);
+
+ALTER TABLE invitations ALTER COLUMN invited_by DROP NOT NULL;
+ALTER TABLE invitations DROP CONSTRAINT invitations_invited_by_fkey;
+ALTER TABLE invitations ADD CONSTRAINT invitations_invited_by_fkey FOREIGN KEY (invited_by) REFERENCES users(user_id) ON DELETE SET NULL;
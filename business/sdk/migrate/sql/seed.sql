@@ -1,4 +1,8 @@
-INSERT INTO users (user_id, name, email, roles, password_hash, department, enabled, date_created, date_updated) VALUES
-	('5cf37266-3473-4006-984f-9325122678b7', 'Admin Gopher', 'admin@example.com', '{ADMIN}', '$2a$10$1ggfMVZV6Js0ybvJufLRUOWHS5f6KneuP0XwwHpJ8L8ipdry9f2/a', NULL, true, '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
-	('45b5fbd3-755f-4379-8f07-a58d4a30fa2f', 'User Gopher', 'user@example.com', '{USER}', '$2a$10$9/XASPKBbJKVfCAZKDH.UuhsuALDr5vVm6VrYA9VFR8rccK86C1hW', NULL, true, '2019-03-24 00:00:00', '2019-03-24 00:00:00')
-ON CONFLICT DO NOTHING;
\ No newline at end of file
+INSERT INTO users (user_id, org_id, name, name_key, email, roles, password_hash, department, enabled, date_created, date_updated) VALUES
+	('5cf37266-3473-4006-984f-9325122678b7', '00000000-0000-0000-0000-000000000001', 'Admin Gopher', 'admin gopher', 'admin@example.com', '{ADMIN}', '$2a$10$1ggfMVZV6Js0ybvJufLRUOWHS5f6KneuP0XwwHpJ8L8ipdry9f2/a', NULL, true, '2019-03-24 00:00:00', '2019-03-24 00:00:00'),
+	('45b5fbd3-755f-4379-8f07-a58d4a30fa2f', '00000000-0000-0000-0000-000000000001', 'User Gopher', 'user gopher', 'user@example.com', '{USER}', '$2a$10$9/XASPKBbJKVfCAZKDH.UuhsuALDr5vVm6VrYA9VFR8rccK86C1hW', NULL, true, '2019-03-24 00:00:00', '2019-03-24 00:00:00')
+ON CONFLICT DO NOTHING;
+INSERT INTO products (product_id, org_id, user_id, name, cost, currency, quantity, date_created, date_updated) VALUES
+	('a2b0639f-2cc6-44b8-b97b-15d69dbb511e', '00000000-0000-0000-0000-000000000001', '5cf37266-3473-4006-984f-9325122678b7', 'Comic Books', 50, 'USD', 42, '2019-01-01 00:00:01.000001+00', '2019-01-01 00:00:01.000001+00'),
+	('72f8b983-3eb4-48db-9ed0-e45cc6bd716b', '00000000-0000-0000-0000-000000000001', '45b5fbd3-755f-4379-8f07-a58d4a30fa2f', 'McDonalds Toys', 75, 'USD', 120, '2019-01-01 00:00:02.000001+00', '2019-01-01 00:00:02.000001+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,239 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"service/foundation/logger"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// Postgres accepts at most maxParams parameters in a single statement,
+// which caps the number of rows in a batch.
+const (
+	maxParams        = 65535
+	defaultBatchSize = 1000
+)
+
+// CopyFrom inserts the rows into the table using the COPY protocol, which
+// is much faster than inserting them one statement at a time. The columns
+// are taken from the db tags of T the same way sqlx binds a struct.
+//
+// The database can be a connection pool, a Cluster or a transaction
+// started by DBBeginner. The driver connection behind any other database,
+// like a plain *sqlx.Tx or a Cluster running the call in a schema, can't be
+// reached, so the rows are written with BatchUpsert through that database
+// instead.
+func CopyFrom[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, table string, rows []T) (err error) {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	cols := columnsOf(reflect.TypeFor[T]())
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.name
+	}
+
+	tgt, err := routeWrite(ctx, db)
+	if err != nil {
+		return err
+	}
+	db = tgt.db
+
+	switch db.(type) {
+	case *Tx, *sqlx.DB:
+	default:
+		return tgt.done(BatchUpsert(ctx, log, db, Upsert{Table: table}, rows))
+	}
+
+	q := fmt.Sprintf("COPY %s (%s) FROM STDIN", table, strings.Join(names, ", "))
+	ctx, c := startCall(ctx, "CopyFrom", q, q, nil, false)
+
+	defer func() {
+		c.finish(ctx, log, db, err)
+		err = tgt.done(err)
+		noteRetry(ctx, err)
+
+		if err != nil {
+			log.Infoc(ctx, 5, "database.CopyFrom", "query", q, "rows", len(rows), "ERROR", err)
+		}
+	}()
+
+	src := pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+		v := reflect.ValueOf(rows[i])
+		values := make([]any, len(cols))
+		for j, col := range cols {
+			values[j] = v.FieldByIndex(col.index).Interface()
+		}
+		return values, nil
+	})
+
+	copyFn := func(driverConn any) error {
+		conn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("driver connection(%T) is not a pgx connection", driverConn)
+		}
+
+		_, err := conn.Conn().CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), names, src)
+		return err
+	}
+
+	if err := rawConn(ctx, db, copyFn); err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
+
+// rawConn runs the function with the driver connection behind the database.
+func rawConn(ctx context.Context, db sqlx.ExtContext, fn func(driverConn any) error) error {
+	switch db := db.(type) {
+	case *Tx:
+		return db.conn.Raw(fn)
+
+	case *sqlx.DB:
+		conn, err := db.Connx(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		return conn.Raw(fn)
+	}
+
+	return fmt.Errorf("database(%T) doesn't expose its driver connection, use a transaction from DBBeginner", db)
+}
+
+// =============================================================================
+
+// Upsert describes how BatchUpsert writes the rows of a table. Rows
+// conflicting on the Conflict columns have the Update columns overwritten,
+// or are skipped when Update is empty. Without Conflict columns every row
+// is inserted and a conflict is reported as ErrDBDuplicatedEntry.
+//
+// A conflict key can only appear once in a batch since Postgres won't
+// update the same row twice in a statement.
+type Upsert struct {
+	Table     string
+	Conflict  []string
+	Update    []string
+	BatchSize int
+}
+
+// BatchUpsert writes the rows into the table with multi row INSERT
+// statements of up to BatchSize rows. A batch is made smaller when its rows
+// would need more parameters than Postgres accepts in a statement. The
+// columns are taken from the db tags of T the same way sqlx binds a struct.
+//
+// Each batch is a separate statement, so run it inside a transaction when
+// the rows must be written all or nothing.
+func BatchUpsert[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, ups Upsert, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	cols := columnsOf(reflect.TypeFor[T]())
+	if len(cols) == 0 {
+		return errors.New("no columns to write")
+	}
+
+	names := make([]string, len(cols))
+	params := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.name
+		params[i] = ":" + col.name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES (%s)", ups.Table, strings.Join(names, ", "), strings.Join(params, ", "))
+
+	if len(ups.Conflict) > 0 {
+		fmt.Fprintf(&b, " ON CONFLICT (%s)", strings.Join(ups.Conflict, ", "))
+
+		switch len(ups.Update) {
+		case 0:
+			b.WriteString(" DO NOTHING")
+		default:
+			set := make([]string, len(ups.Update))
+			for i, col := range ups.Update {
+				set[i] = fmt.Sprintf("%s = EXCLUDED.%s", col, col)
+			}
+			fmt.Fprintf(&b, " DO UPDATE SET %s", strings.Join(set, ", "))
+		}
+	}
+
+	q := b.String()
+
+	size := ups.BatchSize
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+	size = min(size, maxParams/len(cols))
+
+	for batch := range slices.Chunk(rows, size) {
+		if err := NamedExecContext(ctx, log, db, q, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+type column struct {
+	name  string
+	index []int
+}
+
+// columnsOf returns the columns of a struct type following the sqlx rules:
+// the db tag names the column, untagged fields use the lowercase field
+// name and embedded structs add their own fields.
+func columnsOf(t reflect.Type) []column {
+	var cols []column
+
+	valuer := reflect.TypeFor[driver.Valuer]()
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := range t.NumField() {
+			f := t.Field(i)
+
+			name, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+			if name == "-" {
+				continue
+			}
+
+			idx := append(slices.Clone(index), i)
+
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct && !f.Type.Implements(valuer) {
+				walk(f.Type, idx)
+				continue
+			}
+
+			if !f.IsExported() {
+				continue
+			}
+
+			if name == "" {
+				name = sqlx.NameMapper(f.Name)
+			}
+
+			cols = append(cols, column{name: name, index: idx})
+		}
+	}
+
+	if t.Kind() == reflect.Struct {
+		walk(t, nil)
+	}
+
+	return cols
+}
@@ -0,0 +1,233 @@
+package sqldb_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/unitest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type base struct {
+	SKU string `db:"sku"`
+}
+
+type item struct {
+	base
+	Name     string `db:"name"`
+	Quantity int    `db:"quantity"`
+	Ignored  string `db:"-"`
+}
+
+func Test_Bulk(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Bulk")
+
+	const q = `CREATE TABLE items (sku TEXT PRIMARY KEY, name TEXT NOT NULL, quantity INT NOT NULL)`
+	if err := sqldb.ExecContext(context.Background(), db.Log, db.DB, q); err != nil {
+		t.Fatalf("Creating table: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, copyFrom(db), "copyfrom")
+	unitest.Run(t, batchUpsert(db), "batchupsert")
+}
+
+// =============================================================================
+
+func copyFrom(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "transaction",
+			ExpResp: 1000,
+			ExcFunc: func(ctx context.Context) any {
+				items := make([]item, 1000)
+				for i := range items {
+					items[i] = item{base: base{SKU: fmt.Sprintf("SKU-%04d", i)}, Name: "Item", Quantity: i}
+				}
+
+				tx, err := sqldb.NewBeginner(db.DB).Begin(ctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				ec, err := sqldb.GetExtContext(tx)
+				if err != nil {
+					return err
+				}
+
+				if err := sqldb.CopyFrom(ctx, db.Log, ec, "items", items); err != nil {
+					return err
+				}
+
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				return count(ctx, db)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "sqlxtx",
+			ExpResp: 1010,
+			ExcFunc: func(ctx context.Context) any {
+				items := make([]item, 10)
+				for i := range items {
+					items[i] = item{base: base{SKU: fmt.Sprintf("TX-%04d", i)}, Name: "Item", Quantity: i}
+				}
+
+				tx, err := db.DB.BeginTxx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				if err := sqldb.CopyFrom(ctx, db.Log, tx, "items", items); err != nil {
+					return err
+				}
+
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				return count(ctx, db)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "duplicate",
+			ExpResp: sqldb.ErrDBDuplicatedEntry,
+			ExcFunc: func(ctx context.Context) any {
+				items := []item{{base: base{SKU: "SKU-0000"}, Name: "Again"}}
+
+				return sqldb.CopyFrom(ctx, db.Log, db.DB, "items", items)
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func batchUpsert(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "update",
+			ExpResp: []item{{base: base{SKU: "SKU-0001"}, Name: "Item", Quantity: 50}, {base: base{SKU: "SKU-9999"}, Name: "New", Quantity: 1}},
+			ExcFunc: func(ctx context.Context) any {
+				items := []item{
+					{base: base{SKU: "SKU-0001"}, Name: "Renamed", Quantity: 50},
+					{base: base{SKU: "SKU-9999"}, Name: "New", Quantity: 1},
+				}
+
+				ups := sqldb.Upsert{
+					Table:     "items",
+					Conflict:  []string{"sku"},
+					Update:    []string{"quantity"},
+					BatchSize: 1,
+				}
+
+				if err := sqldb.BatchUpsert(ctx, db.Log, db.DB, ups, items); err != nil {
+					return err
+				}
+
+				const q = `SELECT sku, name, quantity FROM items WHERE sku IN ('SKU-0001', 'SKU-9999') ORDER BY sku`
+
+				var got []item
+				if err := sqldb.QuerySlice(ctx, db.Log, db.DB, q, &got); err != nil {
+					return err
+				}
+
+				return got
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp, cmp.AllowUnexported(item{}))
+			},
+		},
+		{
+			Name:    "params",
+			ExpResp: 30000,
+			ExcFunc: func(ctx context.Context) any {
+				// 30000 rows of 3 columns are more parameters than a single
+				// statement can hold.
+				items := make([]item, 30000)
+				for i := range items {
+					items[i] = item{base: base{SKU: fmt.Sprintf("BIG-%05d", i)}, Name: "Item", Quantity: i}
+				}
+
+				ups := sqldb.Upsert{
+					Table:     "items",
+					BatchSize: len(items),
+				}
+
+				if err := sqldb.BatchUpsert(ctx, db.Log, db.DB, ups, items); err != nil {
+					return err
+				}
+
+				const q = `SELECT count(1) AS count FROM items WHERE sku LIKE 'BIG-%'`
+
+				var row struct {
+					Count int `db:"count"`
+				}
+				if err := sqldb.QueryStruct(ctx, db.Log, db.DB, q, &row); err != nil {
+					return err
+				}
+
+				return row.Count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "duplicate",
+			ExpResp: sqldb.ErrDBDuplicatedEntry,
+			ExcFunc: func(ctx context.Context) any {
+				items := []item{{base: base{SKU: "SKU-0002"}, Name: "Again"}}
+
+				return sqldb.BatchUpsert(ctx, db.Log, db.DB, sqldb.Upsert{Table: "items"}, items)
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func count(ctx context.Context, db *dbtest.Database) any {
+	const q = `SELECT count(1) AS count FROM items`
+
+	var row struct {
+		Count int `db:"count"`
+	}
+	if err := sqldb.QueryStruct(ctx, db.Log, db.DB, q, &row); err != nil {
+		return err
+	}
+
+	return row.Count
+}
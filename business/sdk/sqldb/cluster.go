@@ -0,0 +1,406 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+// targets holds the metrics for every database a cluster routes to. The
+// expvar package registers values as singletons so a single map is shared
+// by all clusters in the process.
+var targets = expvar.NewMap("sqldb")
+
+// ClusterConfig is the required properties to use a primary database with
+// a set of read replicas. The replicas share the credentials and settings
+// of the primary.
+//
+// With a Schemas function the statements of a call are run in the schema it
+// returns for the call. Every schema shares the same connection pools, each
+// statement running in a transaction of its own bound to the schema.
+type ClusterConfig struct {
+	Config
+	ReplicaHosts []string
+	MaxLag       time.Duration
+	Schemas      SchemaFunc
+}
+
+// Cluster routes the queries made through the helper functions of this
+// package between a primary database and its read replicas. Read only
+// statements go to a healthy replica and everything else, including the
+// transactions started from the primary, goes to the primary.
+//
+// Cluster implements sqlx.ExtContext so it can be handed to the stores in
+// place of a *sqlx.DB. Calls made directly on the interface always go to
+// the primary.
+type Cluster struct {
+	primary  *sqlx.DB
+	stats    *targetStats
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+
+	schemas SchemaFunc
+}
+
+type replica struct {
+	db      *sqlx.DB
+	stats   *targetStats
+	healthy atomic.Bool
+}
+
+// OpenCluster opens the primary and replica databases based on the
+// configuration. Replicas start out healthy until the first Check says
+// otherwise.
+func OpenCluster(cfg ClusterConfig) (*Cluster, error) {
+	primary, err := Open(cfg.Config)
+	if err != nil {
+		return nil, fmt.Errorf("open primary: %w", err)
+	}
+
+	PublishStats("primary", primary)
+
+	c := Cluster{
+		primary: primary,
+		stats:   newTargetStats("primary"),
+		maxLag:  cfg.MaxLag,
+		schemas: cfg.Schemas,
+	}
+
+	for i, host := range cfg.ReplicaHosts {
+		// A replica that's down at startup is left to the health checks so
+		// it can't keep the service from starting.
+		rCfg := cfg.Config
+		rCfg.Host = host
+		rCfg.ConnectTimeout = 0
+
+		db, err := Open(rCfg)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("open replica[%s]: %w", host, err)
+		}
+
+		name := fmt.Sprintf("replica-%d", i)
+		PublishStats(name, db)
+
+		r := replica{
+			db:    db,
+			stats: newTargetStats(name),
+		}
+		r.healthy.Store(true)
+		r.stats.healthy.Set(1)
+
+		c.replicas = append(c.replicas, &r)
+	}
+
+	return &c, nil
+}
+
+// Primary returns the primary database for the APIs that need a concrete
+// connection pool, like starting a transaction.
+func (c *Cluster) Primary() *sqlx.DB {
+	return c.primary
+}
+
+// Close closes the primary and every replica.
+func (c *Cluster) Close() error {
+	errs := []error{c.primary.Close()}
+	for _, r := range c.replicas {
+		errs = append(errs, r.db.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// bind returns the target the statement of a call with a schema runs on.
+// The statement gets a transaction of its own that sets the search_path
+// with SET LOCAL, which only lasts until the end of the transaction, so the
+// connection goes back to the pool with its original search_path whatever
+// happens to the statement.
+func (c *Cluster) bind(ctx context.Context, db *sqlx.DB, stats *targetStats) (target, error) {
+	tgt := target{db: db, stats: stats}
+
+	if c.schemas == nil {
+		return tgt, nil
+	}
+
+	schema, ok := c.schemas(ctx)
+	if !ok {
+		return tgt, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return target{}, fmt.Errorf("begin: schema[%s]: %w", schema, err)
+	}
+
+	q := "SET LOCAL search_path TO " + pgx.Identifier{schema}.Sanitize()
+	if _, err := tx.ExecContext(ctx, q); err != nil {
+		tx.Rollback()
+		return target{}, fmt.Errorf("set search_path[%s]: %w", schema, err)
+	}
+
+	tgt.db = tx
+	tgt.tx = tx
+
+	return tgt, nil
+}
+
+// Check measures how far behind the primary each replica is. A replica that
+// can't be reached or lags more than the configured maximum stops receiving
+// reads until a later check finds it caught up.
+func (c *Cluster) Check(ctx context.Context) error {
+
+	// A replica that has replayed everything it received is up to date no
+	// matter how long ago the last transaction was replayed.
+	const q = `
+	SELECT
+		CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END AS lag`
+
+	var errs []error
+	for i, r := range c.replicas {
+		var lag float64
+		if err := r.db.QueryRowContext(ctx, q).Scan(&lag); err != nil {
+			r.setHealthy(false)
+			errs = append(errs, fmt.Errorf("replica-%d: %w", i, err))
+			continue
+		}
+
+		d := time.Duration(lag * float64(time.Second))
+		r.stats.lag.Set(d.Milliseconds())
+		r.setHealthy(c.maxLag <= 0 || d <= c.maxLag)
+	}
+
+	return errors.Join(errs...)
+}
+
+// reader returns the database the next read only statement should use.
+func (c *Cluster) reader(ctx context.Context) (*sqlx.DB, *targetStats) {
+	if len(c.replicas) == 0 || usePrimary(ctx) {
+		return c.primary, c.stats
+	}
+
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		r := c.replicas[(start+uint64(i))%uint64(len(c.replicas))]
+		if r.healthy.Load() {
+			return r.db, r.stats
+		}
+	}
+
+	return c.primary, c.stats
+}
+
+func (r *replica) setHealthy(healthy bool) {
+	r.healthy.Store(healthy)
+
+	var v int64
+	if healthy {
+		v = 1
+	}
+	r.stats.healthy.Set(v)
+}
+
+// =============================================================================
+// The sqlx.ExtContext implementation sends everything to the primary. It
+// doesn't know about schemas, the statements of a call with a schema must
+// be run through the helper functions of this package.
+
+// DriverName returns the driver name of the primary.
+func (c *Cluster) DriverName() string {
+	return c.primary.DriverName()
+}
+
+// Rebind transforms a query from QUESTION to the bindvar type of the primary.
+func (c *Cluster) Rebind(query string) string {
+	return c.primary.Rebind(query)
+}
+
+// BindNamed binds a query using the bindvar type of the primary.
+func (c *Cluster) BindNamed(query string, arg any) (string, []any, error) {
+	return c.primary.BindNamed(query, arg)
+}
+
+// QueryContext executes the query against the primary.
+func (c *Cluster) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return c.primary.QueryContext(ctx, query, args...)
+}
+
+// QueryxContext executes the query against the primary.
+func (c *Cluster) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	return c.primary.QueryxContext(ctx, query, args...)
+}
+
+// QueryRowxContext executes the query against the primary.
+func (c *Cluster) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	return c.primary.QueryRowxContext(ctx, query, args...)
+}
+
+// ExecContext executes the statement against the primary.
+func (c *Cluster) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	markWrite(ctx)
+	return c.primary.ExecContext(ctx, query, args...)
+}
+
+// =============================================================================
+
+// target is the database a helper function runs its statement against. A
+// statement run in a schema has the transaction binding it to the schema.
+type target struct {
+	db    sqlx.ExtContext
+	stats *targetStats
+	tx    *sqlx.Tx
+}
+
+// route picks the database for the statement. Only a Cluster is routed,
+// transactions and plain connection pools are used as given.
+func route(ctx context.Context, db sqlx.ExtContext, query string) (target, error) {
+	c, ok := db.(*Cluster)
+	if !ok {
+		return target{db: db}, nil
+	}
+
+	if !readOnly(query) {
+		return routeWrite(ctx, db)
+	}
+
+	rdb, stats := c.reader(ctx)
+	return c.bind(ctx, rdb, stats)
+}
+
+// routeWrite picks the database for a statement that must run on the
+// primary whatever its text, like the statements run by NamedExecContext.
+func routeWrite(ctx context.Context, db sqlx.ExtContext) (target, error) {
+	c, ok := db.(*Cluster)
+	if !ok {
+		return target{db: db}, nil
+	}
+
+	markWrite(ctx)
+
+	return c.bind(ctx, c.primary, c.stats)
+}
+
+// done records the outcome of the statement for the target and ends the
+// transaction of a statement run in a schema, committing it when the
+// statement succeeded. The error of the commit is returned.
+func (t target) done(err error) error {
+	if t.tx != nil {
+		switch {
+		case err != nil && !errors.Is(err, ErrDBNotFound):
+			t.tx.Rollback()
+		default:
+			if cErr := t.tx.Commit(); cErr != nil {
+				err = mapError(cErr)
+			}
+		}
+	}
+
+	if t.stats == nil {
+		return err
+	}
+
+	t.stats.queries.Add(1)
+	if err != nil && !errors.Is(err, ErrDBNotFound) {
+		t.stats.errors.Add(1)
+	}
+
+	return err
+}
+
+// readOnly reports whether the statement can run on a replica. Anything
+// other than a plain SELECT is treated as a write. Statements calling
+// functions with side effects must be sent to the primary with WithPrimary.
+func readOnly(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	if !strings.HasPrefix(query, "SELECT") {
+		return false
+	}
+
+	for _, lock := range []string{"FOR UPDATE", "FOR NO KEY UPDATE", "FOR SHARE", "FOR KEY SHARE"} {
+		if strings.Contains(query, lock) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// =============================================================================
+
+type targetStats struct {
+	queries expvar.Int
+	errors  expvar.Int
+	healthy expvar.Int
+	lag     expvar.Int
+}
+
+func newTargetStats(name string) *targetStats {
+	var s targetStats
+
+	m := new(expvar.Map).Init()
+	m.Set("queries", &s.queries)
+	m.Set("errors", &s.errors)
+	m.Set("healthy", &s.healthy)
+	m.Set("lag_ms", &s.lag)
+
+	targets.Set(name, m)
+
+	return &s
+}
+
+// =============================================================================
+
+type ctxKey int
+
+const (
+	sessionKey ctxKey = iota + 1
+	primaryKey
+	retryKey
+	commitKey
+	isolationKey
+	txKey
+)
+
+type session struct {
+	wrote atomic.Bool
+}
+
+// WithSession starts a read-your-writes session. Once a write or a
+// transaction happens under the returned context, every following read
+// under it goes to the primary so a replica that hasn't caught up can't
+// hide the change.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey, &session{})
+}
+
+// WithPrimary sends every read under the returned context to the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey, true)
+}
+
+func markWrite(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey).(*session); ok {
+		s.wrote.Store(true)
+	}
+}
+
+func usePrimary(ctx context.Context) bool {
+	if v, ok := ctx.Value(primaryKey).(bool); ok && v {
+		return true
+	}
+
+	s, ok := ctx.Value(sessionKey).(*session)
+	return ok && s.wrote.Load()
+}
@@ -0,0 +1,97 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func Test_Route(t *testing.T) {
+	primary := sqlx.NewDb(&sql.DB{}, "pgx")
+	r1 := replica{db: sqlx.NewDb(&sql.DB{}, "pgx"), stats: &targetStats{}}
+	r2 := replica{db: sqlx.NewDb(&sql.DB{}, "pgx"), stats: &targetStats{}}
+	r1.healthy.Store(true)
+
+	c := Cluster{
+		primary:  primary,
+		stats:    &targetStats{},
+		replicas: []*replica{&r1, &r2},
+	}
+
+	const read = `
+	SELECT
+		user_id
+	FROM
+		users`
+
+	dbOf := func(tgt target, err error) sqlx.ExtContext {
+		if err != nil {
+			t.Fatalf("Should be able to route the statement: %s", err)
+		}
+		return tgt.db
+	}
+
+	ctx := WithSession(context.Background())
+
+	if got := dbOf(route(ctx, &c, read)); got != r1.db {
+		t.Fatalf("read: expected the healthy replica, got %p", got)
+	}
+
+	if got := dbOf(route(ctx, &c, read+" FOR UPDATE")); got != primary {
+		t.Fatalf("locking read: expected the primary, got %p", got)
+	}
+
+	if got := dbOf(route(WithPrimary(ctx), &c, read)); got != primary {
+		t.Fatalf("forced: expected the primary, got %p", got)
+	}
+
+	r1.healthy.Store(false)
+	if got := dbOf(route(ctx, &c, read)); got != primary {
+		t.Fatalf("no healthy replica: expected the primary, got %p", got)
+	}
+	r1.healthy.Store(true)
+
+	if got := dbOf(route(ctx, &c, "UPDATE users SET name = :name")); got != primary {
+		t.Fatalf("write: expected the primary, got %p", got)
+	}
+
+	if got := dbOf(route(ctx, &c, read)); got != primary {
+		t.Fatalf("read after write: expected the primary, got %p", got)
+	}
+
+	if got := dbOf(route(context.Background(), &c, read)); got != r1.db {
+		t.Fatalf("new session: expected the healthy replica, got %p", got)
+	}
+
+	if got := dbOf(routeWrite(context.Background(), &c)); got != primary {
+		t.Fatalf("exec: expected the primary, got %p", got)
+	}
+
+	tx := &sqlx.Tx{}
+	if got := dbOf(route(ctx, tx, read)); got != tx {
+		t.Fatalf("transaction: expected the transaction, got %p", got)
+	}
+}
+
+func Test_RouteSchema(t *testing.T) {
+	c := Cluster{
+		primary: sqlx.NewDb(&sql.DB{}, "pgx"),
+		stats:   &targetStats{},
+		schemas: func(ctx context.Context) (string, bool) {
+			return "", false
+		},
+	}
+
+	const read = `SELECT user_id FROM users`
+
+	tgt, err := route(context.Background(), &c, read)
+	if err != nil {
+		t.Fatalf("Should be able to route the statement: %s", err)
+	}
+
+	if tgt.db != c.primary || tgt.tx != nil {
+		t.Fatalf("no schema: expected the primary without a transaction, got %p", tgt.db)
+	}
+}
@@ -0,0 +1,55 @@
+package sqldb
+
+import (
+	"context"
+	"sync"
+)
+
+type afterCommit struct {
+	mu    sync.Mutex
+	funcs []func(ctx context.Context)
+}
+
+// TrackCommit returns a context that collects the functions passed to
+// AfterCommit under it. The owner of the transaction runs them with
+// RunAfterCommit once the transaction commits, and drops the context when
+// it rolls back.
+func TrackCommit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, commitKey, &afterCommit{})
+}
+
+// AfterCommit defers the function until the transaction of the context
+// commits. It's meant for work outside the database, like sending an email,
+// that must not happen for a transaction that is rolled back or happen
+// twice for one that is retried. The function runs right away when the
+// context doesn't come from TrackCommit.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	ac, ok := ctx.Value(commitKey).(*afterCommit)
+	if !ok {
+		fn(ctx)
+		return
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	ac.funcs = append(ac.funcs, fn)
+}
+
+// RunAfterCommit runs the functions collected under a context returned by
+// TrackCommit in the order they were added. Each function only runs once.
+func RunAfterCommit(ctx context.Context) {
+	ac, ok := ctx.Value(commitKey).(*afterCommit)
+	if !ok {
+		return
+	}
+
+	ac.mu.Lock()
+	funcs := ac.funcs
+	ac.funcs = nil
+	ac.mu.Unlock()
+
+	for _, fn := range funcs {
+		fn(ctx)
+	}
+}
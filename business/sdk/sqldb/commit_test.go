@@ -0,0 +1,39 @@
+package sqldb
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func Test_AfterCommit(t *testing.T) {
+	var got []string
+	record := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			got = append(got, name)
+		}
+	}
+
+	AfterCommit(context.Background(), record("untracked"))
+
+	if exp := []string{"untracked"}; !slices.Equal(got, exp) {
+		t.Fatalf("Should run right away without a tracked commit: got %v, exp %v", got, exp)
+	}
+
+	got = nil
+	ctx := TrackCommit(context.Background())
+
+	AfterCommit(ctx, record("first"))
+	AfterCommit(ctx, record("second"))
+
+	if len(got) != 0 {
+		t.Fatalf("Should wait for the commit: got %v", got)
+	}
+
+	RunAfterCommit(ctx)
+	RunAfterCommit(ctx)
+
+	if exp := []string{"first", "second"}; !slices.Equal(got, exp) {
+		t.Fatalf("Should run once in order after the commit: got %v, exp %v", got, exp)
+	}
+}
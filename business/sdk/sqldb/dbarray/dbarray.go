@@ -29,10 +29,14 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 var typeByteSlice = reflect.TypeOf([]byte{})
@@ -70,6 +74,12 @@ func Array(a any) interface {
 		return (*String)(&a)
 	case [][]byte:
 		return (*Bytea)(&a)
+	case []uuid.UUID:
+		return (*UUID)(&a)
+	case []time.Time:
+		return (*Time)(&a)
+	case []json.RawMessage:
+		return (*JSONB)(&a)
 
 	case *[]bool:
 		return (*Bool)(a)
@@ -85,6 +95,12 @@ func Array(a any) interface {
 		return (*String)(a)
 	case *[][]byte:
 		return (*Bytea)(a)
+	case *[]uuid.UUID:
+		return (*UUID)(a)
+	case *[]time.Time:
+		return (*Time)(a)
+	case *[]json.RawMessage:
+		return (*JSONB)(a)
 	}
 
 	return Generic{a}
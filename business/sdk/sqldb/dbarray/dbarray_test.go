@@ -0,0 +1,171 @@
+package dbarray_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/sqldb/dbarray"
+	"service/business/sdk/unitest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+type row struct {
+	ID      int                           `db:"id"`
+	UUIDs   dbarray.UUID                  `db:"uuids"`
+	Times   dbarray.Time                  `db:"times"`
+	Amounts dbarray.Numeric               `db:"amounts"`
+	Docs    dbarray.JSONB                 `db:"docs"`
+	Names   dbarray.Slice[sql.NullString] `db:"names"`
+	Refs    dbarray.Slice[uuid.UUID]      `db:"refs"`
+}
+
+func Test_Array(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Array")
+
+	const q = `
+	CREATE TABLE arrays (
+		id      INT PRIMARY KEY,
+		uuids   UUID[],
+		times   TIMESTAMPTZ[],
+		amounts NUMERIC(10, 2)[],
+		docs    JSONB[],
+		names   TEXT[],
+		refs    UUID[]
+	)`
+
+	if err := sqldb.ExecContext(context.Background(), db.Log, db.DB, q); err != nil {
+		t.Fatalf("Creating table: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, roundTrip(db), "roundtrip")
+}
+
+// =============================================================================
+
+func roundTrip(db *dbtest.Database) []unitest.Table {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	bc := time.Date(44, time.March, 15, 0, 0, 0, 0, time.UTC).AddDate(-87, 0, 0)
+
+	values := row{
+		ID:      1,
+		UUIDs:   dbarray.UUID{uuid.New(), uuid.New()},
+		Times:   dbarray.Time{now, now.Add(-time.Hour), bc},
+		Amounts: dbarray.Numeric{"10.50", "-3.25", "0.00"},
+		Docs:    dbarray.JSONB{json.RawMessage(`{"a": "x,\"y\"}"}`), json.RawMessage(`[1, 2]`), json.RawMessage(`null`), nil},
+		Names:   dbarray.Slice[sql.NullString]{{String: `with "quotes", and commas`, Valid: true}, {}},
+		Refs:    dbarray.Slice[uuid.UUID]{uuid.New()},
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "values",
+			ExpResp: values,
+			ExcFunc: func(ctx context.Context) any {
+				return insertAndGet(ctx, db, values)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "empty",
+			ExpResp: row{ID: 2, UUIDs: dbarray.UUID{}, Times: dbarray.Time{}, Amounts: dbarray.Numeric{}, Docs: dbarray.JSONB{}, Names: dbarray.Slice[sql.NullString]{}, Refs: dbarray.Slice[uuid.UUID]{}},
+			ExcFunc: func(ctx context.Context) any {
+				return insertAndGet(ctx, db, row{ID: 2, UUIDs: dbarray.UUID{}, Times: dbarray.Time{}, Amounts: dbarray.Numeric{}, Docs: dbarray.JSONB{}, Names: dbarray.Slice[sql.NullString]{}, Refs: dbarray.Slice[uuid.UUID]{}})
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "null",
+			ExpResp: row{ID: 3},
+			ExcFunc: func(ctx context.Context) any {
+				return insertAndGet(ctx, db, row{ID: 3})
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "anyuuid",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				var r row
+				if err := get(ctx, db, 1, &r); err != nil {
+					return err
+				}
+
+				const q = `SELECT count(1) AS count FROM arrays WHERE refs && :refs`
+
+				data := struct {
+					Refs dbarray.UUID `db:"refs"`
+				}{
+					Refs: dbarray.UUID{uuid.New(), r.Refs[0]},
+				}
+
+				var dest struct {
+					Count int `db:"count"`
+				}
+				if err := sqldb.NamedQueryStruct(ctx, db.Log, db.DB, q, data, &dest); err != nil {
+					return err
+				}
+
+				return dest.Count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func insertAndGet(ctx context.Context, db *dbtest.Database, r row) any {
+	const q = `
+	INSERT INTO arrays
+		(id, uuids, times, amounts, docs, names, refs)
+	VALUES
+		(:id, :uuids, :times, :amounts, :docs, :names, :refs)`
+
+	if err := sqldb.NamedExecContext(ctx, db.Log, db.DB, q, r); err != nil {
+		return err
+	}
+
+	var got row
+	if err := get(ctx, db, r.ID, &got); err != nil {
+		return err
+	}
+
+	return got
+}
+
+func get(ctx context.Context, db *dbtest.Database, id int, dest *row) error {
+	const q = `
+	SELECT
+		id, uuids, times, amounts, docs, names, refs
+	FROM
+		arrays
+	WHERE
+		id = :id`
+
+	data := struct {
+		ID int `db:"id"`
+	}{
+		ID: id,
+	}
+
+	return sqldb.NamedQueryStruct(ctx, db.Log, db.DB, q, data, dest)
+}
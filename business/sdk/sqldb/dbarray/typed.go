@@ -0,0 +1,352 @@
+package dbarray
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UUID represents a one-dimensional array of the PostgreSQL uuid type.
+type UUID []uuid.UUID
+
+// Scan implements the sql.Scanner interface.
+func (a *UUID) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return a.scanBytes(src)
+	case string:
+		return a.scanBytes([]byte(src))
+	case nil:
+		*a = nil
+		return nil
+	}
+
+	return fmt.Errorf("database: cannot convert %T to UUID", src)
+}
+
+func (a *UUID) scanBytes(src []byte) error {
+	elems, err := scanLinearArray(src, []byte{','}, "UUID")
+	if err != nil {
+		return err
+	}
+	if *a != nil && len(elems) == 0 {
+		*a = (*a)[:0]
+	} else {
+		b := make(UUID, len(elems))
+		for i, v := range elems {
+			if v == nil {
+				return fmt.Errorf("database: parsing array element index %d: cannot convert nil to uuid", i)
+			}
+			if b[i], err = uuid.ParseBytes(v); err != nil {
+				return fmt.Errorf("database: parsing array element index %d: %v", i, err)
+			}
+		}
+		*a = b
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (a UUID) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	if n := len(a); n > 0 {
+		// There will be at least two curly brackets, 36*N bytes of values,
+		// and N-1 bytes of delimiters.
+		b := make([]byte, 1, 1+37*n)
+		b[0] = '{'
+
+		b = append(b, a[0].String()...)
+		for i := 1; i < n; i++ {
+			b = append(b, ',')
+			b = append(b, a[i].String()...)
+		}
+
+		return string(append(b, '}')), nil
+	}
+
+	return "{}", nil
+}
+
+// =============================================================================
+
+// Time represents a one-dimensional array of the PostgreSQL timestamptz
+// type. Values of the timestamp type are read as UTC.
+type Time []time.Time
+
+// Scan implements the sql.Scanner interface.
+func (a *Time) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return a.scanBytes(src)
+	case string:
+		return a.scanBytes([]byte(src))
+	case nil:
+		*a = nil
+		return nil
+	}
+
+	return fmt.Errorf("database: cannot convert %T to Time", src)
+}
+
+func (a *Time) scanBytes(src []byte) error {
+	elems, err := scanLinearArray(src, []byte{','}, "Time")
+	if err != nil {
+		return err
+	}
+	if *a != nil && len(elems) == 0 {
+		*a = (*a)[:0]
+	} else {
+		b := make(Time, len(elems))
+		for i, v := range elems {
+			if v == nil {
+				return fmt.Errorf("database: parsing array element index %d: cannot convert nil to time", i)
+			}
+			if b[i], err = parseTimestamp(v); err != nil {
+				return fmt.Errorf("database: parsing array element index %d: %v", i, err)
+			}
+		}
+		*a = b
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (a Time) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	if n := len(a); n > 0 {
+		// There will be at least two curly brackets, 2*N bytes of quotes,
+		// and N-1 bytes of delimiters.
+		b := make([]byte, 1, 1+3*n)
+		b[0] = '{'
+
+		b = appendArrayQuotedBytes(b, formatTS(a[0]))
+		for i := 1; i < n; i++ {
+			b = append(b, ',')
+			b = appendArrayQuotedBytes(b, formatTS(a[i]))
+		}
+
+		return string(append(b, '}')), nil
+	}
+
+	return "{}", nil
+}
+
+// timestampLayouts are the text formats of timestamps with the ISO
+// DateStyle, from the longest time zone offset to none at all.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseTimestamp parses a timestamp in Postgres' text format, the
+// opposite of formatTS.
+func parseTimestamp(src []byte) (time.Time, error) {
+	switch string(src) {
+	case "infinity":
+		if infinityTSEnabled {
+			return infinityTSPositive, nil
+		}
+		return time.Time{}, fmt.Errorf("infinity timestamp not enabled")
+	case "-infinity":
+		if infinityTSEnabled {
+			return infinityTSNegative, nil
+		}
+		return time.Time{}, fmt.Errorf("-infinity timestamp not enabled")
+	}
+
+	s, bc := bytes.CutSuffix(src, []byte(" BC"))
+
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, string(s))
+		if err != nil {
+			continue
+		}
+
+		// Go counts 1 BC as year 0, 2 BC as year -1 and so on.
+		if bc {
+			t = t.AddDate(1-2*t.Year(), 0, 0)
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as a timestamp", src)
+}
+
+// =============================================================================
+
+// numericPattern matches the text format of the PostgreSQL numeric type.
+var numericPattern = regexp.MustCompile(`^([+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?|NaN|[+-]?Infinity)$`)
+
+// Numeric represents a one-dimensional array of the PostgreSQL numeric
+// type. Values are kept as decimal strings so no precision is lost, the
+// same way money amounts are stored.
+type Numeric []string
+
+// Scan implements the sql.Scanner interface.
+func (a *Numeric) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return a.scanBytes(src)
+	case string:
+		return a.scanBytes([]byte(src))
+	case nil:
+		*a = nil
+		return nil
+	}
+
+	return fmt.Errorf("database: cannot convert %T to Numeric", src)
+}
+
+func (a *Numeric) scanBytes(src []byte) error {
+	elems, err := scanLinearArray(src, []byte{','}, "Numeric")
+	if err != nil {
+		return err
+	}
+	if *a != nil && len(elems) == 0 {
+		*a = (*a)[:0]
+	} else {
+		b := make(Numeric, len(elems))
+		for i, v := range elems {
+			if v == nil {
+				return fmt.Errorf("database: parsing array element index %d: cannot convert nil to numeric", i)
+			}
+			b[i] = string(v)
+		}
+		*a = b
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface. Every element must be a
+// valid number, anything else is rejected before reaching the database.
+func (a Numeric) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	for i, v := range a {
+		if !numericPattern.MatchString(v) {
+			return nil, fmt.Errorf("database: array element index %d: %q is not a number", i, v)
+		}
+	}
+
+	if n := len(a); n > 0 {
+		// There will be at least two curly brackets, N bytes of values,
+		// and N-1 bytes of delimiters.
+		b := make([]byte, 1, 1+2*n)
+		b[0] = '{'
+
+		b = append(b, a[0]...)
+		for i := 1; i < n; i++ {
+			b = append(b, ',')
+			b = append(b, a[i]...)
+		}
+
+		return string(append(b, '}')), nil
+	}
+
+	return "{}", nil
+}
+
+// =============================================================================
+
+// JSONB represents a one-dimensional array of the PostgreSQL jsonb type. A
+// NULL element is read as a nil document and a nil document is written as
+// NULL.
+type JSONB []json.RawMessage
+
+// Scan implements the sql.Scanner interface.
+func (a *JSONB) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return a.scanBytes(src)
+	case string:
+		return a.scanBytes([]byte(src))
+	case nil:
+		*a = nil
+		return nil
+	}
+
+	return fmt.Errorf("database: cannot convert %T to JSONB", src)
+}
+
+func (a *JSONB) scanBytes(src []byte) error {
+	elems, err := scanLinearArray(src, []byte{','}, "JSONB")
+	if err != nil {
+		return err
+	}
+	if *a != nil && len(elems) == 0 {
+		*a = (*a)[:0]
+	} else {
+		b := make(JSONB, len(elems))
+		for i, v := range elems {
+			if v != nil {
+				b[i] = bytes.Clone(v)
+			}
+		}
+		*a = b
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (a JSONB) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	if n := len(a); n > 0 {
+		// There will be at least two curly brackets, 2*N bytes of quotes,
+		// and N-1 bytes of delimiters.
+		b := make([]byte, 1, 1+3*n)
+		b[0] = '{'
+
+		for i, v := range a {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			if v == nil {
+				b = append(b, "NULL"...)
+				continue
+			}
+			b = appendArrayQuotedBytes(b, v)
+		}
+
+		return string(append(b, '}')), nil
+	}
+
+	return "{}", nil
+}
+
+// =============================================================================
+
+// Slice represents a one-dimensional array of any element type that can be
+// written through the driver.Valuer interface and read through the
+// sql.Scanner interface of its pointer, like uuid.UUID or sql.NullString.
+// Elements are converted in the text format of the array.
+type Slice[T any] []T
+
+// Scan implements the sql.Scanner interface.
+func (a *Slice[T]) Scan(src any) error {
+	return Generic{(*[]T)(a)}.Scan(src)
+}
+
+// Value implements the driver.Valuer interface.
+func (a Slice[T]) Value() (driver.Value, error) {
+	return Generic{[]T(a)}.Value()
+}
@@ -0,0 +1,218 @@
+package sqldb
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"service/business/sdk/sqldb/dbarray"
+	"strings"
+	"time"
+)
+
+// columnPattern matches the column names a filter accepts, optionally
+// qualified by a table alias. Anything else would be written into the
+// statement as is, so it's rejected.
+var columnPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// likeEscaper escapes the wildcards of a LIKE pattern so user input is
+// matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Filter composes the conditions of a WHERE clause from the fields of a
+// query filter. Values are never written into the statement, every one of
+// them is bound to a named parameter added to the data map, and column
+// names must be plain identifiers.
+//
+// The predicates skip a nil value, including a nil pointer, so the
+// pointer fields of a query filter can be passed as they are. Non nil
+// pointers are dereferenced and times are converted to UTC.
+type Filter struct {
+	data  map[string]any
+	conds []string
+}
+
+// NewFilter constructs a filter adding its parameters to the data map,
+// which is the one given to the statement.
+func NewFilter(data map[string]any) *Filter {
+	if data == nil {
+		data = make(map[string]any)
+	}
+
+	return &Filter{
+		data: data,
+	}
+}
+
+// Eq adds the condition that the column equals the value.
+func (f *Filter) Eq(column string, value any) {
+	v, ok := deref(value)
+	if !ok {
+		return
+	}
+
+	f.add("%s = :%s", column, column, v)
+}
+
+// Like adds the condition that the column contains the value, matching
+// case. Wildcards in the value are matched literally.
+func (f *Filter) Like(column string, value any) {
+	f.like("LIKE", column, value)
+}
+
+// ILike adds the condition that the column contains the value, ignoring
+// case. Wildcards in the value are matched literally.
+func (f *Filter) ILike(column string, value any) {
+	f.like("ILIKE", column, value)
+}
+
+func (f *Filter) like(op string, column string, value any) {
+	v, ok := deref(value)
+	if !ok {
+		return
+	}
+
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.String {
+			panic(fmt.Sprintf("sqldb: %s on column %q needs a string, got %T", op, column, v))
+		}
+		s = rv.String()
+	}
+
+	f.add(`%s `+op+` :%s ESCAPE '\'`, column, column, "%"+likeEscaper.Replace(s)+"%")
+}
+
+// Range adds the conditions that the column is between from and to,
+// inclusive. Either bound can be nil to leave that side open.
+func (f *Filter) Range(column string, from any, to any) {
+	if v, ok := deref(from); ok {
+		f.add("%s >= :%s", column, "start_"+column, v)
+	}
+
+	if v, ok := deref(to); ok {
+		f.add("%s <= :%s", column, "end_"+column, v)
+	}
+}
+
+// In adds the condition that the column equals one of the values, which
+// must be a slice. An empty slice matches nothing.
+func (f *Filter) In(column string, values any) {
+	arr, ok := array(column, values)
+	if !ok {
+		return
+	}
+
+	f.add("%s = ANY(:%s)", column, column, arr)
+}
+
+// ArrayContains adds the condition that the array column contains every
+// one of the values, which must be a slice.
+func (f *Filter) ArrayContains(column string, values any) {
+	arr, ok := array(column, values)
+	if !ok {
+		return
+	}
+
+	f.add("%s @> :%s", column, column, arr)
+}
+
+// Cond adds a condition as it's written. It's meant for fixed conditions
+// like "date_deleted IS NULL", so it must never be built from input. Any
+// parameter it uses must be set in the data map by the caller.
+func (f *Filter) Cond(cond string) {
+	f.conds = append(f.conds, cond)
+}
+
+// Data returns the data map holding the parameters of the conditions.
+func (f *Filter) Data() map[string]any {
+	return f.data
+}
+
+// Clause returns the WHERE clause joining the conditions, or an empty
+// string when there are none.
+func (f *Filter) Clause() string {
+	if len(f.conds) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(f.conds, " AND ")
+}
+
+// Apply writes the WHERE clause into the statement being built.
+func (f *Filter) Apply(buf *bytes.Buffer) {
+	buf.WriteString(f.Clause())
+}
+
+// add binds the value to a parameter named after the column and adds the
+// condition. A number is appended to the name when the data map already
+// holds it, like when a column is used twice.
+func (f *Filter) add(format string, column string, name string, value any) {
+	if !columnPattern.MatchString(column) {
+		panic(fmt.Sprintf("sqldb: invalid filter column %q", column))
+	}
+
+	name = strings.ReplaceAll(name, ".", "_")
+	if _, exists := f.data[name]; exists {
+		i := 2
+		for ; ; i++ {
+			if _, exists := f.data[fmt.Sprintf("%s_%d", name, i)]; !exists {
+				break
+			}
+		}
+		name = fmt.Sprintf("%s_%d", name, i)
+	}
+
+	f.data[name] = value
+	f.conds = append(f.conds, fmt.Sprintf(format, column, name))
+}
+
+// =============================================================================
+
+// deref returns the value a filter field holds and false when it holds
+// none.
+func deref(value any) (any, bool) {
+	if value == nil {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+		value = rv.Elem().Interface()
+	}
+
+	if t, ok := value.(time.Time); ok {
+		return t.UTC(), true
+	}
+
+	return value, true
+}
+
+// array returns the slice as an array parameter and false when the slice
+// is nil.
+func array(column string, values any) (any, bool) {
+	v, ok := deref(values)
+	if !ok {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("sqldb: filter on column %q needs a slice, got %T", column, v))
+	}
+
+	if rv.IsNil() {
+		return nil, false
+	}
+
+	return dbarray.Array(v), true
+}
@@ -0,0 +1,142 @@
+package sqldb
+
+import (
+	"service/business/sdk/sqldb/dbarray"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func Test_Filter(t *testing.T) {
+	id := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+	name := `Bill'; DROP TABLE users; --`
+	since := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*3600))
+
+	var noID *uuid.UUID
+	var noTime *time.Time
+
+	tests := []struct {
+		name   string
+		seed   map[string]any
+		build  func(f *Filter)
+		clause string
+		data   map[string]any
+	}{
+		{
+			name:   "empty",
+			build:  func(f *Filter) {},
+			clause: "",
+			data:   map[string]any{},
+		},
+		{
+			name: "nil",
+			build: func(f *Filter) {
+				f.Eq("user_id", noID)
+				f.Like("name", nil)
+				f.ILike("name", nil)
+				f.Range("date_created", noTime, nil)
+				f.In("user_id", []uuid.UUID(nil))
+			},
+			clause: "",
+			data:   map[string]any{},
+		},
+		{
+			name: "eq",
+			build: func(f *Filter) {
+				f.Eq("user_id", &id)
+				f.Eq("u.quantity", 10)
+			},
+			clause: " WHERE user_id = :user_id AND u.quantity = :u_quantity",
+			data:   map[string]any{"user_id": id, "u_quantity": 10},
+		},
+		{
+			name: "ilike",
+			build: func(f *Filter) {
+				f.ILike("name", &name)
+				f.ILike("email", "50%_off\\")
+			},
+			clause: ` WHERE name ILIKE :name ESCAPE '\' AND email ILIKE :email ESCAPE '\'`,
+			data:   map[string]any{"name": "%" + name + "%", "email": `%50\%\_off\\%`},
+		},
+		{
+			name: "like",
+			build: func(f *Filter) {
+				f.Like("name", &name)
+				f.Like("obj_name", "100%")
+			},
+			clause: ` WHERE name LIKE :name ESCAPE '\' AND obj_name LIKE :obj_name ESCAPE '\'`,
+			data:   map[string]any{"name": "%" + name + "%", "obj_name": `%100\%%`},
+		},
+		{
+			name: "range",
+			build: func(f *Filter) {
+				f.Range("date_created", &since, nil)
+				f.Range("quantity", 1, 5)
+			},
+			clause: " WHERE date_created >= :start_date_created AND quantity >= :start_quantity AND quantity <= :end_quantity",
+			data:   map[string]any{"start_date_created": since.UTC(), "start_quantity": 1, "end_quantity": 5},
+		},
+		{
+			name: "arrays",
+			build: func(f *Filter) {
+				f.In("user_id", []uuid.UUID{id})
+				f.ArrayContains("roles", []string{"ADMIN"})
+				f.Cond("date_deleted IS NULL")
+			},
+			clause: " WHERE user_id = ANY(:user_id) AND roles @> :roles AND date_deleted IS NULL",
+			data:   map[string]any{"user_id": dbarray.Array([]uuid.UUID{id}), "roles": dbarray.Array([]string{"ADMIN"})},
+		},
+		{
+			name: "collision",
+			seed: map[string]any{"offset": 0, "offset_2": 0},
+			build: func(f *Filter) {
+				f.Eq("name", "a")
+				f.Eq("name", "b")
+				f.Eq("offset", 1)
+			},
+			clause: " WHERE name = :name AND name = :name_2 AND offset = :offset_3",
+			data:   map[string]any{"name": "a", "name_2": "b", "offset": 0, "offset_2": 0, "offset_3": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]any{}
+			for k, v := range tt.seed {
+				data[k] = v
+			}
+
+			f := NewFilter(data)
+			tt.build(f)
+
+			if diff := cmp.Diff(f.Clause(), tt.clause); diff != "" {
+				t.Fatalf("clause: %s", diff)
+			}
+
+			if diff := cmp.Diff(f.Data(), tt.data); diff != "" {
+				t.Fatalf("data: %s", diff)
+			}
+
+			if strings.Contains(f.Clause(), "DROP") {
+				t.Fatalf("value reached the statement: %s", f.Clause())
+			}
+		})
+	}
+}
+
+func Test_FilterColumn(t *testing.T) {
+	for _, column := range []string{"name; DROP TABLE users", "name = name OR 1", "Name", `"name"`, "a.b.c", ""} {
+		t.Run(column, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected column %q to be rejected", column)
+				}
+			}()
+
+			NewFilter(nil).Eq(column, 1)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"service/foundation/logger"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LeaderConfig is the required properties to take part in an election.
+// Instances that aren't leading try to take over every RetryInterval and
+// the leader checks it still holds the lock every CheckInterval.
+type LeaderConfig struct {
+	Name          string
+	RetryInterval time.Duration
+	CheckInterval time.Duration
+}
+
+// Leader elects a single instance among the ones sharing the database by
+// holding a session advisory lock named after the election. Leadership is
+// lost when the lock's connection is, and another instance takes over on
+// its next retry.
+type Leader struct {
+	log     *logger.Logger
+	db      *sqlx.DB
+	cfg     LeaderConfig
+	leading atomic.Bool
+}
+
+// NewLeader constructs a leader for the election. Nothing happens until
+// Run is called.
+func NewLeader(log *logger.Logger, db *sqlx.DB, cfg LeaderConfig) *Leader {
+	return &Leader{
+		log: log,
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// IsLeader reports whether this instance is currently leading.
+func (l *Leader) IsLeader() bool {
+	return l.leading.Load()
+}
+
+// Run takes part in the election until the context is cancelled. The start
+// function is called when this instance becomes the leader with a context
+// that is cancelled as soon as the leadership is lost, and the stop
+// function is called once it has been.
+func (l *Leader) Run(ctx context.Context, start func(ctx context.Context), stop func(ctx context.Context)) {
+	l.log.Info(ctx, "leader", "status", "started", "name", l.cfg.Name)
+	defer l.log.Info(ctx, "leader", "status", "stopped", "name", l.cfg.Name)
+
+	for {
+		lock, err := TryLock(ctx, l.db, l.cfg.Name)
+		switch {
+		case err == nil:
+			l.lead(ctx, lock, start, stop)
+
+		case errors.Is(err, ErrLockNotAcquired):
+			// Another instance is leading.
+
+		case ctx.Err() == nil:
+			l.log.Error(ctx, "leader", "name", l.cfg.Name, "ERROR", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.cfg.RetryInterval):
+		}
+	}
+}
+
+// lead runs the leadership until the lock is lost or the context is
+// cancelled.
+func (l *Leader) lead(ctx context.Context, lock *Lock, start func(ctx context.Context), stop func(ctx context.Context)) {
+	l.leading.Store(true)
+	l.log.Info(ctx, "leader", "status", "leadership acquired", "name", l.cfg.Name)
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	start(leadCtx)
+
+	ticker := time.NewTicker(l.cfg.CheckInterval)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+
+		case <-ticker.C:
+			if err := lock.Check(ctx); err != nil {
+				l.log.Error(ctx, "leader", "name", l.cfg.Name, "ERROR", err)
+				break loop
+			}
+		}
+	}
+
+	cancel()
+	l.leading.Store(false)
+	l.log.Info(ctx, "leader", "status", "leadership lost", "name", l.cfg.Name)
+
+	// The work must be stopped before the lock is released so two
+	// instances never run it at the same time.
+	ctx = context.WithoutCancel(ctx)
+	stop(ctx)
+
+	ctx, release := context.WithTimeout(ctx, 5*time.Second)
+	defer release()
+
+	if err := lock.Release(ctx); err != nil {
+		l.log.Error(ctx, "leader", "name", l.cfg.Name, "ERROR", err)
+	}
+}
@@ -0,0 +1,171 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service/foundation/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+// A lost connection is retried after a delay doubling from minBackoff up to
+// maxBackoff, and back to minBackoff once a connection is made.
+const (
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 30 * time.Second
+)
+
+// Notification is a payload sent to a channel with NOTIFY. A notification
+// with Reconnected set has no payload and tells the receiver the listener
+// reconnected to the channel, so notifications sent while it was down are
+// lost and anything derived from them should be refreshed.
+type Notification struct {
+	Channel     string
+	Payload     string
+	Reconnected bool
+}
+
+// Listener holds a dedicated connection listening on a set of channels.
+// Postgres only delivers notifications to the connection that issued the
+// LISTEN, so the connection is kept out of the pool and re-established,
+// along with its LISTENs, whenever it's lost.
+type Listener struct {
+	log      *logger.Logger
+	dsn      string
+	channels []string
+	ch       chan Notification
+}
+
+// NewListener constructs a listener for the channels. Nothing happens
+// until Run is called.
+func NewListener(log *logger.Logger, cfg Config, channels ...string) (*Listener, error) {
+	connStr, err := dsn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	l := Listener{
+		log:      log,
+		dsn:      connStr,
+		channels: channels,
+		ch:       make(chan Notification, 64),
+	}
+
+	return &l, nil
+}
+
+// Notifications returns the channel the notifications are delivered on. It
+// is closed when Run returns.
+func (l *Listener) Notifications() <-chan Notification {
+	return l.ch
+}
+
+// Run listens until the context is cancelled, reconnecting when the
+// connection is lost.
+func (l *Listener) Run(ctx context.Context) {
+	defer close(l.ch)
+
+	backoff := minBackoff
+	connected := false
+
+	for {
+		err := l.listen(ctx, connected, func() {
+			connected = true
+			backoff = minBackoff
+		})
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.log.Error(ctx, "listener", "channels", l.channels, "retry", backoff, "ERROR", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// listen connects, issues the LISTENs and delivers notifications until the
+// connection fails or the context is cancelled.
+func (l *Listener) listen(ctx context.Context, reconnect bool, onConnect func()) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	for _, channel := range l.channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("listen[%s]: %w", channel, err)
+		}
+	}
+
+	onConnect()
+	l.log.Info(ctx, "listener", "status", "listening", "channels", l.channels)
+
+	if reconnect {
+		for _, channel := range l.channels {
+			if !l.deliver(ctx, Notification{Channel: channel, Reconnected: true}) {
+				return ctx.Err()
+			}
+		}
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait: %w", err)
+		}
+
+		if !l.deliver(ctx, Notification{Channel: n.Channel, Payload: n.Payload}) {
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *Listener) deliver(ctx context.Context, n Notification) bool {
+	select {
+	case l.ch <- n:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// =============================================================================
+
+// maxPayload is the largest payload Postgres accepts for a notification.
+const maxPayload = 7999
+
+// ErrPayloadTooLarge is returned when a notification payload is larger than
+// Postgres allows.
+var ErrPayloadTooLarge = errors.New("notification payload too large")
+
+// Notify sends the payload to the channel. When the database is a
+// transaction the notification is only delivered if it commits, so
+// listeners never hear about work that was rolled back.
+func Notify(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, channel string, payload string) error {
+	if len(payload) > maxPayload {
+		return ErrPayloadTooLarge
+	}
+
+	const q = `SELECT pg_notify(:channel, :payload)`
+
+	data := struct {
+		Channel string `db:"channel"`
+		Payload string `db:"payload"`
+	}{
+		Channel: channel,
+		Payload: payload,
+	}
+
+	return NamedExecContext(ctx, log, db, q, data)
+}
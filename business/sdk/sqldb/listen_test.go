@@ -0,0 +1,142 @@
+package sqldb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/unitest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Listen(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Listen")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l, err := sqldb.NewListener(db.Log, db.Config, "orders")
+	if err != nil {
+		t.Fatalf("Constructing the listener: %s", err)
+	}
+	go l.Run(ctx)
+
+	// Wait for the LISTEN to be in place so no notification is missed.
+	if err := waitListening(ctx, db, "orders"); err != nil {
+		t.Fatalf("Waiting for the listener: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, notify(db, l), "notify")
+}
+
+// =============================================================================
+
+func notify(db *dbtest.Database, l *sqldb.Listener) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "commit",
+			ExpResp: sqldb.Notification{Channel: "orders", Payload: "committed"},
+			ExcFunc: func(ctx context.Context) any {
+				tx, err := sqldb.NewBeginner(db.DB).Begin(ctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				ec, err := sqldb.GetExtContext(tx)
+				if err != nil {
+					return err
+				}
+
+				if err := sqldb.Notify(ctx, db.Log, ec, "orders", "committed"); err != nil {
+					return err
+				}
+
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				return receive(ctx, l)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "rollback",
+			ExpResp: sqldb.Notification{Channel: "orders", Payload: "after"},
+			ExcFunc: func(ctx context.Context) any {
+				tx, err := sqldb.NewBeginner(db.DB).Begin(ctx)
+				if err != nil {
+					return err
+				}
+
+				ec, err := sqldb.GetExtContext(tx)
+				if err != nil {
+					return err
+				}
+
+				if err := sqldb.Notify(ctx, db.Log, ec, "orders", "rolledback"); err != nil {
+					return err
+				}
+
+				if err := tx.Rollback(); err != nil {
+					return err
+				}
+
+				if err := sqldb.Notify(ctx, db.Log, db.DB, "orders", "after"); err != nil {
+					return err
+				}
+
+				return receive(ctx, l)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func receive(ctx context.Context, l *sqldb.Listener) any {
+	select {
+	case n := <-l.Notifications():
+		return n
+	case <-time.After(5 * time.Second):
+		return fmt.Errorf("no notification received")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func waitListening(ctx context.Context, db *dbtest.Database, channel string) error {
+	const q = `SELECT count(1) AS count FROM pg_stat_activity WHERE query = :query`
+
+	data := map[string]any{"query": fmt.Sprintf(`LISTEN "%s"`, channel)}
+
+	for range 50 {
+		var row struct {
+			Count int `db:"count"`
+		}
+		if err := sqldb.NamedQueryStruct(ctx, db.Log, db.DB, q, data, &row); err != nil {
+			return err
+		}
+
+		if row.Count > 0 {
+			return nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return fmt.Errorf("listener never started")
+}
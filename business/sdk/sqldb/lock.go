@@ -0,0 +1,209 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"service/foundation/logger"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrLockNotAcquired is returned when a lock is held by another session.
+var ErrLockNotAcquired = errors.New("lock held by another session")
+
+// LockKey returns the advisory lock key for the name. Every instance
+// hashes the same name to the same key, so names can be used in place of
+// coordinating numbers.
+func LockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+
+	return int64(h.Sum64())
+}
+
+// =============================================================================
+
+// Lock is a session advisory lock. Postgres ties the lock to the
+// connection that took it, so the lock keeps a connection out of the pool
+// until it's released. If that connection is lost, so is the lock.
+type Lock struct {
+	name string
+	key  int64
+	conn *sqlx.Conn
+}
+
+// TryLock takes the named lock if it's free and returns
+// ErrLockNotAcquired otherwise.
+func TryLock(ctx context.Context, db *sqlx.DB, name string) (*Lock, error) {
+	return lock(ctx, db, name, `SELECT pg_try_advisory_lock($1)`, false)
+}
+
+// AcquireLock waits for the named lock until it's free or the context is
+// cancelled. The wait isn't bounded by the statement timeout of the pool.
+func AcquireLock(ctx context.Context, db *sqlx.DB, name string) (*Lock, error) {
+	return lock(ctx, db, name, `SELECT true FROM pg_advisory_lock($1)`, true)
+}
+
+func lock(ctx context.Context, db *sqlx.DB, name string, query string, wait bool) (*Lock, error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connx: %w", err)
+	}
+
+	// There is no transaction to SET LOCAL in, so the timeout is turned off
+	// for the session and reset to the one of the pool once the lock is
+	// taken. On failure the connection is discarded along with the setting.
+	if wait {
+		if _, err := conn.ExecContext(ctx, `SET statement_timeout = 0`); err != nil {
+			discard(conn)
+			return nil, fmt.Errorf("lock[%s]: disable statement timeout: %w", name, err)
+		}
+	}
+
+	key := LockKey(name)
+
+	var acquired bool
+	if err := conn.QueryRowxContext(ctx, query, key).Scan(&acquired); err != nil {
+		discard(conn)
+		return nil, fmt.Errorf("lock[%s]: %w", name, err)
+	}
+
+	if wait {
+		if _, err := conn.ExecContext(ctx, `RESET statement_timeout`); err != nil {
+			discard(conn)
+			return nil, fmt.Errorf("lock[%s]: reset statement timeout: %w", name, err)
+		}
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, ErrLockNotAcquired
+	}
+
+	l := Lock{
+		name: name,
+		key:  key,
+		conn: conn,
+	}
+
+	return &l, nil
+}
+
+// Name returns the name of the lock.
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// Check returns an error when the lock is no longer held, like when the
+// connection holding it was lost.
+func (l *Lock) Check(ctx context.Context) error {
+	const q = `
+	SELECT EXISTS (
+		SELECT 1 FROM pg_locks
+		WHERE locktype = 'advisory' AND pid = pg_backend_pid() AND granted
+			AND classid = $1 AND objid = $2 AND objsubid = 1
+	)`
+
+	// A bigint key is stored split in two halves of the lock tag.
+	classID := uint32(uint64(l.key) >> 32)
+	objID := uint32(l.key)
+
+	var held bool
+	if err := l.conn.QueryRowxContext(ctx, q, classID, objID).Scan(&held); err != nil {
+		return fmt.Errorf("check lock[%s]: %w", l.name, err)
+	}
+
+	if !held {
+		return fmt.Errorf("check lock[%s]: lock lost", l.name)
+	}
+
+	return nil
+}
+
+// Release releases the lock and returns its connection to the pool. The
+// connection is closed instead when the unlock fails, which drops the lock
+// with the session.
+func (l *Lock) Release(ctx context.Context) error {
+	const q = `SELECT pg_advisory_unlock($1)`
+
+	var released bool
+	if err := l.conn.QueryRowxContext(ctx, q, l.key).Scan(&released); err != nil {
+		discard(l.conn)
+		return fmt.Errorf("release lock[%s]: %w", l.name, err)
+	}
+
+	if !released {
+		discard(l.conn)
+		return fmt.Errorf("release lock[%s]: lock not held", l.name)
+	}
+
+	return l.conn.Close()
+}
+
+// discard closes the connection instead of returning it to the pool, so a
+// lock it may hold doesn't outlive the caller.
+func discard(conn *sqlx.Conn) {
+	conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	conn.Close()
+}
+
+// =============================================================================
+
+// TryXactLock takes the named lock for the rest of the transaction if it's
+// free and returns ErrLockNotAcquired otherwise. The lock is released when
+// the transaction commits or rolls back, so the database must be a
+// transaction started by DBBeginner.
+func TryXactLock(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, name string) error {
+	const q = `SELECT pg_try_advisory_xact_lock(:key) AS acquired`
+
+	acquired, err := xactLock(ctx, log, db, name, q)
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		return ErrLockNotAcquired
+	}
+
+	return nil
+}
+
+// XactLock waits for the named lock until it's free or the context is
+// cancelled, and holds it for the rest of the transaction. The database
+// must be a transaction started by DBBeginner. Unlike AcquireLock, the wait
+// is bounded by the statement timeout of the transaction.
+func XactLock(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, name string) error {
+	const q = `SELECT true AS acquired FROM pg_advisory_xact_lock(:key)`
+
+	_, err := xactLock(ctx, log, db, name, q)
+	return err
+}
+
+func xactLock(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, name string, query string) (bool, error) {
+	switch db.(type) {
+	case *Tx, *sqlx.Tx:
+	default:
+		return false, fmt.Errorf("database(%T) is not a transaction, use a transaction from DBBeginner", db)
+	}
+
+	data := struct {
+		Key int64 `db:"key"`
+	}{
+		Key: LockKey(name),
+	}
+
+	var dest struct {
+		Acquired bool `db:"acquired"`
+	}
+
+	if err := NamedQueryStruct(ctx, log, db, query, data, &dest); err != nil {
+		return false, fmt.Errorf("lock[%s]: %w", name, err)
+	}
+
+	return dest.Acquired, nil
+}
@@ -0,0 +1,225 @@
+package sqldb_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/unitest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Lock(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Lock")
+
+	unitest.Run(t, sessionLock(db), "session")
+	unitest.Run(t, xactLock(db), "xact")
+	unitest.Run(t, leader(db), "leader")
+}
+
+// =============================================================================
+
+func sessionLock(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "held",
+			ExpResp: sqldb.ErrLockNotAcquired,
+			ExcFunc: func(ctx context.Context) any {
+				lock, err := sqldb.TryLock(ctx, db.DB, "session")
+				if err != nil {
+					return err
+				}
+				defer lock.Release(ctx)
+
+				_, err = sqldb.TryLock(ctx, db.DB, "session")
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "released",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				lock, err := sqldb.TryLock(ctx, db.DB, "session")
+				if err != nil {
+					return err
+				}
+
+				if err := lock.Check(ctx); err != nil {
+					return err
+				}
+
+				if err := lock.Release(ctx); err != nil {
+					return err
+				}
+
+				lock, err = sqldb.TryLock(ctx, db.DB, "session")
+				if err != nil {
+					return err
+				}
+
+				return lock.Release(ctx)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "wait",
+			ExpResp: context.DeadlineExceeded,
+			ExcFunc: func(ctx context.Context) any {
+				lock, err := sqldb.TryLock(ctx, db.DB, "session")
+				if err != nil {
+					return err
+				}
+				defer lock.Release(ctx)
+
+				waitCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+				defer cancel()
+
+				_, err = sqldb.AcquireLock(waitCtx, db.DB, "session")
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func xactLock(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "commit",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				held := func() error {
+					tx, err := sqldb.NewBeginner(db.DB).Begin(ctx)
+					if err != nil {
+						return err
+					}
+					defer tx.Rollback()
+
+					ec, err := sqldb.GetExtContext(tx)
+					if err != nil {
+						return err
+					}
+
+					if err := sqldb.XactLock(ctx, db.Log, ec, "xact"); err != nil {
+						return err
+					}
+
+					if _, err := sqldb.TryLock(ctx, db.DB, "xact"); !errors.Is(err, sqldb.ErrLockNotAcquired) {
+						return fmt.Errorf("got %v, exp %v", err, sqldb.ErrLockNotAcquired)
+					}
+
+					return tx.Commit()
+				}
+
+				if err := held(); err != nil {
+					return err
+				}
+
+				lock, err := sqldb.TryLock(ctx, db.DB, "xact")
+				if err != nil {
+					return err
+				}
+
+				return lock.Release(ctx)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "notx",
+			ExpResp: "not a transaction",
+			ExcFunc: func(ctx context.Context) any {
+				return sqldb.TryXactLock(ctx, db.Log, db.DB, "xact")
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, ok := got.(error)
+				if !ok || !strings.Contains(err.Error(), exp.(string)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func leader(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "election",
+			ExpResp: []string{"a:start", "a:stop", "b:start"},
+			ExcFunc: func(ctx context.Context) any {
+				cfg := sqldb.LeaderConfig{
+					Name:          "leader",
+					RetryInterval: 50 * time.Millisecond,
+					CheckInterval: 50 * time.Millisecond,
+				}
+
+				events := make(chan string, 10)
+				run := func(ctx context.Context, name string) *sqldb.Leader {
+					l := sqldb.NewLeader(db.Log, db.DB, cfg)
+					go l.Run(ctx,
+						func(context.Context) { events <- name + ":start" },
+						func(context.Context) { events <- name + ":stop" },
+					)
+					return l
+				}
+
+				ctxA, cancelA := context.WithCancel(ctx)
+				defer cancelA()
+				run(ctxA, "a")
+
+				var got []string
+				got = append(got, <-events)
+
+				ctxB, cancelB := context.WithCancel(ctx)
+				defer cancelB()
+				b := run(ctxB, "b")
+
+				// Give b a few tries while a is leading.
+				time.Sleep(200 * time.Millisecond)
+				if b.IsLeader() {
+					return fmt.Errorf("two leaders")
+				}
+
+				cancelA()
+				got = append(got, <-events, <-events)
+
+				return got
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+func cmpError(got any, exp any) string {
+	if exp == nil {
+		if got != nil {
+			return fmt.Sprintf("got %v, exp nil", got)
+		}
+		return ""
+	}
+
+	err, ok := got.(error)
+	if !ok || !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package sqldb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"hash/fnv"
+	"service/foundation/logger"
+	"service/foundation/otel"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SlowQueryConfig sets when a statement is reported as slow. A zero
+// Threshold turns the reporting off. Explain captures the plan of a slow
+// statement, which costs a round trip and is meant for development.
+type SlowQueryConfig struct {
+	Threshold time.Duration
+	Explain   bool
+}
+
+var slowQuery atomic.Pointer[SlowQueryConfig]
+
+// SetSlowQuery sets the slow query reporting for every helper function of
+// this package.
+func SetSlowQuery(cfg SlowQueryConfig) {
+	slowQuery.Store(&cfg)
+}
+
+// =============================================================================
+
+// latencies holds a latency histogram per query fingerprint. The expvar
+// package registers values as singletons so the map is package level.
+var latencies = expvar.NewMap("sqldb_queries")
+
+// latenciesMu serializes adding the histogram of a new fingerprint.
+var latenciesMu sync.Mutex
+
+// latencyBounds are the upper bounds of the histogram buckets. A last
+// bucket counts everything slower.
+var latencyBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+type latency struct {
+	mu        sync.Mutex
+	statement string
+	count     int64
+	total     time.Duration
+	buckets   []int64
+}
+
+func (l *latency) observe(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.count++
+	l.total += d
+
+	i := 0
+	for i < len(latencyBounds) && d > latencyBounds[i] {
+		i++
+	}
+	l.buckets[i]++
+}
+
+// String implements the expvar.Var interface.
+func (l *latency) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buckets := make(map[string]int64, len(l.buckets))
+	for i, n := range l.buckets {
+		le := "+Inf"
+		if i < len(latencyBounds) {
+			le = latencyBounds[i].String()
+		}
+		buckets[le] = n
+	}
+
+	data, _ := json.Marshal(struct {
+		Statement string           `json:"statement"`
+		Count     int64            `json:"count"`
+		TotalMS   float64          `json:"total_ms"`
+		Buckets   map[string]int64 `json:"buckets"`
+	}{
+		Statement: l.statement,
+		Count:     l.count,
+		TotalMS:   float64(l.total) / float64(time.Millisecond),
+		Buckets:   buckets,
+	})
+
+	return string(data)
+}
+
+func recordLatency(fingerprint string, statement string, d time.Duration) {
+	latenciesMu.Lock()
+	v := latencies.Get(fingerprint)
+	if v == nil {
+		v = &latency{
+			statement: statement,
+			buckets:   make([]int64, len(latencyBounds)+1),
+		}
+		latencies.Set(fingerprint, v)
+	}
+	latenciesMu.Unlock()
+
+	if l, ok := v.(*latency); ok {
+		l.observe(d)
+	}
+}
+
+// fingerprint identifies a statement by its text before any parameter is
+// bound, so every call of the same query lands in the same histogram no
+// matter the values or the layout of the source.
+func fingerprint(query string) (string, string) {
+	statement := strings.Join(strings.Fields(query), " ")
+
+	h := fnv.New64a()
+	h.Write([]byte(statement))
+
+	return fmt.Sprintf("%016x", h.Sum64()), statement
+}
+
+// =============================================================================
+
+// call tracks a single statement run by one of the helper functions.
+type call struct {
+	name   string
+	query  string
+	logged string
+	data   any
+	withIn bool
+	start  time.Time
+	span   trace.Span
+}
+
+func startCall(ctx context.Context, name string, query string, logged string, data any, withIn bool) (context.Context, *call) {
+	ctx, span := otel.AddSpan(ctx, "sqldb."+name,
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", logged),
+	)
+
+	c := call{
+		name:   name,
+		query:  query,
+		logged: logged,
+		data:   data,
+		withIn: withIn,
+		start:  time.Now(),
+		span:   span,
+	}
+
+	return ctx, &c
+}
+
+// finish records the duration of the statement and reports it when slow.
+// The database is the one the statement ran against, so the plan comes
+// from the same replica or transaction.
+func (c *call) finish(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, err error) {
+	d := time.Since(c.start)
+
+	if err != nil && !errors.Is(err, ErrDBNotFound) {
+		c.span.RecordError(err)
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	c.span.End()
+
+	fp, statement := fingerprint(c.query)
+	recordLatency(fp, statement, d)
+
+	cfg := slowQuery.Load()
+	if cfg == nil || cfg.Threshold <= 0 || d < cfg.Threshold {
+		return
+	}
+
+	args := []any{"query", c.logged, "fingerprint", fp, "duration", d}
+
+	// COPY streams its rows and has no plan to show.
+	if cfg.Explain && !strings.HasPrefix(c.query, "COPY") {
+		plan, err := explain(ctx, db, c.query, c.data, c.withIn)
+		switch err {
+		case nil:
+			args = append(args, "plan", plan)
+		default:
+			args = append(args, "plan", fmt.Sprintf("explain: %s", err))
+		}
+	}
+
+	log.Warn(ctx, "database."+c.name+": slow query", args...)
+}
+
+// explain returns the plan of the statement without running it.
+func explain(ctx context.Context, db sqlx.ExtContext, query string, data any, withIn bool) (string, error) {
+	query = "EXPLAIN " + query
+
+	var rows *sqlx.Rows
+	var err error
+
+	switch withIn {
+	case true:
+		named, args, err := sqlx.Named(query, data)
+		if err != nil {
+			return "", err
+		}
+
+		query, args, err := sqlx.In(named, args...)
+		if err != nil {
+			return "", err
+		}
+
+		rows, err = db.QueryxContext(ctx, db.Rebind(query), args...)
+		if err != nil {
+			return "", err
+		}
+
+	default:
+		rows, err = sqlx.NamedQueryContext(ctx, db, query, data)
+		if err != nil {
+			return "", err
+		}
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), rows.Err()
+}
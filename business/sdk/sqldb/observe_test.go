@@ -0,0 +1,72 @@
+package sqldb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"service/foundation/logger"
+)
+
+func Test_Fingerprint(t *testing.T) {
+	fp1, stmt := fingerprint(`
+	SELECT
+		user_id
+	FROM
+		users
+	WHERE
+		email = :email`)
+
+	fp2, _ := fingerprint("SELECT user_id FROM users WHERE email = :email")
+
+	if fp1 != fp2 {
+		t.Fatalf("expected the layout to be ignored, got %s and %s", fp1, fp2)
+	}
+
+	if stmt != "SELECT user_id FROM users WHERE email = :email" {
+		t.Fatalf("unexpected statement: %s", stmt)
+	}
+
+	if fp3, _ := fingerprint("SELECT user_id FROM users WHERE name = :name"); fp3 == fp1 {
+		t.Fatal("expected different statements to have different fingerprints")
+	}
+}
+
+func Test_SlowQuery(t *testing.T) {
+	const secret = "s3cr3t-value"
+	const query = "SELECT user_id FROM users WHERE email = :email AND department = :department"
+
+	SetSlowQuery(SlowQueryConfig{Threshold: time.Nanosecond, Explain: true})
+	defer SetSlowQuery(SlowQueryConfig{})
+
+	var buf bytes.Buffer
+	log := logger.New(&buf, logger.LevelInfo, "TEST", func(context.Context) string { return "" })
+
+	data := map[string]any{"email": secret, "department": "ITO"}
+
+	var dest []struct{}
+	NamedQuerySlice(context.Background(), log, failingDB{}, query, data, &dest)
+
+	out := buf.String()
+	if strings.Contains(out, secret) {
+		t.Fatalf("secret reached the log: %s", out)
+	}
+
+	for _, exp := range []string{"slow query", "department = 'ITO'", "explain: failing database"} {
+		if !strings.Contains(out, exp) {
+			t.Fatalf("expected %q in the log: %s", exp, out)
+		}
+	}
+
+	fp, _ := fingerprint(query)
+	l, ok := latencies.Get(fp).(*latency)
+	if !ok {
+		t.Fatalf("expected a histogram for %s", fp)
+	}
+
+	if !strings.Contains(l.String(), `"count":1`) {
+		t.Fatalf("expected one call in the histogram: %s", l.String())
+	}
+}
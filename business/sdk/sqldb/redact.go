@@ -0,0 +1,157 @@
+package sqldb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// Values longer than maxLogValue are cut when a query is logged so a large
+// document or blob doesn't flood the logs.
+const maxLogValue = 256
+
+// redactedValue replaces the value of a sensitive parameter in the logs.
+const redactedValue = "[REDACTED]"
+
+// mapper resolves parameter names the same way sqlx does when it binds a
+// struct to a named query.
+var mapper = reflectx.NewMapperFunc("db", sqlx.NameMapper)
+
+// redacted holds the parameter names that are never logged, whatever the
+// type of the data holding them.
+var redacted = struct {
+	sync.RWMutex
+	names map[string]struct{}
+}{
+	names: map[string]struct{}{
+		"email":         {},
+		"password":      {},
+		"password_hash": {},
+		"token":         {},
+		"token_hash":    {},
+	},
+}
+
+// RedactParams adds parameter names whose values are replaced when a query
+// is logged. Struct fields can also be redacted with a `log:"redact"` tag
+// next to their db tag.
+func RedactParams(names ...string) {
+	redacted.Lock()
+	defer redacted.Unlock()
+
+	for _, name := range names {
+		redacted.names[name] = struct{}{}
+	}
+}
+
+func isRedacted(name string) bool {
+	redacted.RLock()
+	defer redacted.RUnlock()
+
+	_, exists := redacted.names[name]
+	return exists
+}
+
+// logParams returns the named parameters held by the data with the
+// sensitive values replaced. False is returned when the data isn't a map
+// or a struct and the values can't be told apart.
+func logParams(data any) (map[string]any, bool) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	params := make(map[string]any)
+
+	switch v.Kind() {
+	case reflect.Map:
+		m, ok := v.Interface().(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		for name, value := range m {
+			if isRedacted(name) {
+				value = redactedValue
+			}
+			params[name] = value
+		}
+
+	case reflect.Struct:
+		for name, fi := range mapper.TypeMap(v.Type()).Names {
+			fv := reflectx.FieldByIndexesReadOnly(v, fi.Index)
+			if !fv.CanInterface() {
+				continue
+			}
+
+			value := fv.Interface()
+			if fi.Field.Tag.Get("log") == "redact" || isRedacted(name) {
+				value = redactedValue
+			}
+			params[name] = value
+		}
+
+	default:
+		return nil, false
+	}
+
+	return params, true
+}
+
+// queryString provides a pretty print version of the query and parameters
+// with the sensitive values redacted and the large ones truncated.
+func queryString(query string, args any) string {
+	params, ok := logParams(args)
+	if !ok {
+		return flatten(query)
+	}
+
+	query, values, err := sqlx.Named(query, params)
+	if err != nil {
+		return err.Error()
+	}
+
+	for _, param := range values {
+		var value string
+		switch v := param.(type) {
+		case string:
+			value = fmt.Sprintf("'%s'", truncate(v))
+		case []byte:
+			value = fmt.Sprintf("'%s'", truncate(string(v)))
+		default:
+			value = truncate(fmt.Sprintf("%v", v))
+		}
+		query = strings.Replace(query, "?", value, 1)
+	}
+
+	return flatten(query)
+}
+
+func truncate(value string) string {
+	if value == redactedValue || len(value) <= maxLogValue {
+		return value
+	}
+
+	// Cut on a rune boundary so the log line stays valid UTF-8.
+	cut := maxLogValue
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+
+	return fmt.Sprintf("%s...(%d bytes)", value[:cut], len(value))
+}
+
+func flatten(query string) string {
+	query = strings.ReplaceAll(query, "\t", "")
+	query = strings.ReplaceAll(query, "\n", " ")
+
+	return strings.Trim(query, " ")
+}
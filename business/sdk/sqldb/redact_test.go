@@ -0,0 +1,131 @@
+package sqldb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"service/foundation/logger"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func Test_Redact(t *testing.T) {
+	const secret = "s3cr3t-value"
+
+	type user struct {
+		Name   string `db:"name"`
+		Secret string `db:"api_key" log:"redact"`
+		Hash   []byte `db:"password_hash"`
+	}
+
+	type hit struct {
+		user
+		Rank float64 `db:"rank"`
+	}
+
+	RedactParams("session_id")
+
+	tests := []struct {
+		name  string
+		query string
+		data  any
+		exp   []string
+	}{
+		{
+			name:  "tag",
+			query: "UPDATE users SET name = :name, api_key = :api_key, password_hash = :password_hash",
+			data:  user{Name: "Bill", Secret: secret, Hash: []byte(secret)},
+			exp:   []string{"'Bill'", "'[REDACTED]'"},
+		},
+		{
+			name:  "embedded",
+			query: "SELECT :name, :api_key, :rank",
+			data:  &hit{user: user{Name: "Bill", Secret: secret}, Rank: 1.5},
+			exp:   []string{"'Bill'", "'[REDACTED]'", "1.5"},
+		},
+		{
+			name:  "registry",
+			query: "SELECT * FROM users WHERE email = :email AND session_id = :session_id AND name = :name",
+			data:  map[string]any{"email": secret, "session_id": secret, "name": "Bill"},
+			exp:   []string{"'Bill'", "email = '[REDACTED]'", "session_id = '[REDACTED]'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := logger.New(&buf, logger.LevelInfo, "TEST", func(context.Context) string { return "" })
+
+			ctx := context.Background()
+
+			if err := NamedExecContext(ctx, log, failingDB{}, tt.query, tt.data); err == nil {
+				t.Fatal("expected the exec to fail")
+			}
+
+			var dest []user
+			if err := NamedQuerySlice(ctx, log, failingDB{}, tt.query, tt.data, &dest); err == nil {
+				t.Fatal("expected the query to fail")
+			}
+
+			out := buf.String()
+			if strings.Contains(out, secret) {
+				t.Fatalf("secret reached the log: %s", out)
+			}
+
+			for _, exp := range tt.exp {
+				if strings.Count(out, exp) < 2 {
+					t.Fatalf("expected %q in both log lines: %s", exp, out)
+				}
+			}
+		})
+	}
+}
+
+func Test_Truncate(t *testing.T) {
+	long := strings.Repeat("é", maxLogValue)
+
+	got := queryString("SELECT :doc", map[string]any{"doc": long})
+
+	if len(got) > maxLogValue+64 {
+		t.Fatalf("expected the value to be truncated, got %d bytes", len(got))
+	}
+
+	if !strings.Contains(got, "...(512 bytes)") {
+		t.Fatalf("expected the original size in the log: %s", got)
+	}
+}
+
+// =============================================================================
+
+// failingDB fails every statement so the helpers log the query.
+type failingDB struct{}
+
+var errFailing = errors.New("failing database")
+
+func (failingDB) DriverName() string { return "pgx" }
+
+func (failingDB) Rebind(query string) string { return sqlx.Rebind(sqlx.DOLLAR, query) }
+
+func (failingDB) BindNamed(query string, arg any) (string, []any, error) {
+	return sqlx.BindNamed(sqlx.DOLLAR, query, arg)
+}
+
+func (failingDB) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errFailing
+}
+
+func (failingDB) QueryxContext(context.Context, string, ...any) (*sqlx.Rows, error) {
+	return nil, errFailing
+}
+
+func (failingDB) QueryRowxContext(context.Context, string, ...any) *sqlx.Row {
+	return nil
+}
+
+func (failingDB) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errFailing
+}
@@ -0,0 +1,75 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes for the failures that go away when the transaction is
+// run again.
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// IsRetryable reports whether the error is a serialization failure or a
+// deadlock, which means the whole transaction can be safely run again.
+func IsRetryable(err error) bool {
+	var pqerr *pgconn.PgError
+	if !errors.As(err, &pqerr) {
+		return false
+	}
+
+	switch pqerr.Code {
+	case serializationFailure, deadlockDetected:
+		return true
+	}
+
+	return false
+}
+
+// =============================================================================
+
+type retry struct {
+	needed atomic.Bool
+}
+
+// TrackRetry returns a context that remembers whether any statement run
+// under it failed with a retryable error. Handlers usually wrap those
+// errors in ways that hide the SQLSTATE, so the helper functions of this
+// package record it as they see it.
+func TrackRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryKey, &retry{})
+}
+
+// ShouldRetry reports whether a statement run under a context returned by
+// TrackRetry failed with a retryable error.
+func ShouldRetry(ctx context.Context) bool {
+	r, ok := ctx.Value(retryKey).(*retry)
+	return ok && r.needed.Load()
+}
+
+func noteRetry(ctx context.Context, err error) {
+	if !IsRetryable(err) {
+		return
+	}
+
+	if r, ok := ctx.Value(retryKey).(*retry); ok {
+		r.needed.Store(true)
+	}
+}
+
+// WithIsolation sets the isolation level of the transactions started under
+// the returned context.
+func WithIsolation(ctx context.Context, level sql.IsolationLevel) context.Context {
+	return context.WithValue(ctx, isolationKey, level)
+}
+
+func isolation(ctx context.Context) sql.IsolationLevel {
+	level, _ := ctx.Value(isolationKey).(sql.IsolationLevel)
+	return level
+}
@@ -0,0 +1,39 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Test_Retry(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		exp  bool
+	}{
+		{name: "serialization", err: &pgconn.PgError{Code: serializationFailure}, exp: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: deadlockDetected}, exp: true},
+		{name: "wrapped", err: fmt.Errorf("query: %w", &pgconn.PgError{Code: deadlockDetected}), exp: true},
+		{name: "unique", err: &pgconn.PgError{Code: uniqueViolation}, exp: false},
+		{name: "other", err: errors.New("boom"), exp: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.exp {
+				t.Fatalf("IsRetryable: got %t, exp %t", got, tt.exp)
+			}
+
+			ctx := TrackRetry(context.Background())
+			noteRetry(ctx, tt.err)
+
+			if got := ShouldRetry(ctx); got != tt.exp {
+				t.Fatalf("ShouldRetry: got %t, exp %t", got, tt.exp)
+			}
+		})
+	}
+}
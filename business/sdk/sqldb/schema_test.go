@@ -0,0 +1,169 @@
+package sqldb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/tenant"
+	"service/business/sdk/unitest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+func Test_Schemas(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Schemas")
+
+	orgs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	for _, orgID := range orgs {
+		schema := pgx.Identifier{tenant.SchemaName(orgID)}.Sanitize()
+
+		if err := sqldb.ExecContext(context.Background(), db.Log, db.DB, "CREATE SCHEMA "+schema); err != nil {
+			t.Fatalf("Creating schema: %s", err)
+		}
+
+		q := fmt.Sprintf(`CREATE TABLE %s.notes (id SERIAL, note TEXT NOT NULL)`, schema)
+		if err := sqldb.ExecContext(context.Background(), db.Log, db.DB, q); err != nil {
+			t.Fatalf("Creating table: %s", err)
+		}
+	}
+
+	// A single connection shows every schema shares the pool and gets it
+	// back with the search_path it had.
+	cfg := db.Config
+	cfg.MaxOpenConns = 1
+
+	cluster, err := sqldb.OpenCluster(sqldb.ClusterConfig{
+		Config:  cfg,
+		Schemas: tenant.Schema,
+	})
+	if err != nil {
+		t.Fatalf("Opening cluster: %s", err)
+	}
+	defer cluster.Close()
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, isolation(db, cluster, orgs), "isolation")
+}
+
+// =============================================================================
+
+func isolation(db *dbtest.Database, cluster *sqldb.Cluster, orgs []uuid.UUID) []unitest.Table {
+	bgn := sqldb.NewSchemaBeginner(cluster.Primary(), tenant.Schema)
+
+	table := []unitest.Table{
+		{
+			Name:    "statements",
+			ExpResp: [][]string{{"a"}, {"b"}},
+			ExcFunc: func(ctx context.Context) any {
+				for i, note := range []string{"a", "b"} {
+					if err := addNote(tenant.Set(ctx, orgs[i]), db, cluster, note); err != nil {
+						return err
+					}
+				}
+
+				return [][]string{
+					notes(tenant.Set(ctx, orgs[0]), db, cluster),
+					notes(tenant.Set(ctx, orgs[1]), db, cluster),
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "transactions",
+			ExpResp: [][]string{{"a", "tx-a"}, {"b"}},
+			ExcFunc: func(ctx context.Context) any {
+				ctx = tenant.Set(ctx, orgs[0])
+
+				tx, err := bgn.Begin(ctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				ec, err := sqldb.GetExtContext(tx)
+				if err != nil {
+					return err
+				}
+
+				if err := addNote(ctx, db, ec, "tx-a"); err != nil {
+					return err
+				}
+
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				return [][]string{
+					notes(ctx, db, cluster),
+					notes(tenant.Set(ctx, orgs[1]), db, cluster),
+				}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "search-path",
+			ExpResp: `"$user", public`,
+			ExcFunc: func(ctx context.Context) any {
+				if err := addNote(tenant.Set(ctx, orgs[0]), db, cluster, "c"); err != nil {
+					return err
+				}
+
+				var row struct {
+					SearchPath string `db:"search_path"`
+				}
+				if err := sqldb.QueryStruct(ctx, db.Log, cluster, `SHOW search_path`, &row); err != nil {
+					return err
+				}
+
+				if n := cluster.Primary().Stats().OpenConnections; n != 1 {
+					return fmt.Errorf("expected the schemas to share one connection, got %d", n)
+				}
+
+				return row.SearchPath
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func addNote(ctx context.Context, db *dbtest.Database, ec sqlx.ExtContext, note string) error {
+	const q = `INSERT INTO notes (note) VALUES (:note)`
+
+	return sqldb.NamedExecContext(ctx, db.Log, ec, q, map[string]any{"note": note})
+}
+
+func notes(ctx context.Context, db *dbtest.Database, ec sqlx.ExtContext) []string {
+	const q = `SELECT note FROM notes ORDER BY id`
+
+	var rows []struct {
+		Note string `db:"note"`
+	}
+	if err := sqldb.QuerySlice(ctx, db.Log, ec, q, &rows); err != nil {
+		return []string{err.Error()}
+	}
+
+	notes := make([]string, len(rows))
+	for i, row := range rows {
+		notes[i] = row.Note
+	}
+
+	return notes
+}
@@ -7,8 +7,7 @@ import (
 	"fmt"
 	"net/url"
 	"service/foundation/logger"
-
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
@@ -31,22 +30,75 @@ var (
 )
 
 // Config is the required properties to use the database.
+//
+// Connections are recycled after ConnMaxLifetime, or ConnMaxIdleTime
+// without use, so a failover or a rebalanced load balancer is picked up.
+// A zero StatementTimeout lets statements run for as long as the server
+// allows. With a TLSRootCert the server certificate is verified against
+// the CA and the host name, TLSCert and TLSKey add a client certificate
+// and must be set together.
+// A ConnectTimeout makes Open wait for the database to answer, retrying
+// until it does or the timeout passes.
 type Config struct {
-	User         string
-	Password     string
-	Host         string
-	Name         string
-	Schema       string
-	MaxIdleConns int
-	MaxOpenConns int
-	DisableTLS   bool
+	User             string
+	Password         string
+	Host             string
+	Name             string
+	Schema           string
+	MaxIdleConns     int
+	MaxOpenConns     int
+	ConnMaxLifetime  time.Duration
+	ConnMaxIdleTime  time.Duration
+	StatementTimeout time.Duration
+	ApplicationName  string
+	DisableTLS       bool
+	TLSRootCert      string
+	TLSCert          string
+	TLSKey           string
+	ConnectTimeout   time.Duration
 }
 
 // Open knows how to open a database connection based on the configuration.
 func Open(cfg Config) (*sqlx.DB, error) {
+	connStr, err := dsn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Open("pgx", connStr)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if cfg.ConnectTimeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+		defer cancel()
+
+		if err := StatusCheck(ctx, db); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("connect[%s]: %w", cfg.Host, err)
+		}
+	}
+
+	return db, nil
+}
+
+// dsn returns the connection string for the configuration.
+func dsn(cfg Config) (string, error) {
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		return "", errors.New("the TLS client certificate and key must be set together")
+	}
+
 	sslMode := "require"
-	if cfg.DisableTLS {
+	switch {
+	case cfg.DisableTLS:
 		sslMode = "disable"
+	case cfg.TLSRootCert != "":
+		sslMode = "verify-full"
 	}
 
 	q := make(url.Values)
@@ -55,6 +107,21 @@ func Open(cfg Config) (*sqlx.DB, error) {
 	if cfg.Schema != "" {
 		q.Set("search_path", cfg.Schema)
 	}
+	if cfg.ApplicationName != "" {
+		q.Set("application_name", cfg.ApplicationName)
+	}
+	if cfg.StatementTimeout > 0 {
+		q.Set("statement_timeout", strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10))
+	}
+	if !cfg.DisableTLS {
+		if cfg.TLSRootCert != "" {
+			q.Set("sslrootcert", cfg.TLSRootCert)
+		}
+		if cfg.TLSCert != "" {
+			q.Set("sslcert", cfg.TLSCert)
+			q.Set("sslkey", cfg.TLSKey)
+		}
+	}
 
 	u := url.URL{
 		Scheme:   "postgres",
@@ -64,14 +131,7 @@ func Open(cfg Config) (*sqlx.DB, error) {
 		RawQuery: q.Encode(),
 	}
 
-	db, err := sqlx.Open("pgx", u.String())
-	if err != nil {
-		return nil, err
-	}
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-
-	return db, nil
+	return u.String(), nil
 }
 
 // StatusCheck returns nil if it can successfully talk to the database. It
@@ -86,7 +146,7 @@ func StatusCheck(ctx context.Context, db *sqlx.DB) error {
 	}
 
 	for attempts := 1; ; attempts++ {
-		if err := db.Ping(); err == nil {
+		if err := db.PingContext(ctx); err == nil {
 			break
 		}
 
@@ -115,11 +175,23 @@ func ExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, qu
 }
 
 // NamedExecContext is a helper function to execute a CUD operation with
-// logging and tracing where field replacement is necessary.
+// logging and tracing where field replacement is necessary. The statement
+// is run for its side effects, so a Cluster always sends it to the primary,
+// even when it's a SELECT calling a function.
 func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any) (err error) {
 	q := queryString(query, data)
+	tgt, err := routeWrite(ctx, db)
+	if err != nil {
+		return err
+	}
+	db = tgt.db
+	ctx, c := startCall(ctx, "NamedExecContext", query, q, data, false)
 
 	defer func() {
+		c.finish(ctx, log, db, err)
+		err = tgt.done(err)
+		noteRetry(ctx, err)
+
 		if err != nil {
 			switch data.(type) {
 			case struct{}:
@@ -168,8 +240,18 @@ func NamedQuerySliceUsingIn[T any](ctx context.Context, log *logger.Logger, db s
 
 func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any, dest *[]T, withIn bool) (err error) {
 	q := queryString(query, data)
+	tgt, err := route(ctx, db, query)
+	if err != nil {
+		return err
+	}
+	db = tgt.db
+	ctx, c := startCall(ctx, "NamedQuerySlice", query, q, data, withIn)
 
 	defer func() {
+		c.finish(ctx, log, db, err)
+		err = tgt.done(err)
+		noteRetry(ctx, err)
+
 		if err != nil {
 			log.Infoc(ctx, 6, "database.NamedQuerySlice", "query", q, "ERROR", err)
 		}
@@ -241,8 +323,18 @@ func NamedQueryStructUsingIn(ctx context.Context, log *logger.Logger, db sqlx.Ex
 
 func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any, dest any, withIn bool) (err error) {
 	q := queryString(query, data)
+	tgt, err := route(ctx, db, query)
+	if err != nil {
+		return err
+	}
+	db = tgt.db
+	ctx, c := startCall(ctx, "NamedQueryStruct", query, q, data, withIn)
 
 	defer func() {
+		c.finish(ctx, log, db, err)
+		err = tgt.done(err)
+		noteRetry(ctx, err)
+
 		if err != nil {
 			log.Infoc(ctx, 6, "database.NamedQuerySlice", "query", q, "ERROR", err)
 		}
@@ -272,15 +364,16 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	}
 
 	if err != nil {
-		var pqerr *pgconn.PgError
-		if errors.As(err, &pqerr) && pqerr.Code == undefinedTable {
-			return ErrUndefinedTable
-		}
-		return err
+		return mapError(err)
 	}
 	defer rows.Close()
 
 	if !rows.Next() {
+		// Errors raised while executing the statement, like a constraint
+		// violation in an UPDATE ... RETURNING, are reported here.
+		if err := rows.Err(); err != nil {
+			return mapError(err)
+		}
 		return ErrDBNotFound
 	}
 
@@ -291,28 +384,18 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	return nil
 }
 
-// queryString provides a pretty print version of the query and parameters.
-func queryString(query string, args any) string {
-	query, params, err := sqlx.Named(query, args)
-	if err != nil {
-		return err.Error()
-	}
-
-	for _, param := range params {
-		var value string
-		switch v := param.(type) {
-		case string:
-			value = fmt.Sprintf("'%s'", v)
-		case []byte:
-			value = fmt.Sprintf("'%s'", string(v))
-		default:
-			value = fmt.Sprintf("%v", v)
+// mapError converts the postgres errors callers need to handle into the
+// errors declared by this package.
+func mapError(err error) error {
+	var pqerr *pgconn.PgError
+	if errors.As(err, &pqerr) {
+		switch pqerr.Code {
+		case undefinedTable:
+			return ErrUndefinedTable
+		case uniqueViolation:
+			return ErrDBDuplicatedEntry
 		}
-		query = strings.Replace(query, "?", value, 1)
 	}
 
-	query = strings.ReplaceAll(query, "\t", "")
-	query = strings.ReplaceAll(query, "\n", " ")
-
-	return strings.Trim(query, " ")
+	return err
 }
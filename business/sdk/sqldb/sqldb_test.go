@@ -0,0 +1,118 @@
+package sqldb
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_DSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		exp  url.Values
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{DisableTLS: true, TLSRootCert: "/certs/ca.pem"},
+			exp:  url.Values{"sslmode": {"disable"}, "timezone": {"utc"}},
+		},
+		{
+			name: "require",
+			cfg:  Config{},
+			exp:  url.Values{"sslmode": {"require"}, "timezone": {"utc"}},
+		},
+		{
+			name: "verify",
+			cfg: Config{
+				TLSRootCert:      "/certs/ca.pem",
+				TLSCert:          "/certs/client.pem",
+				TLSKey:           "/certs/client.key",
+				ApplicationName:  "sales",
+				StatementTimeout: 1500 * time.Millisecond,
+			},
+			exp: url.Values{
+				"sslmode":           {"verify-full"},
+				"sslrootcert":       {"/certs/ca.pem"},
+				"sslcert":           {"/certs/client.pem"},
+				"sslkey":            {"/certs/client.key"},
+				"application_name":  {"sales"},
+				"statement_timeout": {"1500"},
+				"timezone":          {"utc"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connStr, err := dsn(tt.cfg)
+			if err != nil {
+				t.Fatalf("building dsn: %s", err)
+			}
+
+			u, err := url.Parse(connStr)
+			if err != nil {
+				t.Fatalf("parsing dsn: %s", err)
+			}
+
+			if diff := cmp.Diff(u.Query(), tt.exp); diff != "" {
+				t.Fatalf("query: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_DSNClientCert(t *testing.T) {
+	for _, cfg := range []Config{{TLSCert: "/certs/client.pem"}, {TLSKey: "/certs/client.key"}} {
+		if _, err := dsn(cfg); err == nil {
+			t.Fatalf("expected an error for cert[%s] key[%s]", cfg.TLSCert, cfg.TLSKey)
+		}
+	}
+}
+
+func Test_OpenConnectTimeout(t *testing.T) {
+	// Take a free port and close it so nothing is listening there.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %s", err)
+	}
+	host := ln.Addr().String()
+	ln.Close()
+
+	cfg := Config{
+		User:       "postgres",
+		Password:   "postgres",
+		Host:       host,
+		Name:       "postgres",
+		DisableTLS: true,
+	}
+
+	// Without a timeout Open doesn't connect.
+	db, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("open without timeout: %s", err)
+	}
+	db.Close()
+
+	cfg.ConnectTimeout = 500 * time.Millisecond
+
+	start := time.Now()
+	db, err = Open(cfg)
+	took := time.Since(start)
+
+	if err == nil {
+		db.Close()
+		t.Fatalf("expected an error connecting to %s", host)
+	}
+
+	if took < cfg.ConnectTimeout {
+		t.Fatalf("expected Open to retry for %v, gave up after %v", cfg.ConnectTimeout, took)
+	}
+
+	if took > 5*cfg.ConnectTimeout {
+		t.Fatalf("expected Open to give up after %v, took %v", cfg.ConnectTimeout, took)
+	}
+}
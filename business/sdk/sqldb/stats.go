@@ -0,0 +1,65 @@
+package sqldb
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// pools holds the statistics of every connection pool published with
+// PublishStats. The expvar package registers values as singletons so the
+// map is package level.
+var pools = expvar.NewMap("sqldb_pools")
+
+// PoolStats is the state of a connection pool. A growing WaitCount or
+// WaitDuration means requests are queuing for a connection and the pool
+// is too small for the load.
+type PoolStats struct {
+	MaxOpen           int
+	Open              int
+	InUse             int
+	Idle              int
+	WaitCount         int64
+	WaitDuration      time.Duration
+	MaxIdleClosed     int64
+	MaxIdleTimeClosed int64
+	MaxLifetimeClosed int64
+}
+
+// Stats returns the current state of the connection pool.
+func Stats(db *sqlx.DB) PoolStats {
+	s := db.Stats()
+
+	return PoolStats{
+		MaxOpen:           s.MaxOpenConnections,
+		Open:              s.OpenConnections,
+		InUse:             s.InUse,
+		Idle:              s.Idle,
+		WaitCount:         s.WaitCount,
+		WaitDuration:      s.WaitDuration,
+		MaxIdleClosed:     s.MaxIdleClosed,
+		MaxIdleTimeClosed: s.MaxIdleTimeClosed,
+		MaxLifetimeClosed: s.MaxLifetimeClosed,
+	}
+}
+
+// PublishStats publishes the statistics of the connection pool as metrics
+// under the name. Publishing a name again replaces the pool behind it.
+func PublishStats(name string, db *sqlx.DB) {
+	pools.Set(name, expvar.Func(func() any {
+		s := Stats(db)
+
+		return map[string]any{
+			"max_open":             s.MaxOpen,
+			"open":                 s.Open,
+			"in_use":               s.InUse,
+			"idle":                 s.Idle,
+			"wait_count":           s.WaitCount,
+			"wait_duration_ms":     s.WaitDuration.Milliseconds(),
+			"max_idle_closed":      s.MaxIdleClosed,
+			"max_idle_time_closed": s.MaxIdleTimeClosed,
+			"max_lifetime_closed":  s.MaxLifetimeClosed,
+		}
+	}))
+}
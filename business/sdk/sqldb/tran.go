@@ -1,14 +1,17 @@
 package sqldb
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jmoiron/sqlx"
 )
 
 // Beginner represents a value that can begin a transaction.
 type Beginner interface {
-	Begin() (CommitRollbacker, error)
+	Begin(ctx context.Context) (CommitRollbacker, error)
 }
 
 // CommitRollbacker represents a value that can commit or rollback a transaction.
@@ -17,11 +20,17 @@ type CommitRollbacker interface {
 	Rollback() error
 }
 
+// SchemaFunc returns the schema a transaction should be bound to for the
+// call in the context. When false is returned the transaction uses the
+// search_path of the connection pool.
+type SchemaFunc func(ctx context.Context) (string, bool)
+
 // =============================================================================
 
 // DBBeginner implements the Beginner interface,
 type DBBeginner struct {
 	sqlxDB *sqlx.DB
+	schema SchemaFunc
 }
 
 // NewBeginner constructs a value that implements the beginner interface.
@@ -31,10 +40,174 @@ func NewBeginner(sqlxDB *sqlx.DB) *DBBeginner {
 	}
 }
 
+// NewSchemaBeginner constructs a value that implements the beginner
+// interface and binds every transaction to the schema returned by the
+// specified function using SET LOCAL search_path.
+func NewSchemaBeginner(sqlxDB *sqlx.DB, schema SchemaFunc) *DBBeginner {
+	return &DBBeginner{
+		sqlxDB: sqlxDB,
+		schema: schema,
+	}
+}
+
 // Begin implements the Beginner interface and returns a concrete value that
-// implements the CommitRollbacker interface.
-func (db *DBBeginner) Begin() (CommitRollbacker, error) {
-	return db.sqlxDB.Beginx()
+// implements the CommitRollbacker interface. When the context already holds
+// a transaction, see WithTx, a savepoint inside it is returned instead.
+func (db *DBBeginner) Begin(ctx context.Context) (CommitRollbacker, error) {
+	if tx, ok := ctx.Value(txKey).(*Tx); ok {
+		return tx.Begin(ctx)
+	}
+
+	// Whatever the transaction does, the reads that follow it in the same
+	// session must see it.
+	markWrite(ctx)
+
+	// The transaction runs on a connection of its own so the driver
+	// connection can be reached for the work database/sql doesn't support,
+	// like COPY.
+	conn, err := db.sqlxDB.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlxTx, err := conn.BeginTxx(ctx, &sql.TxOptions{Isolation: isolation(ctx)})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	tx := Tx{
+		Tx:        sqlxTx,
+		conn:      conn,
+		savepoint: new(int),
+	}
+
+	if db.schema == nil {
+		return &tx, nil
+	}
+
+	schema, ok := db.schema(ctx)
+	if !ok {
+		return &tx, nil
+	}
+
+	// SET LOCAL only lasts until the end of the transaction, so the
+	// connection goes back to the pool with its original search_path.
+	q := "SET LOCAL search_path TO " + pgx.Identifier{schema}.Sanitize()
+	if _, err := tx.ExecContext(ctx, q); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("set search_path[%s]: %w", schema, err)
+	}
+
+	return &tx, nil
+}
+
+// =============================================================================
+
+// Tx is a transaction, or a savepoint inside one, that can begin savepoints
+// of its own. A savepoint can be rolled back without losing the work done
+// before it, so a unit of work can fail on its own inside a larger one.
+// Committing a savepoint releases it and its work becomes part of the
+// enclosing transaction.
+//
+// Tx implements sqlx.ExtContext so stores use a savepoint the same way they
+// use a transaction.
+type Tx struct {
+	*sqlx.Tx
+	conn      *sqlx.Conn
+	name      string
+	savepoint *int
+	done      bool
+}
+
+// Begin implements the Beginner interface and creates a savepoint inside
+// the transaction.
+func (tx *Tx) Begin(ctx context.Context) (CommitRollbacker, error) {
+	if tx.done {
+		return nil, sql.ErrTxDone
+	}
+
+	*tx.savepoint++
+	name := fmt.Sprintf("sp_%d", *tx.savepoint)
+
+	if _, err := tx.Tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, fmt.Errorf("savepoint[%s]: %w", name, err)
+	}
+
+	sp := Tx{
+		Tx:        tx.Tx,
+		conn:      tx.conn,
+		name:      name,
+		savepoint: tx.savepoint,
+	}
+
+	return &sp, nil
+}
+
+// Commit commits the transaction or releases the savepoint.
+func (tx *Tx) Commit() error {
+	if tx.name == "" {
+		defer tx.conn.Close()
+		return tx.Tx.Commit()
+	}
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	if _, err := tx.Tx.Exec("RELEASE SAVEPOINT " + tx.name); err != nil {
+		return fmt.Errorf("release savepoint[%s]: %w", tx.name, err)
+	}
+
+	return nil
+}
+
+// Rollback rolls back the transaction or the work done since the savepoint
+// was created. Like a transaction, rolling back a savepoint that is already
+// done returns sql.ErrTxDone.
+func (tx *Tx) Rollback() error {
+	if tx.name == "" {
+		defer tx.conn.Close()
+		return tx.Tx.Rollback()
+	}
+
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+
+	if _, err := tx.Tx.Exec("ROLLBACK TO SAVEPOINT " + tx.name); err != nil {
+		return fmt.Errorf("rollback to savepoint[%s]: %w", tx.name, err)
+	}
+
+	if _, err := tx.Tx.Exec("RELEASE SAVEPOINT " + tx.name); err != nil {
+		return fmt.Errorf("release savepoint[%s]: %w", tx.name, err)
+	}
+
+	return nil
+}
+
+// WithTx returns a context that makes DBBeginner create savepoints inside
+// the transaction instead of starting new transactions. Code reached
+// through the context, like a delegate function, can join the transaction
+// with GetTx.
+func WithTx(ctx context.Context, tx CommitRollbacker) context.Context {
+	if t, ok := tx.(*Tx); ok {
+		return context.WithValue(ctx, txKey, t)
+	}
+
+	return ctx
+}
+
+// GetTx returns the transaction set in the context by WithTx.
+func GetTx(ctx context.Context) (CommitRollbacker, bool) {
+	tx, ok := ctx.Value(txKey).(*Tx)
+	if !ok {
+		return nil, false
+	}
+
+	return tx, true
 }
 
 // GetExtContext is a helper function that extracts the sqlx value
@@ -0,0 +1,160 @@
+package sqldb_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"service/business/sdk/dbtest"
+	"service/business/sdk/sqldb"
+	"service/business/sdk/unitest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Savepoint(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Savepoint")
+
+	const q = `CREATE TABLE savepoints (id SERIAL, step TEXT NOT NULL)`
+	if err := sqldb.ExecContext(context.Background(), db.Log, db.DB, q); err != nil {
+		t.Fatalf("Creating table: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, nesting(db), "nesting")
+}
+
+// =============================================================================
+
+func nesting(db *dbtest.Database) []unitest.Table {
+	bgn := sqldb.NewBeginner(db.DB)
+
+	table := []unitest.Table{
+		{
+			Name:    "independent",
+			ExpResp: []string{"outer", "kept", "kept-inner"},
+			ExcFunc: func(ctx context.Context) any {
+				tx, err := bgn.Begin(ctx)
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				if err := insert(ctx, db, tx, "outer"); err != nil {
+					return err
+				}
+
+				// With the transaction in the context, the beginner creates
+				// savepoints. A savepoint that is rolled back takes its nested
+				// work with it, even the work of savepoints already released.
+				ctx = sqldb.WithTx(ctx, tx)
+
+				dropped, err := bgn.Begin(ctx)
+				if err != nil {
+					return err
+				}
+
+				if err := insert(ctx, db, dropped, "dropped"); err != nil {
+					return err
+				}
+
+				inner, err := dropped.(sqldb.Beginner).Begin(ctx)
+				if err != nil {
+					return err
+				}
+
+				if err := insert(ctx, db, inner, "dropped-inner"); err != nil {
+					return err
+				}
+
+				if err := inner.Commit(); err != nil {
+					return err
+				}
+
+				if err := dropped.Rollback(); err != nil {
+					return err
+				}
+
+				// A failed statement only aborts the savepoint it ran in.
+				kept, err := bgn.Begin(ctx)
+				if err != nil {
+					return err
+				}
+
+				if err := insert(ctx, db, kept, "kept"); err != nil {
+					return err
+				}
+
+				failed, err := kept.(sqldb.Beginner).Begin(ctx)
+				if err != nil {
+					return err
+				}
+
+				if err := insert(ctx, db, failed, ""); err == nil {
+					return errors.New("expected the empty step to fail")
+				}
+
+				if err := failed.Rollback(); err != nil {
+					return err
+				}
+
+				if err := insert(ctx, db, kept, "kept-inner"); err != nil {
+					return err
+				}
+
+				if err := kept.Commit(); err != nil {
+					return err
+				}
+
+				if err := kept.Rollback(); !errors.Is(err, sql.ErrTxDone) {
+					return fmt.Errorf("expected rollback after commit to be done, got %v", err)
+				}
+
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				return steps(ctx, db)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func insert(ctx context.Context, db *dbtest.Database, tx sqldb.CommitRollbacker, step string) error {
+	ec, err := sqldb.GetExtContext(tx)
+	if err != nil {
+		return err
+	}
+
+	const q = `INSERT INTO savepoints (step) VALUES (NULLIF(:step, ''))`
+
+	return sqldb.NamedExecContext(ctx, db.Log, ec, q, map[string]any{"step": step})
+}
+
+func steps(ctx context.Context, db *dbtest.Database) any {
+	const q = `SELECT step FROM savepoints ORDER BY id`
+
+	var rows []struct {
+		Step string `db:"step"`
+	}
+	if err := sqldb.QuerySlice(ctx, db.Log, db.DB, q, &rows); err != nil {
+		return err
+	}
+
+	steps := make([]string, len(rows))
+	for i, row := range rows {
+		steps[i] = row.Step
+	}
+
+	return steps
+}
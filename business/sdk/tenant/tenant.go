@@ -0,0 +1,95 @@
+// Package tenant provides support for carrying the organization a call is
+// acting on behalf of through the context. Stores use it to scope every
+// query to that organization.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DefaultOrgID is the organization that owns the users that existed before
+// organizations were introduced, and any user created without a tenant.
+var DefaultOrgID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Set of error variables for tenant checks.
+var (
+	ErrCrossTenant = errors.New("data belongs to a different tenant")
+	ErrNoTenant    = errors.New("no tenant in context")
+)
+
+type ctxKey int
+
+const (
+	orgIDKey ctxKey = iota + 1
+	systemKey
+)
+
+// Set stores the organization the call is acting on behalf of.
+func Set(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, orgIDKey, orgID)
+}
+
+// Get returns the organization the call is acting on behalf of.
+func Get(ctx context.Context) (uuid.UUID, bool) {
+	v, ok := ctx.Value(orgIDKey).(uuid.UUID)
+	return v, ok
+}
+
+// System marks the call as a trusted internal one, like a background job or
+// the admin tooling, that acts across every organization. A tenant set on
+// the context later still takes precedence.
+func System(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey, true)
+}
+
+// Scope returns the organization a store must restrict the call to. The
+// bool is false for a system call, which is not restricted. A call that
+// carries neither a tenant nor the system mark fails with ErrNoTenant, so
+// a route missing the tenant middleware can't read every organization.
+func Scope(ctx context.Context) (uuid.UUID, bool, error) {
+	if orgID, ok := Get(ctx); ok {
+		return orgID, true, nil
+	}
+
+	if system, _ := ctx.Value(systemKey).(bool); system {
+		return uuid.Nil, false, nil
+	}
+
+	return uuid.Nil, false, ErrNoTenant
+}
+
+// Check validates the specified organization is the tenant in the context.
+// A system call may act on any organization.
+func Check(ctx context.Context, orgID uuid.UUID) error {
+	v, scoped, err := Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	if scoped && v != orgID {
+		return ErrCrossTenant
+	}
+
+	return nil
+}
+
+// SchemaName returns the postgres schema that holds the data for the
+// specified organization when the database runs with a schema per tenant.
+func SchemaName(orgID uuid.UUID) string {
+	return "tenant_" + strings.ReplaceAll(orgID.String(), "-", "")
+}
+
+// Schema returns the schema for the tenant in the context. It matches the
+// sqldb.SchemaFunc signature so it can bind transactions to the tenant.
+func Schema(ctx context.Context) (string, bool) {
+	orgID, ok := Get(ctx)
+	if !ok {
+		return "", false
+	}
+
+	return SchemaName(orgID), true
+}
@@ -0,0 +1,63 @@
+package tenant_test
+
+import (
+	"context"
+	"errors"
+	"service/business/sdk/tenant"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_Scope(t *testing.T) {
+	orgID := uuid.New()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		orgID  uuid.UUID
+		scoped bool
+		err    error
+	}{
+		{name: "tenant", ctx: tenant.Set(context.Background(), orgID), orgID: orgID, scoped: true},
+		{name: "system", ctx: tenant.System(context.Background())},
+		{name: "system-tenant", ctx: tenant.Set(tenant.System(context.Background()), orgID), orgID: orgID, scoped: true},
+		{name: "none", ctx: context.Background(), err: tenant.ErrNoTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, scoped, err := tenant.Scope(tt.ctx)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, exp %v", err, tt.err)
+			}
+
+			if got != tt.orgID || scoped != tt.scoped {
+				t.Fatalf("got %s scoped %t, exp %s scoped %t", got, scoped, tt.orgID, tt.scoped)
+			}
+		})
+	}
+}
+
+func Test_Check(t *testing.T) {
+	orgID := uuid.New()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+	}{
+		{name: "same", ctx: tenant.Set(context.Background(), orgID)},
+		{name: "other", ctx: tenant.Set(context.Background(), uuid.New()), err: tenant.ErrCrossTenant},
+		{name: "system", ctx: tenant.System(context.Background())},
+		{name: "none", ctx: context.Background(), err: tenant.ErrNoTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tenant.Check(tt.ctx, orgID); !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, exp %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -2,12 +2,14 @@ package unitest
 
 import (
 	"context"
+	"service/business/domain/productbus"
 	"service/business/domain/userbus"
 )
 
 // User represents an app user specified for the test.
 type User struct {
 	userbus.User
+	Products []productbus.Product
 }
 
 // SeedData represents data that was seeded for the test.
@@ -2,6 +2,7 @@ package unitest
 
 import (
 	"context"
+	"service/business/sdk/tenant"
 	"testing"
 )
 
@@ -9,7 +10,10 @@ import (
 func Run(t *testing.T, table []Table, testName string) {
 	for _, tt := range table {
 		f := func(t *testing.T) {
-			gotResp := tt.ExcFunc(context.Background())
+			// The tests call the business layer directly, like a background
+			// job, so they act across every organization unless a test sets
+			// a tenant.
+			gotResp := tt.ExcFunc(tenant.System(context.Background()))
 
 			diff := tt.CmpFunc(gotResp, tt.ExpResp)
 			if diff != "" {
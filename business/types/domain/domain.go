@@ -2,9 +2,11 @@ package domain
 
 import "fmt"
 
-// The set of roles that can be used.
+// The set of domains that can be used.
 var (
-	User = newDomain("USER")
+	User       = newDomain("USER")
+	Invitation = newDomain("INVITATION")
+	Order      = newDomain("ORDER")
 )
 
 // =============================================================================
@@ -0,0 +1,75 @@
+package money
+
+import "fmt"
+
+// The set of currencies that can be used.
+var (
+	AUD = newCurrency("AUD", 2)
+	CAD = newCurrency("CAD", 2)
+	CHF = newCurrency("CHF", 2)
+	EUR = newCurrency("EUR", 2)
+	GBP = newCurrency("GBP", 2)
+	JPY = newCurrency("JPY", 0)
+	USD = newCurrency("USD", 2)
+)
+
+// =============================================================================
+
+// Set of known currencies.
+var currencies = make(map[string]Currency)
+
+// Currency represents an ISO 4217 currency and the number of minor units
+// that make up one major unit.
+type Currency struct {
+	code   string
+	digits int
+}
+
+func newCurrency(code string, digits int) Currency {
+	c := Currency{code, digits}
+	currencies[code] = c
+	return c
+}
+
+// String returns the ISO 4217 code of the currency.
+func (c Currency) String() string {
+	return c.code
+}
+
+// Digits returns the number of decimal places used by the currency.
+func (c Currency) Digits() int {
+	return c.digits
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (c Currency) Equal(c2 Currency) bool {
+	return c.code == c2.code
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (c Currency) MarshalText() ([]byte, error) {
+	return []byte(c.code), nil
+}
+
+// =============================================================================
+
+// ParseCurrency parses the string value and returns a currency if one exists.
+func ParseCurrency(value string) (Currency, error) {
+	c, exists := currencies[value]
+	if !exists {
+		return Currency{}, fmt.Errorf("invalid currency %q", value)
+	}
+
+	return c, nil
+}
+
+// MustParseCurrency parses the string value and returns a currency if one
+// exists. If an error occurs the function panics.
+func MustParseCurrency(value string) Currency {
+	c, err := ParseCurrency(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
@@ -0,0 +1,328 @@
+// Package money represents an amount of money in a specific currency.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Set of error variables for money operations.
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrOverflow         = errors.New("amount overflow")
+)
+
+// Money represents an amount of money held as an integer number of minor
+// units, such as cents, so arithmetic is exact.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// New constructs a money value from an amount of minor units.
+func New(amount int64, currency Currency) Money {
+	return Money{amount, currency}
+}
+
+// Amount returns the amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the currency of the amount.
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsNegative reports whether the amount is less than zero.
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Decimal returns the amount as a decimal string in major units, such as
+// "10.34" for 1034 cents.
+func (m Money) Decimal() string {
+	digits := m.currency.digits
+
+	sign := ""
+	amount := m.amount
+	if amount < 0 {
+		sign = "-"
+	}
+
+	// Format the absolute value from the unsigned form so the minimum
+	// int64 value doesn't overflow when negated.
+	abs := strconv.FormatUint(absUint(amount), 10)
+	if digits == 0 {
+		return sign + abs
+	}
+
+	if len(abs) <= digits {
+		abs = strings.Repeat("0", digits-len(abs)+1) + abs
+	}
+
+	return sign + abs[:len(abs)-digits] + "." + abs[len(abs)-digits:]
+}
+
+// String returns the amount and currency, such as "10.34 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.currency.code
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (m Money) Equal(m2 Money) bool {
+	return m.amount == m2.amount && m.currency.Equal(m2.currency)
+}
+
+// Cmp compares two amounts of the same currency and returns -1, 0 or 1.
+func (m Money) Cmp(m2 Money) (int, error) {
+	if !m.currency.Equal(m2.currency) {
+		return 0, fmt.Errorf("%s and %s: %w", m.currency, m2.currency, ErrCurrencyMismatch)
+	}
+
+	switch {
+	case m.amount < m2.amount:
+		return -1, nil
+	case m.amount > m2.amount:
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// Add returns the sum of two amounts of the same currency.
+func (m Money) Add(m2 Money) (Money, error) {
+	if !m.currency.Equal(m2.currency) {
+		return Money{}, fmt.Errorf("%s and %s: %w", m.currency, m2.currency, ErrCurrencyMismatch)
+	}
+
+	sum := m.amount + m2.amount
+	if (m2.amount > 0 && sum < m.amount) || (m2.amount < 0 && sum > m.amount) {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{sum, m.currency}, nil
+}
+
+// Sub returns the difference of two amounts of the same currency.
+func (m Money) Sub(m2 Money) (Money, error) {
+	if m2.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+
+	return m.Add(Money{-m2.amount, m2.currency})
+}
+
+// Mul returns the amount multiplied by the specified quantity.
+func (m Money) Mul(quantity int64) (Money, error) {
+	if m.amount == 0 || quantity == 0 {
+		return Money{0, m.currency}, nil
+	}
+
+	product := m.amount * quantity
+	if product/quantity != m.amount || (m.amount == -1 && quantity == math.MinInt64) || (quantity == -1 && m.amount == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{product, m.currency}, nil
+}
+
+// Sum adds the specified amounts together. All amounts must be in the same
+// currency.
+func Sum(currency Currency, amounts ...Money) (Money, error) {
+	total := Money{0, currency}
+
+	for _, m := range amounts {
+		var err error
+		total, err = total.Add(m)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+// =============================================================================
+
+// Parse parses a decimal amount in major units, such as "10.34", and the
+// ISO 4217 code of its currency. The amount can't have more decimal places
+// than the currency supports unless they are zero, so a NUMERIC column
+// with a larger scale can still be read.
+func Parse(amount string, currency string) (Money, error) {
+	cur, err := ParseCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	minor, err := parseMinor(amount, cur.digits)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{minor, cur}, nil
+}
+
+// MustParse parses the decimal amount and currency and returns a money
+// value. If an error occurs the function panics.
+func MustParse(amount string, currency string) Money {
+	m, err := Parse(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+func parseMinor(amount string, digits int) (int64, error) {
+	value := amount
+
+	neg := strings.HasPrefix(value, "-")
+	if neg {
+		value = value[1:]
+	}
+
+	whole, frac, _ := strings.Cut(value, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	if len(frac) > digits {
+		if strings.Trim(frac[digits:], "0") != "" {
+			return 0, fmt.Errorf("invalid amount %q: more than %d decimal places", amount, digits)
+		}
+		frac = frac[:digits]
+	}
+
+	frac += strings.Repeat("0", digits-len(frac))
+
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", amount, ErrOverflow)
+	}
+
+	if neg {
+		minor = -minor
+	}
+
+	return minor, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func absUint(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+
+	return uint64(v)
+}
+
+// =============================================================================
+
+type jsonMoney struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string so no precision is lost
+// by clients that decode numbers as floats.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{
+		Amount:   m.Decimal(),
+		Currency: m.currency.code,
+	})
+}
+
+// UnmarshalJSON decodes an amount encoded by MarshalJSON.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var jm jsonMoney
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return err
+	}
+
+	v, err := Parse(jm.Amount, jm.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = v
+
+	return nil
+}
+
+// =============================================================================
+
+// Value implements the driver.Valuer interface. The amount is written as a
+// composite of a NUMERIC amount in major units and a currency code, such as
+// "(10.34,USD)", that can be cast to the money_amount type.
+func (m Money) Value() (driver.Value, error) {
+	return "(" + m.Decimal() + "," + m.currency.code + ")", nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts a composite value
+// written by Value. It also accepts a plain NUMERIC value when the money
+// value already holds the currency of the column, such as one constructed
+// with New(0, currency).
+func (m *Money) Scan(src any) error {
+	var value string
+	switch v := src.(type) {
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case nil:
+		return errors.New("scan: money can't be NULL")
+	default:
+		return fmt.Errorf("scan: unsupported type %T", src)
+	}
+
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		amount, currency, ok := strings.Cut(value[1:len(value)-1], ",")
+		if !ok {
+			return fmt.Errorf("scan: invalid composite %q", value)
+		}
+
+		v, err := Parse(strings.TrimSpace(amount), strings.Trim(strings.TrimSpace(currency), `"`))
+		if err != nil {
+			return fmt.Errorf("scan: %w", err)
+		}
+
+		*m = v
+
+		return nil
+	}
+
+	if m.currency.code == "" {
+		return fmt.Errorf("scan: numeric %q has no currency", value)
+	}
+
+	minor, err := parseMinor(value, m.currency.digits)
+	if err != nil {
+		return fmt.Errorf("scan: %w", err)
+	}
+
+	m.amount = minor
+
+	return nil
+}
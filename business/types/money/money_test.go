@@ -0,0 +1,226 @@
+package money_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"service/business/types/money"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		exp      money.Money
+		ok       bool
+	}{
+		{name: "cents", amount: "10.34", currency: "USD", exp: money.New(1034, money.USD), ok: true},
+		{name: "whole", amount: "10", currency: "USD", exp: money.New(1000, money.USD), ok: true},
+		{name: "one-digit", amount: "10.3", currency: "EUR", exp: money.New(1030, money.EUR), ok: true},
+		{name: "negative", amount: "-0.05", currency: "USD", exp: money.New(-5, money.USD), ok: true},
+		{name: "trailing-zeros", amount: "10.3400", currency: "USD", exp: money.New(1034, money.USD), ok: true},
+		{name: "yen", amount: "1500", currency: "JPY", exp: money.New(1500, money.JPY), ok: true},
+		{name: "yen-zero-fraction", amount: "1500.00", currency: "JPY", exp: money.New(1500, money.JPY), ok: true},
+		{name: "large", amount: "123456789012.34", currency: "USD", exp: money.New(12345678901234, money.USD), ok: true},
+		{name: "max", amount: "92233720368547758.07", currency: "USD", exp: money.New(math.MaxInt64, money.USD), ok: true},
+		{name: "extra-digit", amount: "10.345", currency: "USD"},
+		{name: "yen-fraction", amount: "1500.5", currency: "JPY"},
+		{name: "overflow", amount: "92233720368547758.08", currency: "USD"},
+		{name: "empty", amount: "", currency: "USD"},
+		{name: "no-whole", amount: ".5", currency: "USD"},
+		{name: "letters", amount: "1O.00", currency: "USD"},
+		{name: "exponent", amount: "1e3", currency: "USD"},
+		{name: "plus", amount: "+1.00", currency: "USD"},
+		{name: "currency", amount: "1.00", currency: "XXX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := money.Parse(tt.amount, tt.currency)
+
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected %q %s to parse: %s", tt.amount, tt.currency, err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected %q %s to be rejected, got %s", tt.amount, tt.currency, got)
+			case tt.ok && !got.Equal(tt.exp):
+				t.Fatalf("got %s, exp %s", got, tt.exp)
+			}
+		})
+	}
+}
+
+func Test_Decimal(t *testing.T) {
+	tests := []struct {
+		money money.Money
+		exp   string
+	}{
+		{money: money.New(1034, money.USD), exp: "10.34"},
+		{money: money.New(5, money.USD), exp: "0.05"},
+		{money: money.New(-5, money.USD), exp: "-0.05"},
+		{money: money.New(0, money.USD), exp: "0.00"},
+		{money: money.New(1500, money.JPY), exp: "1500"},
+		{money: money.New(math.MinInt64, money.USD), exp: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.exp {
+			t.Fatalf("got %q, exp %q", got, tt.exp)
+		}
+
+		// Every decimal must parse back to the same amount, except the
+		// minimum which has no positive counterpart.
+		if tt.money.Amount() == math.MinInt64 {
+			continue
+		}
+
+		back, err := money.Parse(tt.money.Decimal(), tt.money.Currency().String())
+		if err != nil {
+			t.Fatalf("parsing %q back: %s", tt.money.Decimal(), err)
+		}
+
+		if !back.Equal(tt.money) {
+			t.Fatalf("round trip: got %s, exp %s", back, tt.money)
+		}
+	}
+}
+
+func Test_Arithmetic(t *testing.T) {
+	usd := money.New(1034, money.USD)
+
+	sum, err := usd.Add(money.New(66, money.USD))
+	if err != nil || !sum.Equal(money.New(1100, money.USD)) {
+		t.Fatalf("add: got %s %v, exp 11.00 USD", sum, err)
+	}
+
+	diff, err := usd.Sub(money.New(2000, money.USD))
+	if err != nil || !diff.Equal(money.New(-966, money.USD)) {
+		t.Fatalf("sub: got %s %v, exp -9.66 USD", diff, err)
+	}
+
+	product, err := usd.Mul(3)
+	if err != nil || !product.Equal(money.New(3102, money.USD)) {
+		t.Fatalf("mul: got %s %v, exp 31.02 USD", product, err)
+	}
+
+	total, err := money.Sum(money.USD, usd, usd, money.New(-68, money.USD))
+	if err != nil || !total.Equal(money.New(2000, money.USD)) {
+		t.Fatalf("sum: got %s %v, exp 20.00 USD", total, err)
+	}
+
+	if cmp, err := usd.Cmp(money.New(1035, money.USD)); err != nil || cmp != -1 {
+		t.Fatalf("cmp: got %d %v, exp -1", cmp, err)
+	}
+}
+
+func Test_CurrencyMismatch(t *testing.T) {
+	usd := money.New(1034, money.USD)
+	eur := money.New(1034, money.EUR)
+
+	if _, err := usd.Add(eur); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("add: got %v, exp %v", err, money.ErrCurrencyMismatch)
+	}
+
+	if _, err := usd.Sub(eur); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("sub: got %v, exp %v", err, money.ErrCurrencyMismatch)
+	}
+
+	if _, err := usd.Cmp(eur); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("cmp: got %v, exp %v", err, money.ErrCurrencyMismatch)
+	}
+
+	if _, err := money.Sum(money.USD, usd, eur); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("sum: got %v, exp %v", err, money.ErrCurrencyMismatch)
+	}
+}
+
+func Test_Overflow(t *testing.T) {
+	max := money.New(math.MaxInt64, money.USD)
+	min := money.New(math.MinInt64, money.USD)
+	one := money.New(1, money.USD)
+
+	tests := []struct {
+		name string
+		fn   func() (money.Money, error)
+	}{
+		{name: "add", fn: func() (money.Money, error) { return max.Add(one) }},
+		{name: "add-negative", fn: func() (money.Money, error) { return min.Add(money.New(-1, money.USD)) }},
+		{name: "sub", fn: func() (money.Money, error) { return min.Sub(one) }},
+		{name: "sub-min", fn: func() (money.Money, error) { return one.Sub(min) }},
+		{name: "mul", fn: func() (money.Money, error) { return max.Mul(2) }},
+		{name: "mul-negative", fn: func() (money.Money, error) { return min.Mul(-1) }},
+		{name: "mul-min", fn: func() (money.Money, error) { return money.New(-1, money.USD).Mul(math.MinInt64) }},
+		{name: "sum", fn: func() (money.Money, error) { return money.Sum(money.USD, max, one) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if !errors.Is(err, money.ErrOverflow) {
+				t.Fatalf("got %s %v, exp %v", got, err, money.ErrOverflow)
+			}
+		})
+	}
+}
+
+func Test_JSON(t *testing.T) {
+	m := money.New(150000, money.JPY)
+
+	data, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal: %s", err)
+	}
+
+	if exp := `{"amount":"150000","currency":"JPY"}`; string(data) != exp {
+		t.Fatalf("got %s, exp %s", data, exp)
+	}
+
+	var got money.Money
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("unmarshal: %s", err)
+	}
+
+	if !got.Equal(m) {
+		t.Fatalf("got %s, exp %s", got, m)
+	}
+}
+
+func Test_ValueScan(t *testing.T) {
+	m := money.New(-1034, money.EUR)
+
+	v, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %s", err)
+	}
+
+	if exp := "(-10.34,EUR)"; v != exp {
+		t.Fatalf("got %v, exp %s", v, exp)
+	}
+
+	var got money.Money
+	if err := got.Scan([]byte(`(-10.3400,"EUR")`)); err != nil {
+		t.Fatalf("scan: %s", err)
+	}
+
+	if !got.Equal(m) {
+		t.Fatalf("got %s, exp %s", got, m)
+	}
+
+	numeric := money.New(0, money.JPY)
+	if err := numeric.Scan("1500"); err != nil {
+		t.Fatalf("scan numeric: %s", err)
+	}
+
+	if exp := money.New(1500, money.JPY); !numeric.Equal(exp) {
+		t.Fatalf("got %s, exp %s", numeric, exp)
+	}
+
+	for _, src := range []any{nil, "10.34", "(10.34)", "(10.345,USD)", 1.5} {
+		var m money.Money
+		if err := m.Scan(src); err == nil {
+			t.Fatalf("expected %v to be rejected, got %s", src, m)
+		}
+	}
+}
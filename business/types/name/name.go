@@ -2,8 +2,8 @@
 package name
 
 import (
-	"fmt"
-	"regexp"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Name represents a name in the system.
@@ -16,6 +16,14 @@ func (n Name) String() string {
 	return n.value
 }
 
+// Key returns a case folded form of the name for comparing and searching
+// names regardless of case and compatibility forms. Look-alike characters
+// from different scripts aren't folded, so names written in one script
+// that resemble names in another keep different keys.
+func (n Name) Key() string {
+	return foldKey(n.value)
+}
+
 // Equal provides support for the go-cmp package and testing.
 func (n Name) Equal(n2 Name) bool {
 	return n.value == n2.value
@@ -28,16 +36,10 @@ func (n Name) MarshalText() ([]byte, error) {
 
 // =============================================================================
 
-var nameRegEx = regexp.MustCompile("^[a-zA-Z0-9' -]{3,20}$")
-
 // Parse parses the string value and returns a name if the value complies
-// with the rules for a name.
+// with the default rules for a name. The value is normalized to NFC.
 func Parse(value string) (Name, error) {
-	if !nameRegEx.MatchString(value) {
-		return Name{}, fmt.Errorf("invalid name %q", value)
-	}
-
-	return Name{value}, nil
+	return DefaultRules().Parse(value)
 }
 
 // MustParse parses the string value and returns a name if the value
@@ -68,6 +70,16 @@ func (n Null) String() string {
 	return n.value
 }
 
+// Key returns a case folded form of the name for comparing and searching
+// names. The key of a null name is empty.
+func (n Null) Key() string {
+	if !n.valid {
+		return ""
+	}
+
+	return foldKey(n.value)
+}
+
 // Valid tests if the value is null.
 func (n Null) Valid() bool {
 	return n.valid
@@ -86,17 +98,18 @@ func (n Null) MarshalText() ([]byte, error) {
 // =============================================================================
 
 // ParseNull parses the string value and returns a name if the value complies
-// with the rules for a name.
+// with the default rules for a name.
 func ParseNull(value string) (Null, error) {
 	if value == "" {
 		return Null{}, nil
 	}
 
-	if !nameRegEx.MatchString(value) {
-		return Null{}, fmt.Errorf("invalid name %q", value)
+	name, err := Parse(value)
+	if err != nil {
+		return Null{}, err
 	}
 
-	return Null{value, true}, nil
+	return Null{name.value, true}, nil
 }
 
 // MustParseNull parses the string value and returns a name if the value
@@ -109,3 +122,16 @@ func MustParseNull(value string) Null {
 
 	return name
 }
+
+// =============================================================================
+
+// Fold returns the key of a value that doesn't have to be a valid name, such
+// as part of a name being searched for, so it can be matched against the
+// keys of stored names.
+func Fold(value string) string {
+	return foldKey(value)
+}
+
+func foldKey(value string) string {
+	return cases.Fold().String(norm.NFKC.String(value))
+}
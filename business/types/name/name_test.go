@@ -0,0 +1,141 @@
+package name_test
+
+import (
+	"strings"
+	"testing"
+
+	"service/business/types/name"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		exp   string
+		ok    bool
+	}{
+		{name: "ascii", value: "Bill Kennedy", exp: "Bill Kennedy", ok: true},
+		{name: "apostrophe", value: "O'Brien", exp: "O'Brien", ok: true},
+		{name: "accents", value: "José Müller", exp: "José Müller", ok: true},
+		{name: "nfc", value: "Jose\u0301", exp: "Jos\u00e9", ok: true},
+		{name: "han", value: "王小明", exp: "王小明", ok: true},
+		{name: "arabic", value: "محمد علي", exp: "محمد علي", ok: true},
+		{name: "hangul", value: "김민준", exp: "김민준", ok: true},
+		{name: "japanese", value: "山田 たろう Taro", exp: "山田 たろう Taro", ok: true},
+		{name: "long", value: strings.Repeat("a", 100), exp: strings.Repeat("a", 100), ok: true},
+		{name: "short", value: "a"},
+		{name: "toolong", value: strings.Repeat("a", 101)},
+		{name: "cyrillic-a", value: "P\u0430ypal"},
+		{name: "single-script-confusable", value: "\u0421\u043e\u0441\u043e\u0430", exp: "\u0421\u043e\u0441\u043e\u0430", ok: true},
+		{name: "greek-latin", value: "\u0391lex Smith"},
+		{name: "hangul-hiragana", value: "김たろう"},
+		{name: "symbol", value: "Bill; DROP"},
+		{name: "emoji", value: "Bill 🙂"},
+		{name: "invalid-utf8", value: "Bi\xffll"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := name.Parse(tt.value)
+
+			switch {
+			case tt.ok && err != nil:
+				t.Fatalf("expected %q to parse: %s", tt.value, err)
+			case !tt.ok && err == nil:
+				t.Fatalf("expected %q to be rejected, got %q", tt.value, got)
+			case tt.ok && got.String() != tt.exp:
+				t.Fatalf("got %q, exp %q", got, tt.exp)
+			}
+		})
+	}
+}
+
+// Test_ParseLegacy checks every name accepted by the original rule,
+// ^[a-zA-Z0-9' -]{3,20}$, still parses and is stored unchanged.
+func Test_ParseLegacy(t *testing.T) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789' -"
+
+	var values []string
+	for _, c := range alphabet {
+		values = append(values, strings.Repeat(string(c), 3), "Ab"+string(c), string(c)+"xY", strings.Repeat(string(c), 20))
+	}
+	values = append(values, "Name0", "Name99", "Erased User", "-'-", "   ")
+
+	for _, value := range values {
+		got, err := name.Parse(value)
+		if err != nil {
+			t.Fatalf("expected legacy name %q to parse: %s", value, err)
+		}
+
+		if got.String() != value {
+			t.Fatalf("got %q, exp the legacy name %q unchanged", got, value)
+		}
+	}
+}
+
+func Test_Rules(t *testing.T) {
+	rules := name.Rules{MinLength: 3, MaxLength: 5}
+
+	for _, value := range []string{"abc", "abcde", "éüàöß"} {
+		if _, err := rules.Parse(value); err != nil {
+			t.Fatalf("expected %q to parse: %s", value, err)
+		}
+	}
+
+	for _, value := range []string{"ab", "abcdef"} {
+		if _, err := rules.Parse(value); err == nil {
+			t.Fatalf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func Test_Key(t *testing.T) {
+	tests := []struct {
+		value string
+		exp   string
+	}{
+		{value: "Bill Kennedy", exp: "bill kennedy"},
+		{value: "JOSÉ MÜLLER", exp: "josé müller"},
+		{value: "Strauß", exp: "strauss"},
+		{value: "Ｂｉｌｌ", exp: "bill"},
+		{value: "王小明", exp: "王小明"},
+	}
+
+	for _, tt := range tests {
+		n, err := name.Parse(tt.value)
+		if err != nil {
+			t.Fatalf("expected %q to parse: %s", tt.value, err)
+		}
+
+		if got := n.Key(); got != tt.exp {
+			t.Fatalf("got key %q for %q, exp %q", got, tt.value, tt.exp)
+		}
+
+		if got := name.Fold(tt.value); got != tt.exp {
+			t.Fatalf("got fold %q for %q, exp %q", got, tt.value, tt.exp)
+		}
+	}
+
+	if key := name.MustParse("Сосоа").Key(); key == name.MustParse("Cocoa").Key() {
+		t.Fatalf("expected look-alikes from different scripts to keep different keys, got %q", key)
+	}
+
+	if key := (name.Null{}).Key(); key != "" {
+		t.Fatalf("expected the key of a null name to be empty, got %q", key)
+	}
+}
+
+func Test_ParseNull(t *testing.T) {
+	n, err := name.ParseNull("")
+	if err != nil {
+		t.Fatalf("expected an empty name to parse: %s", err)
+	}
+
+	if n.Valid() {
+		t.Fatalf("expected an empty name to be null")
+	}
+
+	if _, err := name.ParseNull("P\u0430ypal"); err == nil {
+		t.Fatalf("expected a mixed script name to be rejected")
+	}
+}
@@ -0,0 +1,127 @@
+package name
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultRules returns the rules applied by Parse. The bounds are wide
+// enough for names written in any script and still accept every name that
+// passed the original ASCII only rules, so stored names always parse.
+func DefaultRules() Rules {
+	return Rules{
+		MinLength: 2,
+		MaxLength: 100,
+	}
+}
+
+// Rules define what is accepted as a name. Lengths are counted in
+// characters after normalization, not bytes.
+type Rules struct {
+	MinLength int
+	MaxLength int
+}
+
+// Parse parses the string value and returns a name if the value complies
+// with the rules. The value is normalized to NFC so the same name typed on
+// different systems is stored the same way.
+//
+// A name may contain letters and combining marks from any script, decimal
+// digits, spaces, hyphens and apostrophes, which keeps every name accepted
+// by the original ASCII rules valid. To guard against names spoofing
+// other names with look-alike characters, the letters must come from a
+// single script or one of the combinations used to write Chinese, Japanese
+// and Korean, following the highly restrictive level of Unicode TS #39.
+//
+// No confusable skeleton check is made, so a name written entirely in one
+// script that looks like a name in another, such as an all Cyrillic
+// "Сосоа" for "Cocoa", is accepted. Only look-alikes mixed from several
+// scripts in one name are rejected.
+func (r Rules) Parse(value string) (Name, error) {
+	if !utf8.ValidString(value) {
+		return Name{}, fmt.Errorf("invalid name %q: not valid utf-8", value)
+	}
+
+	value = norm.NFC.String(value)
+
+	n := utf8.RuneCountInString(value)
+	if n < r.MinLength || (r.MaxLength > 0 && n > r.MaxLength) {
+		return Name{}, fmt.Errorf("invalid name %q: length must be between %d and %d", value, r.MinLength, r.MaxLength)
+	}
+
+	scripts := make(map[string]bool)
+
+	for _, c := range value {
+		switch {
+		case unicode.IsLetter(c):
+			scripts[scriptOf(c)] = true
+
+		case unicode.IsMark(c), unicode.Is(unicode.Nd, c):
+			// Marks inherit the script of the letter they combine with
+			// and digits are shared across scripts.
+
+		case c == ' ', c == '-', c == '\'', c == '’':
+
+		default:
+			return Name{}, fmt.Errorf("invalid name %q: character %q not allowed", value, c)
+		}
+	}
+
+	delete(scripts, "Common")
+	delete(scripts, "Inherited")
+
+	if !allowedScripts(scripts) {
+		return Name{}, fmt.Errorf("invalid name %q: mixes characters from different scripts", value)
+	}
+
+	return Name{value}, nil
+}
+
+// =============================================================================
+
+// scriptSets are the combinations of scripts that can be mixed in one name.
+var scriptSets = []map[string]bool{
+	{"Latin": true, "Han": true, "Hiragana": true, "Katakana": true},
+	{"Latin": true, "Han": true, "Bopomofo": true},
+	{"Latin": true, "Han": true, "Hangul": true},
+}
+
+func allowedScripts(scripts map[string]bool) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+
+next:
+	for _, set := range scriptSets {
+		for script := range scripts {
+			if !set[script] {
+				continue next
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// commonScripts are checked first since most names are written in them.
+var commonScripts = []string{"Latin", "Cyrillic", "Greek", "Han", "Arabic", "Hebrew", "Hiragana", "Katakana", "Hangul", "Devanagari"}
+
+func scriptOf(c rune) string {
+	for _, script := range commonScripts {
+		if unicode.Is(unicode.Scripts[script], c) {
+			return script
+		}
+	}
+
+	for script, table := range unicode.Scripts {
+		if unicode.Is(table, c) {
+			return script
+		}
+	}
+
+	return "Unknown"
+}
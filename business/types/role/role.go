@@ -1,6 +1,9 @@
 package role
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+)
 
 // The set of roles that can be used.
 var (
@@ -10,9 +13,11 @@ var (
 
 // =============================================================================
 
-// Set of known roles.
+// Set of built-in roles.
 var roles = make(map[string]Role)
 
+// Role represents a role a user can be assigned. Besides the built-in roles,
+// roles can be defined at runtime and are stored with the role domain.
 type Role struct {
 	value string
 }
@@ -28,6 +33,12 @@ func (r Role) String() string {
 	return r.value
 }
 
+// BuiltIn reports whether the role is one of the roles defined by the system.
+func (r Role) BuiltIn() bool {
+	_, exists := roles[r.value]
+	return exists
+}
+
 // Equal provides support for the go-cmp package and testing.
 func (r Role) Equal(r2 Role) bool {
 	return r.value == r2.value
@@ -40,17 +51,25 @@ func (r Role) MarshalText() ([]byte, error) {
 
 // =============================================================================
 
-// Parse parses the string value and returns a role if one exists.
+var roleRegEx = regexp.MustCompile("^[A-Z][A-Z0-9_]{1,31}$")
+
+// Parse parses the string value and returns a role if it is a built-in role
+// or a well formed name for a role defined at runtime. Whether a runtime role
+// exists is checked by the role domain.
 func Parse(value string) (Role, error) {
-	role, exists := roles[value]
-	if !exists {
+	if role, exists := roles[value]; exists {
+		return role, nil
+	}
+
+	if !roleRegEx.MatchString(value) {
 		return Role{}, fmt.Errorf("invalid role %q", value)
 	}
-	return role, nil
+
+	return Role{value}, nil
 }
 
-// MustParse parses the string value and returns a role if one exists. If
-// an error occurs the function panics.
+// MustParse parses the string value and returns a role. If an error occurs
+// the function panics.
 func MustParse(value string) Role {
 	role, err := Parse(value)
 	if err != nil {
@@ -27,6 +27,10 @@ type httpStatus interface {
 	HTTPStatus() int
 }
 
+type httpHeader interface {
+	HTTPHeader() http.Header
+}
+
 // Respond sends a response to the client.
 func Respond(ctx context.Context, w http.ResponseWriter, resp Encoder) error {
 	if _, ok := resp.(NoResponse); ok {
@@ -67,6 +71,14 @@ func Respond(ctx context.Context, w http.ResponseWriter, resp Encoder) error {
 		return fmt.Errorf("respond: encode: %w", err)
 	}
 
+	if v, ok := resp.(httpHeader); ok {
+		for key, values := range v.HTTPHeader() {
+			for _, value := range values {
+				w.Header().Add(key, value)
+			}
+		}
+	}
+
 	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(statusCode)
 